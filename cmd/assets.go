@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kofifort/obsidian-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var assetsCmd = &cobra.Command{
+	Use:   "assets",
+	Short: "Manage attachments and other vault files",
+	Long: `Commands for the files in the vault that aren't notes: images, PDFs,
+media and other attachments.`,
+}
+
+func init() {
+	rootCmd.AddCommand(assetsCmd)
+}
+
+// assetLink is a wikilink or markdown link in a note that may point to a
+// vault file.
+type assetLink struct {
+	Wikilink *wikilink          // Set for wikilinks and embeds
+	Markdown *markdownLinkMatch // Set for markdown links and images
+	Text     string             // The link as written
+	Target   string             // Decoded path as written, without fragment
+	Embed    bool
+	Line     int
+}
+
+// withTarget renders the link with a new target, keeping everything else.
+func (l assetLink) withTarget(target string) string {
+	if l.Wikilink != nil {
+		w := *l.Wikilink
+		w.Target = target
+		return w.String()
+	}
+	ml := l.Markdown
+	dest := encodeLinkPath(target)
+	if ml.Fragment != "" {
+		dest += "#" + encodeLinkPath(strings.TrimPrefix(ml.Fragment, "#"))
+	}
+	prefix := ""
+	if ml.Embed {
+		prefix = "!"
+	}
+	return prefix + "[" + ml.Label + "](" + dest + ml.Title + ")"
+}
+
+// rewriteAssetLinks calls fn for every wikilink and internal markdown link
+// in the body of a note, outside code, and substitutes the returned text
+// when fn reports a change. Returns the new content and the number of
+// links replaced.
+func rewriteAssetLinks(note *noteFile, fn func(l assetLink) (string, bool)) (string, int) {
+	count := 0
+	body := mapOutsideFences(note.Body, note.BodyLine, func(chunk string, line int) string {
+		return mapOutsideInlineCode(chunk, line, func(piece string, line int) string {
+			piece, n := rewriteWikilinks(piece, func(w wikilink) (string, bool) {
+				if w.Target == "" {
+					return "", false
+				}
+				return fn(assetLink{Wikilink: &w, Text: w.String(), Target: w.Target, Embed: w.Embed, Line: line + lineNumberAt(piece, w.Start) - 1})
+			})
+			count += n
+
+			var b strings.Builder
+			last := 0
+			for _, m := range markdownLinkRegex.FindAllStringSubmatchIndex(piece, -1) {
+				ml := parseMarkdownLink(piece, m)
+				if ml.Target == "" || vault.IsExternalLink(ml.Dest) || strings.Contains(ml.Dest, ":") {
+					continue
+				}
+				replacement, changed := fn(assetLink{Markdown: &ml, Text: piece[m[0]:m[1]], Target: ml.Target, Embed: ml.Embed, Line: line + lineNumberAt(piece, m[0]) - 1})
+				if !changed {
+					continue
+				}
+				b.WriteString(piece[last:m[0]])
+				b.WriteString(replacement)
+				last = m[1]
+				count++
+			}
+			b.WriteString(piece[last:])
+			return b.String()
+		})
+	})
+	return note.Content[:len(note.Content)-len(note.Body)] + body, count
+}
+
+// resolveAssetLink resolves a link in note src to a vault file the way
+// Obsidian does: wikilinks by path or name, markdown links relative to the
+// note, then from the vault root.
+func resolveAssetLink(assets *noteIndex, src string, l assetLink) (string, bool) {
+	if l.Wikilink != nil {
+		return assets.resolve(l.Target)
+	}
+	for _, cand := range []string{path.Join(path.Dir(src), l.Target), strings.TrimPrefix(l.Target, "/")} {
+		if a, ok := assets.byPath[strings.ToLower(path.Clean(cand))]; ok {
+			return a, true
+		}
+	}
+	return "", false
+}
+
+// assetLinkTarget returns the target to write in a link from note src to
+// vault file asset, in the style of the link it replaces: wikilinks and
+// bare markdown names use the shortest unambiguous form, other markdown
+// links a path relative to the note when they were, else from the root.
+// before and after index the vault files before and after the change.
+func assetLinkTarget(before, after *noteIndex, src, asset string, l assetLink) string {
+	short := asset
+	if !after.ambiguous(pathBase(asset)) {
+		short = pathBase(asset)
+	}
+	switch {
+	case l.Wikilink != nil, !strings.Contains(l.Target, "/"):
+		return short
+	case strings.HasPrefix(l.Target, "/"):
+		return "/" + asset
+	}
+	if _, ok := before.byPath[strings.ToLower(path.Clean(path.Join(path.Dir(src), l.Target)))]; ok {
+		return relativeLinkPath(src, asset)
+	}
+	return asset
+}
+
+// loadAssetIndex indexes the vault's files other than notes by path
+// (with extension) and name.
+func loadAssetIndex(absPath string) ([]string, *noteIndex, error) {
+	assets, err := collectAssetFiles(absPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, a := range assets {
+		assets[i] = filepath.ToSlash(a)
+	}
+	return assets, newNoteIndex(assets), nil
+}
@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	assetDupDedupe bool
+	assetDupDryRun bool
+	assetDupFolder string
+	assetDupFormat string
+	assetDupYes    bool
+)
+
+var assetsDuplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Find files saved more than once under different names",
+	Long: `Finds vault files with identical content: files of the same size are
+compared by SHA-256. Each group shows the bytes wasted by the extra copies.
+
+--dedupe keeps one copy of each group, the most referenced (then the
+shallowest path), rewrites every embed, wikilink and markdown link to the
+other copies to point to it, and removes them after confirmation (skip it
+with --yes). Changes are journaled (revert with "undo").
+
+Examples:
+  obsidian-cli assets duplicates --vault ~/notes
+  obsidian-cli assets duplicates --vault ~/notes --dedupe --dry-run
+  obsidian-cli assets duplicates --vault ~/notes --dedupe --yes
+  obsidian-cli assets duplicates --vault ~/notes --folder Attachments --format json`,
+	Args: cobra.NoArgs,
+	RunE: runAssetsDuplicates,
+}
+
+func init() {
+	assetsCmd.AddCommand(assetsDuplicatesCmd)
+	assetsDuplicatesCmd.Flags().BoolVar(&assetDupDedupe, "dedupe", false, "Keep one copy of each group and retarget references to it")
+	assetsDuplicatesCmd.Flags().BoolVar(&assetDupDryRun, "dry-run", false, "Preview --dedupe without modifying files")
+	assetsDuplicatesCmd.Flags().StringVarP(&assetDupFolder, "folder", "f", "", "Only compare files in this folder")
+	assetsDuplicatesCmd.Flags().StringVar(&assetDupFormat, "format", "text", "Output format: text, json")
+	assetsDuplicatesCmd.Flags().BoolVarP(&assetDupYes, "yes", "y", false, "With --dedupe, don't ask for confirmation")
+}
+
+// AssetCopy is a file in a group of duplicates.
+type AssetCopy struct {
+	Path       string `json:"path"`
+	References int    `json:"references"`
+}
+
+// AssetDuplicateGroup is a set of files with identical content. The
+// first file is the canonical copy.
+type AssetDuplicateGroup struct {
+	Hash        string      `json:"sha256"`
+	Size        int64       `json:"size"`
+	Wasted      int64       `json:"wasted_bytes"`
+	WastedHuman string      `json:"wasted_human"`
+	Files       []AssetCopy `json:"files"`
+}
+
+// AssetDuplicatesResult is the result of assets duplicates.
+type AssetDuplicatesResult struct {
+	TotalAssets  int                   `json:"total_assets"`
+	Groups       []AssetDuplicateGroup `json:"groups"`
+	Wasted       int64                 `json:"wasted_bytes"`
+	WastedHuman  string                `json:"wasted_human"`
+	Deduped      bool                  `json:"deduped"`
+	LinksUpdated int                   `json:"links_updated,omitempty"`
+	FilesRemoved int                   `json:"files_removed,omitempty"`
+	Executed     bool                  `json:"executed"`
+	JournalID    string                `json:"journal_id,omitempty"`
+}
+
+func runAssetsDuplicates(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	start := time.Now()
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	if assetDupDedupe && !assetDupDryRun && assetDupFormat == "json" && !assetDupYes {
+		return fmt.Errorf("--dedupe with --format json needs --yes or --dry-run")
+	}
+	if assetDupFormat == "text" {
+		printScanHeader("Hashing assets")
+	}
+
+	assets, ix, err := loadAssetIndex(absPath)
+	if err != nil {
+		return err
+	}
+	var candidates []string
+	for _, a := range assets {
+		if folderMatches(a, assetDupFolder) {
+			candidates = append(candidates, a)
+		}
+	}
+	groups, err := findDuplicateAssets(absPath, candidates)
+	if err != nil {
+		return err
+	}
+
+	notes, err := loadNotes(absPath)
+	if err != nil {
+		return err
+	}
+	refs := make(map[string]int)
+	for _, n := range notes {
+		src := filepath.ToSlash(n.RelPath)
+		rewriteAssetLinks(n, func(l assetLink) (string, bool) {
+			if a, ok := resolveAssetLink(ix, src, l); ok {
+				refs[a]++
+			}
+			return "", false
+		})
+	}
+
+	result := &AssetDuplicatesResult{TotalAssets: len(assets), Groups: []AssetDuplicateGroup{}, Deduped: assetDupDedupe, Executed: assetDupDedupe && !assetDupDryRun}
+	for _, g := range groups {
+		files := g.files
+		sort.Slice(files, func(i, j int) bool {
+			if refs[files[i]] != refs[files[j]] {
+				return refs[files[i]] > refs[files[j]]
+			}
+			if di, dj := strings.Count(files[i], "/"), strings.Count(files[j], "/"); di != dj {
+				return di < dj
+			}
+			return files[i] < files[j]
+		})
+		group := AssetDuplicateGroup{Hash: g.hash, Size: g.size, Wasted: g.size * int64(len(files)-1)}
+		group.WastedHuman = humanizeBytes(group.Wasted)
+		for _, f := range files {
+			group.Files = append(group.Files, AssetCopy{Path: f, References: refs[f]})
+		}
+		result.Groups = append(result.Groups, group)
+		result.Wasted += group.Wasted
+	}
+	sort.SliceStable(result.Groups, func(i, j int) bool { return result.Groups[i].Wasted > result.Groups[j].Wasted })
+	result.WastedHuman = humanizeBytes(result.Wasted)
+
+	var changed map[string]string
+	var removed []string
+	if assetDupDedupe {
+		changed, removed, result.LinksUpdated = planAssetDedupe(notes, assets, ix, result.Groups)
+		result.FilesRemoved = len(removed)
+	}
+
+	if assetDupFormat == "json" {
+		if result.Executed {
+			if result.JournalID, err = writeAssetDedupe(absPath, changed, removed); err != nil {
+				return err
+			}
+		}
+		return encodeJSON(cmd, result)
+	}
+
+	printAssetDuplicates(result)
+	printScanFooter(time.Since(start))
+	if !assetDupDedupe || len(removed) == 0 {
+		return nil
+	}
+	if assetDupDryRun {
+		fmt.Printf("\n  %s Run without --dry-run to remove %d copies and update %d links in %d notes\n\n", colors.Yellow("!"), len(removed), result.LinksUpdated, len(changed))
+		return nil
+	}
+	if !assetDupYes {
+		fmt.Println()
+		ok, err := confirmPrompt(fmt.Sprintf("Remove %d copies (%s) and update %d links in %d notes?", len(removed), result.WastedHuman, result.LinksUpdated, len(changed)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("\n  %s Cancelled. No files changed.\n\n", colors.Yellow("!"))
+			return nil
+		}
+	}
+	journalID, err := writeAssetDedupe(absPath, changed, removed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n  %s Removed %d copies (%s), updated %d links in %d notes\n", colors.Green("✓"), len(removed), result.WastedHuman, result.LinksUpdated, len(changed))
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n\n", colors.Dim("i"), journalID)
+	return nil
+}
+
+// assetHashGroup is a set of files with the same content.
+type assetHashGroup struct {
+	hash  string
+	size  int64
+	files []string
+}
+
+// findDuplicateAssets groups vault files (slash-separated relative paths)
+// by content. Only files sharing a size are hashed; empty files are
+// ignored.
+func findDuplicateAssets(absPath string, assets []string) ([]assetHashGroup, error) {
+	bySize := make(map[int64][]string)
+	for _, a := range assets {
+		info, err := os.Lstat(filepath.Join(absPath, filepath.FromSlash(a)))
+		if err != nil || !info.Mode().IsRegular() || info.Size() == 0 {
+			continue
+		}
+		bySize[info.Size()] = append(bySize[info.Size()], a)
+	}
+
+	var sizes []int64
+	for size, same := range bySize {
+		if len(same) > 1 {
+			sizes = append(sizes, size)
+		}
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] > sizes[j] })
+
+	var groups []assetHashGroup
+	for _, size := range sizes {
+		same := bySize[size]
+		byHash := make(map[string][]string)
+		for _, a := range same {
+			hash, err := hashFile(filepath.Join(absPath, filepath.FromSlash(a)))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", a, err)
+			}
+			byHash[hash] = append(byHash[hash], a)
+		}
+		for _, hash := range sortedKeys(byHash) {
+			if files := byHash[hash]; len(files) > 1 {
+				groups = append(groups, assetHashGroup{hash: hash, size: size, files: files})
+			}
+		}
+	}
+	return groups, nil
+}
+
+// hashFile returns the hex SHA-256 of a file.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// planAssetDedupe rewrites the links to the extra copies of each group to
+// the canonical copy. Returns the new content of changed notes, the
+// copies to remove and the number of links rewritten.
+func planAssetDedupe(notes []*noteFile, assets []string, ix *noteIndex, groups []AssetDuplicateGroup) (changed map[string]string, removed []string, links int) {
+	canonical := make(map[string]string) // Copy -> canonical file
+	for _, g := range groups {
+		for _, f := range g.Files[1:] {
+			canonical[f.Path] = g.Files[0].Path
+		}
+	}
+	var remaining []string
+	for _, a := range assets {
+		if _, ok := canonical[a]; !ok {
+			remaining = append(remaining, a)
+		}
+	}
+	after := newNoteIndex(remaining)
+
+	changed = make(map[string]string)
+	for _, n := range notes {
+		src := filepath.ToSlash(n.RelPath)
+		content, count := rewriteAssetLinks(n, func(l assetLink) (string, bool) {
+			a, ok := resolveAssetLink(ix, src, l)
+			if !ok {
+				return "", false
+			}
+			to, ok := canonical[a]
+			if !ok {
+				return "", false
+			}
+			return l.withTarget(assetLinkTarget(ix, after, src, to, l)), true
+		})
+		if count > 0 {
+			changed[n.RelPath] = content
+			links += count
+		}
+	}
+	return changed, sortedKeys(canonical), links
+}
+
+// writeAssetDedupe writes the retargeted notes and removes the copies
+// through a journal. Returns the journal ID.
+func writeAssetDedupe(absPath string, changed map[string]string, removed []string) (journalID string, err error) {
+	if len(removed) == 0 {
+		return "", nil
+	}
+	j := newJournal(absPath, "dedupe", fmt.Sprintf("remove %d duplicate files", len(removed)))
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	for _, file := range sortedKeys(changed) {
+		if err := j.writeFile(file, []byte(changed[file]), 0644); err != nil {
+			return j.ID, err
+		}
+	}
+	for _, file := range removed {
+		if err := j.remove(filepath.FromSlash(file)); err != nil {
+			return j.ID, err
+		}
+	}
+	return j.ID, nil
+}
+
+func printAssetDuplicates(result *AssetDuplicatesResult) {
+	if len(result.Groups) == 0 {
+		fmt.Printf("%s No duplicate files among %d assets\n\n", colors.Green("✓"), result.TotalAssets)
+		return
+	}
+	title := "Duplicate Assets"
+	if result.Deduped && !result.Executed {
+		title += " (dry run)"
+	}
+	fmt.Printf("%s %s %s\n\n", colors.Yellow("!"), title,
+		colors.Dim(fmt.Sprintf("(%d groups, %s wasted)", len(result.Groups), result.WastedHuman)))
+	for _, g := range result.Groups {
+		fmt.Printf("  %s %s\n", colors.Cyan(g.Hash[:12]), colors.Dim(fmt.Sprintf("%d copies of %s, %s wasted", len(g.Files), humanizeBytes(g.Size), g.WastedHuman)))
+		for i, f := range g.Files {
+			marker, refs := " ", colors.Dim(fmt.Sprintf("(%d references)", f.References))
+			if i == 0 {
+				marker = colors.Green("*")
+			} else if result.Deduped {
+				marker = colors.Red("-")
+			}
+			fmt.Printf("    %s %s %s\n", marker, f.Path, refs)
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	assetPurgeOlderThan string
+	assetPurgeYes       bool
+	assetPurgeDryRun    bool
+	assetPurgeFormat    string
+)
+
+var assetsPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently delete trashed files",
+	Long: `Permanently deletes the files of trash batches older than --older-than,
+along with their manifests. This can't be undone.
+
+Ages are given in days (30d), weeks (2w) or as durations (12h);
+--older-than 0 empties the whole trash.
+
+Examples:
+  obsidian-cli assets purge --vault ~/notes --dry-run
+  obsidian-cli assets purge --vault ~/notes --older-than 30d
+  obsidian-cli assets purge --vault ~/notes --older-than 0 --yes`,
+	Args: cobra.NoArgs,
+	RunE: runAssetsPurge,
+}
+
+func init() {
+	assetsCmd.AddCommand(assetsPurgeCmd)
+	assetsPurgeCmd.Flags().StringVar(&assetPurgeOlderThan, "older-than", "30d", "Only purge batches trashed longer ago than this")
+	assetsPurgeCmd.Flags().BoolVarP(&assetPurgeYes, "yes", "y", false, "Don't ask for confirmation")
+	assetsPurgeCmd.Flags().BoolVar(&assetPurgeDryRun, "dry-run", false, "Show what would be purged")
+	assetsPurgeCmd.Flags().StringVar(&assetPurgeFormat, "format", "text", "Output format: text, json")
+}
+
+// AssetPurgeResult is the result of assets purge.
+type AssetPurgeResult struct {
+	Batches    []*TrashBatch `json:"batches"`
+	Files      int           `json:"files"`
+	Bytes      int64         `json:"bytes"`
+	BytesHuman string        `json:"bytes_human"`
+	Executed   bool          `json:"executed"`
+}
+
+func runAssetsPurge(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	age, err := parseAge(assetPurgeOlderThan)
+	if err != nil {
+		return err
+	}
+	if assetPurgeFormat != "text" && !assetPurgeYes && !assetPurgeDryRun {
+		return fmt.Errorf("--format %s needs --yes or --dry-run", assetPurgeFormat)
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+
+	batches, err := loadTrashBatches(absPath)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-age)
+	result := &AssetPurgeResult{Batches: []*TrashBatch{}}
+	for _, b := range batches {
+		if !b.TrashedAt.After(cutoff) {
+			result.Batches = append(result.Batches, b)
+			for _, f := range b.pending() {
+				result.Files++
+				result.Bytes += f.Size
+			}
+		}
+	}
+	result.BytesHuman = humanizeBytes(result.Bytes)
+
+	if assetPurgeFormat == "text" {
+		if len(result.Batches) == 0 {
+			fmt.Printf("\n  %s No trash batches older than %s\n\n", colors.Green("✓"), assetPurgeOlderThan)
+			return nil
+		}
+		printTrashBatches(result.Batches)
+		if assetPurgeDryRun {
+			fmt.Printf("  %s Run without --dry-run to delete %d files (%s)\n\n", colors.Yellow("!"), result.Files, result.BytesHuman)
+			return nil
+		}
+		if !assetPurgeYes {
+			ok, err := confirmPrompt(fmt.Sprintf("Permanently delete %d files (%s)?", result.Files, result.BytesHuman))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Printf("\n  %s Cancelled. Nothing purged.\n\n", colors.Yellow("!"))
+				return nil
+			}
+			fmt.Println()
+		}
+	}
+
+	if !assetPurgeDryRun {
+		for _, b := range result.Batches {
+			if _, err := b.purge(); err != nil {
+				return err
+			}
+		}
+		result.Executed = true
+	}
+	if assetPurgeFormat == "json" {
+		return encodeJSON(cmd, result)
+	}
+	fmt.Printf("  %s Purged %d batches, freed %s\n\n", colors.Green("✓"), len(result.Batches), result.BytesHuman)
+	return nil
+}
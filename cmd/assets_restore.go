@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	assetRestoreList   bool
+	assetRestoreFormat string
+)
+
+var assetsRestoreCmd = &cobra.Command{
+	Use:   "restore [batch-id]",
+	Short: "Put trashed files back where they were",
+	Long: `Moves files trashed with "unused-assets --trash" back to their original
+paths. Without an ID, the most recent batch is restored.
+
+Files whose original path is taken again are left in the trash and
+reported; run restore again once the path is free.
+
+Examples:
+  obsidian-cli assets restore --vault ~/notes --list
+  obsidian-cli assets restore --vault ~/notes
+  obsidian-cli assets restore 20250115-103000-ab12 --vault ~/notes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAssetsRestore,
+}
+
+func init() {
+	assetsCmd.AddCommand(assetsRestoreCmd)
+	assetsRestoreCmd.Flags().BoolVar(&assetRestoreList, "list", false, "List trashed batches")
+	assetsRestoreCmd.Flags().StringVar(&assetRestoreFormat, "format", "text", "Output format: text, json")
+}
+
+// AssetRestoreResult is the result of assets restore.
+type AssetRestoreResult struct {
+	Batch     *TrashBatch    `json:"batch"`
+	Restored  int            `json:"restored"`
+	Conflicts []TrashFailure `json:"conflicts"`
+}
+
+func runAssetsRestore(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+
+	batches, err := loadTrashBatches(absPath)
+	if err != nil {
+		return err
+	}
+	if assetRestoreList {
+		if assetRestoreFormat == "json" {
+			if batches == nil {
+				batches = []*TrashBatch{}
+			}
+			return encodeJSON(cmd, batches)
+		}
+		printTrashBatches(batches)
+		return nil
+	}
+
+	var target *TrashBatch
+	for _, b := range batches {
+		if len(args) == 0 || b.ID == args[0] {
+			target = b
+			break
+		}
+	}
+	if target == nil {
+		if len(args) == 1 {
+			return fmt.Errorf("trash batch not found: %s", args[0])
+		}
+		return fmt.Errorf("nothing to restore")
+	}
+
+	restored, conflicts, err := target.restore()
+	if err != nil {
+		return err
+	}
+	if assetRestoreFormat == "json" {
+		if conflicts == nil {
+			conflicts = []TrashFailure{}
+		}
+		return encodeJSON(cmd, &AssetRestoreResult{Batch: target, Restored: restored, Conflicts: conflicts})
+	}
+
+	fmt.Printf("\n  %s Restored %d files from %s\n", colors.Green("✓"), restored, target.ID)
+	for _, c := range conflicts {
+		fmt.Printf("  %s %s: %s\n", colors.Yellow("!"), c.Path, c.Error)
+	}
+	fmt.Println()
+	return nil
+}
+
+func printTrashBatches(batches []*TrashBatch) {
+	fmt.Printf("\n%s Trash %s\n\n", colors.Cyan("=>"), colors.Dim(fmt.Sprintf("(%d batches)", len(batches))))
+	if len(batches) == 0 {
+		fmt.Println("  Nothing in the trash.")
+		return
+	}
+	for _, b := range batches {
+		var size int64
+		pending := b.pending()
+		for _, f := range pending {
+			size += f.Size
+		}
+		fmt.Printf("  %s %s %s\n", colors.Cyan(b.ID), b.Location,
+			colors.Dim(fmt.Sprintf("(%d files, %s, %s)", len(pending), humanizeBytes(size), b.TrashedAt.Local().Format("2006-01-02 15:04"))))
+		if b.Reason != "" {
+			fmt.Printf("    %s\n", b.Reason)
+		}
+	}
+	fmt.Println()
+}
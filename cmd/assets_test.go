@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestPlanAssetDedupe tests that wikilinks and markdown links to copies
+// are retargeted in their own style
+func TestPlanAssetDedupe(t *testing.T) {
+	dir := t.TempDir()
+	assets := []string{"att/a.png", "att/b.png", "N/img/c.png"}
+	note := parseNote(dir, filepath.Join(dir, "N", "n.md"), "![[b.png|300]] ![x](img/c.png) ![y](/att/b.png) [[a.png]]\n")
+	groups := []AssetDuplicateGroup{{Files: []AssetCopy{{Path: "att/a.png"}, {Path: "att/b.png"}, {Path: "N/img/c.png"}}}}
+
+	changed, removed, links := planAssetDedupe([]*noteFile{note}, assets, newNoteIndex(assets), groups)
+	if want := "![[a.png|300]] ![x](../att/a.png) ![y](/att/a.png) [[a.png]]\n"; changed[note.RelPath] != want {
+		t.Errorf("content = %q, want %q", changed[note.RelPath], want)
+	}
+	if len(removed) != 2 || links != 3 {
+		t.Errorf("removed = %v, links = %d; want 2 files and 3 links", removed, links)
+	}
+}
+
+// TestTrashAndRestore tests that trashed files keep their relative paths
+// and go back on restore, except where the original path was taken again
+func TestTrashAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"img/a.png", "b.pdf"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, f), []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	batch, failures, err := trashFiles(dir, []string{"img/a.png", "b.pdf", "missing.png"}, trashQuarantine, "test")
+	if err != nil || len(batch.Files) != 2 || len(failures) != 1 {
+		t.Fatalf("trashFiles = %v, %v, %v; want 2 files and 1 failure", batch.Files, failures, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, batch.dir(), "files", "img", "a.png")); err != nil {
+		t.Errorf("trashed file not in quarantine: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "b.pdf"), []byte("new"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	batches, err := loadTrashBatches(dir)
+	if err != nil || len(batches) != 1 {
+		t.Fatalf("loadTrashBatches = %v, %v", batches, err)
+	}
+	restored, conflicts, err := batches[0].restore()
+	if err != nil || restored != 1 || len(conflicts) != 1 || conflicts[0].Path != "b.pdf" {
+		t.Errorf("restore = %d, %v, %v; want 1 restored and a conflict on b.pdf", restored, conflicts, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "img", "a.png")); err != nil {
+		t.Errorf("a.png not restored: %v", err)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := map[string]time.Duration{"30d": 30 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "12h": 12 * time.Hour, "0": 0}
+	for in, want := range tests {
+		if got, err := parseAge(in); err != nil || got != want {
+			t.Errorf("parseAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseAge("soon"); err == nil {
+		t.Error("parseAge(\"soon\") should fail")
+	}
+}
+
+// TestFindMissingAssets tests Obsidian-style resolution of embeds and
+// the fixes suggested for the missing ones
+func TestFindMissingAssets(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{".obsidian/app.json", "N/att/shot.png", "Files/diagram.svg"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, f), []byte(`{"attachmentFolderPath": "./att"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	note := parseNote(dir, filepath.Join(dir, "N", "n.md"), "![](shot.png)\n![[diagram.png]]\n`![[code.png]]`\n![[v1.2]]\n")
+	notes := []*noteFile{note, parseNote(dir, filepath.Join(dir, "v1.2.md"), "")}
+
+	r, err := newAssetResolver(dir, newNoteIndexFromNotes(notes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := r.findMissing([]*noteFile{note})
+	if len(missing) != 1 || missing[0].Line != 2 || missing[0].Target != "diagram.png" {
+		t.Fatalf("missing = %+v, want only diagram.png on line 2", missing)
+	}
+	if sg := missing[0].Suggestions; len(sg) != 1 || sg[0].Fix != "![[diagram.svg]]" {
+		t.Errorf("suggestions = %+v, want ![[diagram.svg]]", sg)
+	}
+}
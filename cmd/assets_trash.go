@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// quarantineDir holds one folder per batch of trashed files, relative to
+// the vault. Each folder has a manifest.json and, for the quarantine
+// location, the files themselves under files/ at their original paths.
+var quarantineDir = filepath.Join(".obsidian-cli", "quarantine")
+
+// obsidianTrashDir is Obsidian's own vault trash.
+const obsidianTrashDir = ".trash"
+
+// Trash locations
+const (
+	trashQuarantine = "quarantine" // .obsidian-cli/quarantine/<id>/files/
+	trashObsidian   = "obsidian"   // .trash/
+)
+
+// TrashEntry is a file moved to the trash.
+type TrashEntry struct {
+	Path      string `json:"path"`       // Original vault-relative path
+	TrashPath string `json:"trash_path"` // Vault-relative path in the trash
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+	Restored  bool   `json:"restored,omitempty"`
+}
+
+// TrashBatch is the manifest of a set of files trashed together.
+type TrashBatch struct {
+	ID        string       `json:"id"`
+	Location  string       `json:"location"`
+	Reason    string       `json:"reason"`
+	TrashedAt time.Time    `json:"trashed_at"`
+	Files     []TrashEntry `json:"files"`
+
+	absPath string
+}
+
+// TrashFailure is a file that couldn't be moved.
+type TrashFailure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// dir returns the vault-relative folder of the batch.
+func (b *TrashBatch) dir() string {
+	return filepath.Join(quarantineDir, b.ID)
+}
+
+// pending returns the files not restored yet.
+func (b *TrashBatch) pending() []TrashEntry {
+	var files []TrashEntry
+	for _, f := range b.Files {
+		if !f.Restored {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// save writes the batch manifest.
+func (b *TrashBatch) save() error {
+	dir := filepath.Join(b.absPath, b.dir())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// trashFiles moves vault-relative files to the trash location, keeping
+// their relative paths, and writes the batch manifest. Files that can't be
+// moved (symlinks, missing, outside the vault) are reported and skipped.
+func trashFiles(absPath string, paths []string, location, reason string) (*TrashBatch, []TrashFailure, error) {
+	now := time.Now()
+	b := &TrashBatch{
+		ID:        fmt.Sprintf("%s-%s", now.Format("20060102-150405"), randomHex(4)),
+		Location:  location,
+		Reason:    reason,
+		TrashedAt: now.UTC(),
+		Files:     []TrashEntry{},
+		absPath:   absPath,
+	}
+
+	var failures []TrashFailure
+	for _, rel := range paths {
+		entry, err := b.moveIn(rel)
+		if err != nil {
+			failures = append(failures, TrashFailure{Path: rel, Error: err.Error()})
+			continue
+		}
+		b.Files = append(b.Files, entry)
+	}
+	if len(b.Files) == 0 {
+		return b, failures, nil
+	}
+	return b, failures, b.save()
+}
+
+// moveIn moves one file into the batch's trash location.
+func (b *TrashBatch) moveIn(rel string) (TrashEntry, error) {
+	from := filepath.Join(b.absPath, rel)
+	if !isPathWithinVault(from, b.absPath) {
+		return TrashEntry{}, fmt.Errorf("path escapes vault boundary")
+	}
+	// Security: the file could have been replaced with a symlink since the scan
+	info, err := os.Lstat(from)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	if !info.Mode().IsRegular() {
+		return TrashEntry{}, fmt.Errorf("not a regular file")
+	}
+	hash, err := hashFile(from)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+
+	trashRel := filepath.Join(b.dir(), "files", rel)
+	if b.Location == trashObsidian {
+		trashRel = availablePath(b.absPath, filepath.Join(obsidianTrashDir, rel))
+	}
+	to := filepath.Join(b.absPath, trashRel)
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return TrashEntry{}, err
+	}
+	if err := os.Rename(from, to); err != nil {
+		return TrashEntry{}, err
+	}
+	return TrashEntry{Path: filepath.ToSlash(rel), TrashPath: filepath.ToSlash(trashRel), Size: info.Size(), SHA256: hash}, nil
+}
+
+// availablePath returns rel, or rel with " 1", " 2"... before the
+// extension if a file already exists there.
+func availablePath(absPath, rel string) string {
+	ext := filepath.Ext(rel)
+	stem := strings.TrimSuffix(rel, ext)
+	candidate := rel
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(filepath.Join(absPath, candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s %d%s", stem, i, ext)
+	}
+}
+
+// restore moves the batch's files back to their original paths. Files
+// whose original path is taken again, or that are gone from the trash,
+// are reported as conflicts and stay in the trash. Once every file is
+// restored the batch folder is removed.
+func (b *TrashBatch) restore() (restored int, conflicts []TrashFailure, err error) {
+	for i, f := range b.Files {
+		if f.Restored {
+			continue
+		}
+		from := filepath.Join(b.absPath, filepath.FromSlash(f.TrashPath))
+		to := filepath.Join(b.absPath, filepath.FromSlash(f.Path))
+		if !isPathWithinVault(from, b.absPath) || !isPathWithinVault(to, b.absPath) {
+			return restored, conflicts, fmt.Errorf("manifest path escapes vault boundary: %s", f.Path)
+		}
+		if _, err := os.Lstat(to); err == nil {
+			conflicts = append(conflicts, TrashFailure{Path: f.Path, Error: "a file already exists at the original path"})
+			continue
+		}
+		if _, err := os.Lstat(from); err != nil {
+			conflicts = append(conflicts, TrashFailure{Path: f.Path, Error: "no longer in the trash"})
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+			return restored, conflicts, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.Rename(from, to); err != nil {
+			return restored, conflicts, fmt.Errorf("failed to restore %s: %w", f.Path, err)
+		}
+		b.Files[i].Restored = true
+		restored++
+	}
+
+	if len(b.pending()) == 0 {
+		return restored, conflicts, os.RemoveAll(filepath.Join(b.absPath, b.dir()))
+	}
+	return restored, conflicts, b.save()
+}
+
+// purge permanently deletes the batch's files still in the trash and the
+// batch folder. Returns the bytes freed.
+func (b *TrashBatch) purge() (int64, error) {
+	var freed int64
+	for _, f := range b.pending() {
+		full := filepath.Join(b.absPath, filepath.FromSlash(f.TrashPath))
+		if !isPathWithinVault(full, b.absPath) {
+			return freed, fmt.Errorf("manifest path escapes vault boundary: %s", f.TrashPath)
+		}
+		if err := os.Remove(full); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return freed, fmt.Errorf("failed to remove %s: %w", f.TrashPath, err)
+		}
+		freed += f.Size
+	}
+	return freed, os.RemoveAll(filepath.Join(b.absPath, b.dir()))
+}
+
+// loadTrashBatches returns the vault's trash batches, newest first.
+func loadTrashBatches(absPath string) ([]*TrashBatch, error) {
+	entries, err := os.ReadDir(filepath.Join(absPath, quarantineDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read quarantine directory: %w", err)
+	}
+
+	var batches []*TrashBatch
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(absPath, quarantineDir, e.Name(), "manifest.json"))
+		if err != nil {
+			continue
+		}
+		b := &TrashBatch{}
+		if err := json.Unmarshal(data, b); err != nil || b.ID != e.Name() {
+			continue // Skip malformed manifests
+		}
+		b.absPath = absPath
+		batches = append(batches, b)
+	}
+	sort.Slice(batches, func(i, j int) bool {
+		if !batches[i].TrashedAt.Equal(batches[j].TrashedAt) {
+			return batches[i].TrashedAt.After(batches[j].TrashedAt)
+		}
+		return batches[i].ID > batches[j].ID
+	})
+	return batches, nil
+}
+
+// parseAge parses an age such as "30d", "2w" or any Go duration ("12h").
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "0" {
+		return 0, nil
+	}
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) && n >= 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q (use e.g. 30d, 2w or 12h)", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultConfigName is the per-vault config file looked up in the vault root.
+const defaultConfigName = ".obsidian-cli.json"
+
+// vaultConfig holds per-vault settings loaded from .obsidian-cli.json.
+type vaultConfig struct {
+	Health healthConfig `json:"health"`
+	Stubs  stubsConfig  `json:"stubs"`
+	New    newConfig    `json:"new"`
+}
+
+// newConfig controls where the new command puts notes and which template
+// it uses by default.
+type newConfig struct {
+	Folder   string            `json:"folder,omitempty"`   // Vault-relative folder for new notes
+	Template string            `json:"template,omitempty"` // Template used without --template
+	Folders  map[string]string `json:"folders,omitempty"`  // Template name -> folder for notes made from it
+}
+
+// stubsConfig controls where deadlinks --create-stubs puts new notes.
+type stubsConfig struct {
+	Folder   string `json:"folder,omitempty"`   // Vault-relative folder for bare targets
+	Template string `json:"template,omitempty"` // Vault-relative template note
+}
+
+// healthConfig configures the health rule engine.
+type healthConfig struct {
+	MaxErrors   *int                  `json:"max_errors,omitempty"`
+	MaxWarnings *int                  `json:"max_warnings,omitempty"`
+	Rules       map[string]ruleConfig `json:"rules,omitempty"`
+}
+
+// ruleConfig configures a single health rule. Folders holds overrides keyed by
+// vault-relative folder path; the most specific matching folder wins.
+type ruleConfig struct {
+	Enabled  *bool                  `json:"enabled,omitempty"`
+	Severity string                 `json:"severity,omitempty"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+	Folders  map[string]ruleConfig  `json:"folders,omitempty"`
+}
+
+// loadVaultConfig reads the config file for the vault at absPath.
+// Uses --config when set, otherwise <vault>/.obsidian-cli.json. A missing
+// default config is not an error; an explicitly requested one is.
+func loadVaultConfig(absPath string) (*vaultConfig, error) {
+	cfg := &vaultConfig{}
+
+	path := configPath
+	explicit := path != ""
+	if !explicit {
+		path = filepath.Join(absPath, defaultConfigName)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// folderMatches reports whether relPath lives in folder (vault-relative, slash separated).
+func folderMatches(relPath, folder string) bool {
+	folder = strings.Trim(filepath.ToSlash(folder), "/")
+	if folder == "" || folder == "." {
+		return true
+	}
+	p := strings.ToLower(filepath.ToSlash(relPath))
+	f := strings.ToLower(folder)
+	return strings.HasPrefix(p, f+"/")
+}
+
+// matchingFolders returns the keys of folders that contain relPath,
+// ordered from least to most specific.
+func matchingFolders[V any](folders map[string]V, relPath string) []string {
+	var matched []string
+	for _, folder := range sortedKeys(folders) {
+		if folderMatches(relPath, folder) {
+			matched = append(matched, folder)
+		}
+	}
+	// Shorter paths are less specific
+	sort.SliceStable(matched, func(i, j int) bool {
+		return len(matched[i]) < len(matched[j])
+	})
+	return matched
+}
+
+// ruleOptions wraps a rule's option map with typed accessors.
+// JSON numbers decode as float64, lists as []interface{}.
+type ruleOptions map[string]interface{}
+
+func (o ruleOptions) Int(key string, def int) int {
+	switch v := o[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return def
+}
+
+func (o ruleOptions) String(key, def string) string {
+	if v, ok := o[key].(string); ok {
+		return v
+	}
+	return def
+}
+
+func (o ruleOptions) Strings(key string) []string {
+	switch v := o[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		return []string{v}
+	}
+	return nil
+}
+
+func (o ruleOptions) Bool(key string, def bool) bool {
+	if v, ok := o[key].(bool); ok {
+		return v
+	}
+	return def
+}
@@ -1,21 +1,36 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/csv"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/kofifort/obsidian-cli/internal/vault"
 	"github.com/spf13/cobra"
 )
 
 var (
-	deadlinksLimit  int
-	deadlinksFormat string
-	deadlinksGroup  string
+	deadlinksLimit        int
+	deadlinksFormat       string
+	deadlinksGroup        string
+	deadlinksSuggest      bool
+	deadlinksApplyBest    bool
+	deadlinksInteractive  bool
+	deadlinksDryRun       bool
+	deadlinksNoGit        bool
+	deadlinksCreateStubs  bool
+	deadlinksStubFolder   string
+	deadlinksStubTemplate string
 )
 
+// maxLinkSuggestions is the number of suggestions shown per dead target.
+const maxLinkSuggestions = 3
+
 var deadlinksCmd = &cobra.Command{
 	Use:   "deadlinks",
 	Short: "List dead links (broken [[wikilinks]])",
@@ -24,11 +39,33 @@ var deadlinksCmd = &cobra.Command{
 Dead links are [[wikilinks]] that point to non-existent files.
 This helps identify broken references that need to be fixed or removed.
 
+With --suggest, each dead target gets ranked replacement notes based on:
+  - edit distance (typos like [[Projets]] for Projects)
+  - shared words ([[plan project]] for Project Plan)
+  - frontmatter aliases
+  - renames in git history, when the vault is in a git repository
+
+--apply-best rewrites links whose best suggestion is a clear winner;
+--interactive asks for each dead target instead. Both keep headings and
+aliases, support --dry-run, and are journaled (revert with "undo").
+
+--create-stubs creates a note for each dead target instead, for links that
+are intentional "notes to be written". Targets are merged case-insensitively;
+path-style targets ([[concepts/new-idea]]) are created at that path, others in
+the stub folder. Each stub lists its referencing notes in created_from.
+Folder and template are configured in <vault>/.obsidian-cli.json:
+
+  {"stubs": {"folder": "Inbox", "template": "Templates/Stub"}}
+
 Examples:
   obsidian-cli deadlinks --vault ~/Documents/Obsidian
   obsidian-cli deadlinks --vault ~/Documents/Obsidian --limit 50
   obsidian-cli deadlinks --vault ~/Documents/Obsidian --group target
-  obsidian-cli deadlinks --vault ~/Documents/Obsidian --format json`,
+  obsidian-cli deadlinks --vault ~/Documents/Obsidian --format json
+  obsidian-cli deadlinks --vault ~/Documents/Obsidian --suggest
+  obsidian-cli deadlinks --vault ~/Documents/Obsidian --apply-best --dry-run
+  obsidian-cli deadlinks --vault ~/Documents/Obsidian --interactive
+  obsidian-cli deadlinks --vault ~/Documents/Obsidian --create-stubs --dry-run`,
 	RunE: runDeadlinks,
 }
 
@@ -37,6 +74,14 @@ func init() {
 	deadlinksCmd.Flags().IntVarP(&deadlinksLimit, "limit", "n", 0, "Limit number of results (0 = no limit)")
 	deadlinksCmd.Flags().StringVar(&deadlinksFormat, "format", "text", "Output format: text, json, csv")
 	deadlinksCmd.Flags().StringVarP(&deadlinksGroup, "group", "g", "source", "Group by: source, target")
+	deadlinksCmd.Flags().BoolVar(&deadlinksSuggest, "suggest", false, "Suggest existing notes for each dead link")
+	deadlinksCmd.Flags().BoolVar(&deadlinksApplyBest, "apply-best", false, "Rewrite links to their best suggestion when it is a clear winner")
+	deadlinksCmd.Flags().BoolVarP(&deadlinksInteractive, "interactive", "i", false, "Choose a replacement for each dead target interactively")
+	deadlinksCmd.Flags().BoolVar(&deadlinksDryRun, "dry-run", false, "With --apply-best, --interactive or --create-stubs, preview changes without modifying files")
+	deadlinksCmd.Flags().BoolVar(&deadlinksNoGit, "no-git", false, "Don't use git history for rename detection")
+	deadlinksCmd.Flags().BoolVar(&deadlinksCreateStubs, "create-stubs", false, "Create a stub note for each dead link target")
+	deadlinksCmd.Flags().StringVar(&deadlinksStubFolder, "stub-folder", "", "Folder for stub notes (overrides config)")
+	deadlinksCmd.Flags().StringVar(&deadlinksStubTemplate, "stub-template", "", "Template note for stubs (overrides config)")
 }
 
 func runDeadlinks(cmd *cobra.Command, args []string) error {
@@ -44,6 +89,22 @@ func runDeadlinks(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	modes := 0
+	for _, on := range []bool{deadlinksApplyBest, deadlinksInteractive, deadlinksCreateStubs} {
+		if on {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return fmt.Errorf("--apply-best, --interactive and --create-stubs are mutually exclusive")
+	}
+	if deadlinksInteractive && deadlinksFormat != "text" {
+		return fmt.Errorf("--interactive requires --format text")
+	}
+	if deadlinksDryRun && modes == 0 {
+		return fmt.Errorf("--dry-run requires --apply-best, --interactive or --create-stubs")
+	}
+
 	if deadlinksFormat == "text" {
 		printScanHeader("Scanning vault")
 	}
@@ -53,18 +114,34 @@ func runDeadlinks(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if deadlinksCreateStubs {
+		return createDeadLinkStubs(cmd, scan.DeadLinks)
+	}
+
+	var suggestions map[string][]linkSuggestion
+	if deadlinksSuggest || deadlinksApplyBest || deadlinksInteractive {
+		var suggester *linkSuggester
+		suggestions, suggester, err = suggestDeadLinkTargets(scan.DeadLinks)
+		if err != nil {
+			return err
+		}
+		if deadlinksApplyBest || deadlinksInteractive {
+			return repairDeadLinks(cmd, scan.DeadLinks, suggestions, suggester.ix)
+		}
+	}
+
 	total := len(scan.DeadLinks)
 	deadLinks := applyLimit(scan.DeadLinks, deadlinksLimit)
 
 	switch deadlinksFormat {
 	case "json":
-		return encodeJSON(cmd, toJSONDeadLinks(deadLinks))
+		return encodeJSON(cmd, toJSONDeadLinks(deadLinks, suggestions))
 
 	case "csv":
-		return writeDeadLinksCSV(cmd, deadLinks)
+		return writeDeadLinksCSV(cmd, deadLinks, suggestions)
 
 	default:
-		printDeadLinksText(deadLinks, total)
+		printDeadLinksText(deadLinks, total, suggestions)
 		printLimitNote(total, deadlinksLimit)
 		printScanFooter(scan.Elapsed)
 	}
@@ -73,34 +150,70 @@ func runDeadlinks(cmd *cobra.Command, args []string) error {
 }
 
 type jsonDeadLink struct {
-	Source string `json:"source"`
-	Target string `json:"target"`
-	Line   int    `json:"line"`
+	Source      string           `json:"source"`
+	Target      string           `json:"target"`
+	Line        int              `json:"line"`
+	Suggestions []linkSuggestion `json:"suggestions,omitempty"`
 }
 
-func toJSONDeadLinks(deadLinks []vault.DeadLink) []jsonDeadLink {
+func toJSONDeadLinks(deadLinks []vault.DeadLink, suggestions map[string][]linkSuggestion) []jsonDeadLink {
 	result := make([]jsonDeadLink, len(deadLinks))
 	for i, dl := range deadLinks {
 		result[i] = jsonDeadLink{
-			Source: dl.SourceFile,
-			Target: dl.Target,
-			Line:   dl.Line,
+			Source:      dl.SourceFile,
+			Target:      dl.Target,
+			Line:        dl.Line,
+			Suggestions: suggestions[dl.Target],
 		}
 	}
 	return result
 }
 
-func writeDeadLinksCSV(cmd *cobra.Command, deadLinks []vault.DeadLink) error {
+func writeDeadLinksCSV(cmd *cobra.Command, deadLinks []vault.DeadLink, suggestions map[string][]linkSuggestion) error {
 	w := csv.NewWriter(cmd.OutOrStdout())
-	w.Write([]string{"source", "target", "line"})
+	header := []string{"source", "target", "line"}
+	if suggestions != nil {
+		header = append(header, "suggestion", "score")
+	}
+	w.Write(header)
 	for _, dl := range deadLinks {
-		w.Write([]string{dl.SourceFile, dl.Target, strconv.Itoa(dl.Line)})
+		row := []string{dl.SourceFile, dl.Target, strconv.Itoa(dl.Line)}
+		if suggestions != nil {
+			if sg := suggestions[dl.Target]; len(sg) > 0 {
+				row = append(row, sg[0].Path, strconv.FormatFloat(sg[0].Score, 'f', 2, 64))
+			} else {
+				row = append(row, "", "")
+			}
+		}
+		w.Write(row)
 	}
 	w.Flush()
 	return w.Error()
 }
 
-func printDeadLinksText(deadLinks []vault.DeadLink, total int) {
+// suggestDeadLinkTargets ranks replacement notes for each distinct dead target.
+func suggestDeadLinkTargets(deadLinks []vault.DeadLink) (map[string][]linkSuggestion, *linkSuggester, error) {
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid vault path: %w", err)
+	}
+	notes, err := loadNotes(absPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	suggester := newLinkSuggester(absPath, notes, !deadlinksNoGit)
+	suggestions := make(map[string][]linkSuggestion)
+	for _, dl := range deadLinks {
+		if _, done := suggestions[dl.Target]; done || strings.HasSuffix(dl.Target, "/") {
+			continue
+		}
+		suggestions[dl.Target] = suggester.suggest(dl.Target, maxLinkSuggestions)
+	}
+	return suggestions, suggester, nil
+}
+
+func printDeadLinksText(deadLinks []vault.DeadLink, total int, suggestions map[string][]linkSuggestion) {
 	fmt.Printf("%s Dead Links %s\n\n", colors.Red("!"), colors.Dim(fmt.Sprintf("(%d total)", total)))
 
 	if len(deadLinks) == 0 {
@@ -109,13 +222,29 @@ func printDeadLinksText(deadLinks []vault.DeadLink, total int) {
 	}
 
 	if deadlinksGroup == "target" {
-		printDeadLinksByTarget(deadLinks)
+		printDeadLinksByTarget(deadLinks, suggestions)
 	} else {
-		printDeadLinksBySource(deadLinks)
+		printDeadLinksBySource(deadLinks, suggestions)
 	}
 }
 
-func printDeadLinksByTarget(deadLinks []vault.DeadLink) {
+// printLinkSuggestions prints ranked suggestions, numbered when numbered is set.
+func printLinkSuggestions(suggestions []linkSuggestion, indent string, numbered bool) {
+	if len(suggestions) == 0 {
+		fmt.Printf("%s%s\n", indent, colors.Dim("no suggestions"))
+		return
+	}
+	for i, sg := range suggestions {
+		marker := colors.Green("→")
+		if numbered {
+			marker = colors.Green(fmt.Sprintf("%d)", i+1))
+		}
+		fmt.Printf("%s%s %s %s\n", indent, marker, sg.Path,
+			colors.Dim(fmt.Sprintf("(%.2f: %s)", sg.Score, strings.Join(sg.Reasons, ", "))))
+	}
+}
+
+func printDeadLinksByTarget(deadLinks []vault.DeadLink, suggestions map[string][]linkSuggestion) {
 	byTarget := make(map[string][]vault.DeadLink)
 	for _, dl := range deadLinks {
 		byTarget[dl.Target] = append(byTarget[dl.Target], dl)
@@ -140,11 +269,14 @@ func printDeadLinksByTarget(deadLinks []vault.DeadLink) {
 		for _, dl := range links {
 			fmt.Printf("    %s:%d\n", dl.SourceFile, dl.Line)
 		}
+		if suggestions != nil {
+			printLinkSuggestions(suggestions[tc.target], "    ", false)
+		}
 		fmt.Println()
 	}
 }
 
-func printDeadLinksBySource(deadLinks []vault.DeadLink) {
+func printDeadLinksBySource(deadLinks []vault.DeadLink, suggestions map[string][]linkSuggestion) {
 	bySource := make(map[string][]vault.DeadLink)
 	for _, dl := range deadLinks {
 		bySource[dl.SourceFile] = append(bySource[dl.SourceFile], dl)
@@ -155,7 +287,223 @@ func printDeadLinksBySource(deadLinks []vault.DeadLink) {
 		fmt.Printf("  %s %s\n", colors.Cyan(source), colors.Dim(fmt.Sprintf("(%d)", len(links))))
 		for _, dl := range links {
 			fmt.Printf("    :%d -> %s\n", dl.Line, colors.Red("[["+dl.Target+"]]"))
+			if suggestions != nil {
+				printLinkSuggestions(applyLimit(suggestions[dl.Target], 1), "         ", false)
+			}
 		}
 		fmt.Println()
 	}
 }
+
+// DeadLinkRepair is a dead link target rewritten to an existing note.
+type DeadLinkRepair struct {
+	Target string   `json:"target"`
+	Path   string   `json:"path"` // Chosen note, vault-relative without .md
+	Links  int      `json:"links"`
+	Files  []string `json:"files"`
+}
+
+// DeadLinkRepairResult holds the outcome of --apply-best or --interactive.
+type DeadLinkRepairResult struct {
+	Repairs       []DeadLinkRepair `json:"repairs"`
+	Skipped       []string         `json:"skipped"` // Targets left unchanged
+	LinksUpdated  int              `json:"links_updated"`
+	FilesModified int              `json:"files_modified"`
+	Executed      bool             `json:"executed"`
+	JournalID     string           `json:"journal_id,omitempty"`
+}
+
+// repairDeadLinks picks a replacement for each dead target (the confident
+// best suggestion, or the user's choice in interactive mode) and rewrites
+// the links, keeping headings, block refs and aliases.
+func repairDeadLinks(cmd *cobra.Command, deadLinks []vault.DeadLink, suggestions map[string][]linkSuggestion, ix *noteIndex) error {
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+
+	bySource := make(map[string]map[string]bool)
+	refs := make(map[string]int)
+	for _, dl := range deadLinks {
+		if bySource[dl.SourceFile] == nil {
+			bySource[dl.SourceFile] = make(map[string]bool)
+		}
+		bySource[dl.SourceFile][dl.Target] = true
+		refs[dl.Target]++
+	}
+
+	result := &DeadLinkRepairResult{
+		Repairs:  []DeadLinkRepair{},
+		Skipped:  []string{},
+		Executed: !deadlinksDryRun,
+	}
+
+	chosen := make(map[string]string)
+	if deadlinksInteractive {
+		chosen, err = chooseDeadLinkTargets(sortedKeys(refs), refs, suggestions)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, target := range sortedKeys(refs) {
+			if best, ok := bestSuggestion(suggestions[target]); ok {
+				chosen[target] = best.Path
+			}
+		}
+	}
+	for _, target := range sortedKeys(refs) {
+		if _, ok := chosen[target]; !ok {
+			result.Skipped = append(result.Skipped, target)
+		}
+	}
+
+	// Rewrite each affected file in memory
+	repairs := make(map[string]*DeadLinkRepair)
+	newContent := make(map[string]string)
+	oldContent := make(map[string]string)
+	for _, source := range sortedKeys(bySource) {
+		dead := bySource[source]
+		fullPath := filepath.Join(absPath, source)
+		if !isPathWithinVault(fullPath, absPath) {
+			continue
+		}
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", source, err)
+		}
+		content, count := rewriteWikilinks(string(data), func(l wikilink) (string, bool) {
+			path, ok := chosen[l.Target]
+			if !ok || !dead[l.Target] {
+				return "", false
+			}
+			repair := repairs[l.Target]
+			if repair == nil {
+				repair = &DeadLinkRepair{Target: l.Target, Path: path}
+				repairs[l.Target] = repair
+			}
+			repair.Links++
+			if n := len(repair.Files); n == 0 || repair.Files[n-1] != source {
+				repair.Files = append(repair.Files, source)
+			}
+			l.Target = ix.linkText(path)
+			return l.String(), true
+		})
+		if count > 0 {
+			oldContent[source] = string(data)
+			newContent[source] = content
+			result.LinksUpdated += count
+		}
+	}
+	for _, target := range sortedKeys(repairs) {
+		result.Repairs = append(result.Repairs, *repairs[target])
+	}
+	result.FilesModified = len(newContent)
+
+	if deadlinksFormat == "json" {
+		if !deadlinksDryRun {
+			result.JournalID, err = writeDeadLinkRepairs(absPath, newContent)
+			if err != nil {
+				return err
+			}
+		}
+		return encodeJSON(cmd, result)
+	}
+
+	printDeadLinkRepairs(result, oldContent, newContent)
+	if len(newContent) == 0 {
+		return nil
+	}
+	if deadlinksDryRun {
+		fmt.Printf("  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
+		return nil
+	}
+
+	journalID, err := writeDeadLinkRepairs(absPath, newContent)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s Updated %d links in %d files\n", colors.Green("✓"), result.LinksUpdated, result.FilesModified)
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n\n", colors.Dim("i"), journalID)
+	return nil
+}
+
+// chooseDeadLinkTargets asks which suggestion to use for each dead target.
+// Enter or "s" skips a target, "q" stops asking.
+func chooseDeadLinkTargets(targets []string, refs map[string]int, suggestions map[string][]linkSuggestion) (map[string]string, error) {
+	chosen := make(map[string]string)
+	reader := bufio.NewReader(os.Stdin)
+	for _, target := range targets {
+		options := suggestions[target]
+		if len(options) == 0 {
+			continue
+		}
+		fmt.Printf("  %s %s\n", colors.Red("[["+target+"]]"), colors.Dim(fmt.Sprintf("(%d references)", refs[target])))
+		printLinkSuggestions(options, "    ", true)
+		fmt.Printf("  %s Replace with [1-%d], s to skip, q to quit: ", colors.Yellow("?"), len(options))
+
+		response, err := reader.ReadString('\n')
+		if err != nil && response == "" {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+		response = strings.TrimSpace(strings.ToLower(response))
+		fmt.Println()
+		if response == "q" {
+			break
+		}
+		if n, err := strconv.Atoi(response); err == nil && n >= 1 && n <= len(options) {
+			chosen[target] = options[n-1].Path
+		}
+	}
+	return chosen, nil
+}
+
+// writeDeadLinkRepairs writes rewritten files through a journal. Returns the journal ID.
+func writeDeadLinkRepairs(absPath string, newContent map[string]string) (journalID string, err error) {
+	if len(newContent) == 0 {
+		return "", nil
+	}
+
+	j := newJournal(absPath, "deadlinks", fmt.Sprintf("repair dead links in %d files", len(newContent)))
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	for _, source := range sortedKeys(newContent) {
+		if err := j.writeFile(source, []byte(newContent[source]), 0644); err != nil {
+			return j.ID, err
+		}
+	}
+	return j.ID, nil
+}
+
+func printDeadLinkRepairs(result *DeadLinkRepairResult, oldContent, newContent map[string]string) {
+	title := "Dead Link Repairs"
+	if deadlinksDryRun {
+		title += " (dry run)"
+	}
+	fmt.Printf("%s %s\n\n", colors.Green("→"), title)
+
+	if len(result.Repairs) == 0 {
+		fmt.Printf("  No dead links with a clear replacement.\n")
+	}
+	for _, r := range result.Repairs {
+		fmt.Printf("  %s %s %s %s\n", colors.Red("[["+r.Target+"]]"), colors.Green("→"), r.Path,
+			colors.Dim(fmt.Sprintf("(%d links in %d files)", r.Links, len(r.Files))))
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Printf("\n  %s %d targets left unchanged\n", colors.Dim("i"), len(result.Skipped))
+	}
+	fmt.Println()
+
+	if deadlinksDryRun {
+		for _, source := range sortedKeys(newContent) {
+			fmt.Printf("    %s\n", colors.Cyan(source))
+			printDiff(diffLines(oldContent[source], newContent[source]), 0)
+		}
+		if len(newContent) > 0 {
+			fmt.Println()
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffLine is one line of a line-based diff.
+type diffLine struct {
+	Op   byte // ' ', '-' or '+'
+	Text string
+	Old  int // 1-based line in the old text (0 for additions)
+	New  int // 1-based line in the new text (0 for removals)
+}
+
+// diffLines computes a minimal line diff between two texts using an LCS
+// table. Notes are small enough that the quadratic table is not a concern;
+// for very large inputs the common prefix and suffix are trimmed first.
+func diffLines(oldText, newText string) []diffLine {
+	a := strings.Split(oldText, "\n")
+	b := strings.Split(newText, "\n")
+
+	// Trim common prefix and suffix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var out []diffLine
+	for i := 0; i < prefix; i++ {
+		out = append(out, diffLine{Op: ' ', Text: a[i], Old: i + 1, New: i + 1})
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			out = append(out, diffLine{Op: ' ', Text: ma[i], Old: prefix + i + 1, New: prefix + j + 1})
+			i++
+			j++
+		case i < len(ma) && (j == len(mb) || lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, diffLine{Op: '-', Text: ma[i], Old: prefix + i + 1})
+			i++
+		default:
+			out = append(out, diffLine{Op: '+', Text: mb[j], New: prefix + j + 1})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		out = append(out, diffLine{
+			Op:   ' ',
+			Text: a[len(a)-suffix+k],
+			Old:  len(a) - suffix + k + 1,
+			New:  len(b) - suffix + k + 1,
+		})
+	}
+	return out
+}
+
+// printDiff prints the changed lines of a diff with up to context
+// unchanged lines around each change, in the style of the rename preview.
+func printDiff(lines []diffLine, context int) {
+	show := make([]bool, len(lines))
+	for i, l := range lines {
+		if l.Op == ' ' {
+			continue
+		}
+		for k := max(0, i-context); k <= min(len(lines)-1, i+context); k++ {
+			show[k] = true
+		}
+	}
+
+	gap := false
+	for i, l := range lines {
+		if !show[i] {
+			gap = true
+			continue
+		}
+		if gap {
+			fmt.Printf("      %s\n", colors.Dim("..."))
+			gap = false
+		}
+		text := truncateRunes(l.Text, 76)
+		switch l.Op {
+		case '-':
+			fmt.Printf("      %s %s\n", colors.Red(fmt.Sprintf("-%-4d", l.Old)), colors.Red(text))
+		case '+':
+			fmt.Printf("      %s %s\n", colors.Green(fmt.Sprintf("+%-4d", l.New)), colors.Green(text))
+		default:
+			fmt.Printf("      %s %s\n", colors.Dim(fmt.Sprintf(" %-4d", l.New)), colors.Dim(text))
+		}
+	}
+}
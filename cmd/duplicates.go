@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	duplicatesKinds     []string
+	duplicatesThreshold float64
+	duplicatesMinWords  int
+	duplicatesFolder    string
+	duplicatesFormat    string
+)
+
+var duplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Find duplicate and near-duplicate notes",
+	Long: `Finds notes that duplicate each other, grouped in clusters:
+
+  exact   Same body, ignoring frontmatter, case and whitespace
+  near    Bodies sharing most of their 5-word phrases: the Jaccard
+          similarity of the phrases is at least --threshold. Candidates
+          are found with MinHash, then scored exactly
+  title   Same name in different folders, whatever their bodies; these
+          clusters have no similarity score
+
+Notes with fewer than --min-words words are left out of near-duplicate
+detection, and empty notes out of all but title matches.
+
+Examples:
+  obsidian-cli duplicates --vault ~/notes
+  obsidian-cli duplicates --vault ~/notes --kind near --threshold 0.6
+  obsidian-cli duplicates --vault ~/notes --folder Imports --format json`,
+	Args: cobra.NoArgs,
+	RunE: runDuplicates,
+}
+
+func init() {
+	rootCmd.AddCommand(duplicatesCmd)
+	duplicatesCmd.Flags().StringSliceVar(&duplicatesKinds, "kind", []string{"exact", "near", "title"}, "Kinds of duplicates: exact, near, title")
+	duplicatesCmd.Flags().Float64Var(&duplicatesThreshold, "threshold", 0.8, "Minimum similarity of near duplicates (0-1)")
+	duplicatesCmd.Flags().IntVar(&duplicatesMinWords, "min-words", 20, "Minimum words for near-duplicate detection")
+	duplicatesCmd.Flags().StringVarP(&duplicatesFolder, "folder", "f", "", "Only compare notes in this folder")
+	duplicatesCmd.Flags().StringVar(&duplicatesFormat, "format", "text", "Output format: text, json")
+}
+
+const (
+	shingleSize   = 5  // Words per shingle
+	minHashBands  = 16 // LSH bands; with 4 rows, pairs above ~0.5 similarity become candidates
+	minHashRows   = 4
+	minHashLength = minHashBands * minHashRows
+)
+
+// DuplicateNote is a note in a cluster.
+type DuplicateNote struct {
+	Path       string  `json:"path"`
+	Words      int     `json:"words"`
+	Similarity float64 `json:"similarity,omitempty"` // To the first note of the cluster; none for title clusters
+}
+
+// DuplicateCluster is a group of notes duplicating each other.
+type DuplicateCluster struct {
+	Kind       string          `json:"kind"`                 // exact, near or title
+	Similarity float64         `json:"similarity,omitempty"` // None for title clusters
+	Notes      []DuplicateNote `json:"notes"`
+}
+
+// DuplicatesResult is the result of the duplicates command.
+type DuplicatesResult struct {
+	NotesScanned int                `json:"notes_scanned"`
+	Threshold    float64            `json:"threshold"`
+	Clusters     []DuplicateCluster `json:"clusters"`
+}
+
+// dupNote is a note prepared for comparison.
+type dupNote struct {
+	path      string
+	words     int
+	hash      string          // Of the normalized body
+	shingles  map[uint64]bool // Hashed word shingles
+	signature []uint64        // MinHash signature
+}
+
+func runDuplicates(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	kinds := make(map[string]bool)
+	for _, k := range duplicatesKinds {
+		if k != "exact" && k != "near" && k != "title" {
+			return fmt.Errorf("invalid --kind %q (expected exact, near or title)", k)
+		}
+		kinds[k] = true
+	}
+	if duplicatesThreshold <= 0 || duplicatesThreshold > 1 {
+		return fmt.Errorf("invalid --threshold %g (expected 0-1)", duplicatesThreshold)
+	}
+	start := time.Now()
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	if duplicatesFormat == "text" {
+		printScanHeader("Finding duplicates")
+	}
+
+	notes, err := loadNotes(absPath)
+	if err != nil {
+		return err
+	}
+	var dups []*dupNote
+	for _, n := range notes {
+		if folderMatches(n.RelPath, duplicatesFolder) {
+			dups = append(dups, newDupNote(filepath.ToSlash(n.RelPath), n.Body))
+		}
+	}
+	sort.Slice(dups, func(i, j int) bool { return dups[i].path < dups[j].path })
+
+	result := &DuplicatesResult{NotesScanned: len(dups), Threshold: duplicatesThreshold, Clusters: []DuplicateCluster{}}
+	if kinds["exact"] {
+		result.Clusters = append(result.Clusters, exactDuplicates(dups)...)
+	}
+	if kinds["near"] {
+		result.Clusters = append(result.Clusters, nearDuplicates(dups, duplicatesThreshold, duplicatesMinWords)...)
+	}
+	if kinds["title"] {
+		result.Clusters = append(result.Clusters, titleDuplicates(dups)...)
+	}
+
+	if duplicatesFormat == "json" {
+		return encodeJSON(cmd, result)
+	}
+	printDuplicates(result)
+	printScanFooter(time.Since(start))
+	return nil
+}
+
+// newDupNote normalizes a note body and computes its shingles and MinHash
+// signature.
+func newDupNote(p, body string) *dupNote {
+	words := strings.FieldsFunc(strings.ToLower(body), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	d := &dupNote{path: p, words: len(words), shingles: make(map[uint64]bool)}
+	if len(words) == 0 {
+		return d
+	}
+	d.hash = contentHash([]byte(strings.Join(strings.Fields(strings.ToLower(body)), " ")))
+
+	for i := 0; i+shingleSize <= len(words) || i == 0; i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:min(i+shingleSize, len(words))], " ")))
+		d.shingles[h.Sum64()] = true
+	}
+	d.signature = make([]uint64, minHashLength)
+	for i := range d.signature {
+		d.signature[i] = ^uint64(0)
+	}
+	for s := range d.shingles {
+		for i := range d.signature {
+			if v := mix64(s ^ minHashSeed(i)); v < d.signature[i] {
+				d.signature[i] = v
+			}
+		}
+	}
+	return d
+}
+
+// minHashSeed returns the seed of the i-th MinHash function.
+func minHashSeed(i int) uint64 {
+	return mix64(uint64(i+1) * 0x9e3779b97f4a7c15)
+}
+
+// mix64 is the splitmix64 finalizer, a fast well-mixed 64-bit hash.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// jaccard returns the Jaccard similarity of two notes' shingles.
+func jaccard(a, b *dupNote) float64 {
+	if len(a.shingles) == 0 || len(b.shingles) == 0 {
+		return 0
+	}
+	if len(a.shingles) > len(b.shingles) {
+		a, b = b, a
+	}
+	shared := 0
+	for s := range a.shingles {
+		if b.shingles[s] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a.shingles)+len(b.shingles)-shared)
+}
+
+// exactDuplicates groups notes with the same normalized body.
+func exactDuplicates(notes []*dupNote) []DuplicateCluster {
+	byHash := make(map[string][]*dupNote)
+	for _, n := range notes {
+		if n.hash != "" {
+			byHash[n.hash] = append(byHash[n.hash], n)
+		}
+	}
+	var clusters []DuplicateCluster
+	for _, hash := range sortedKeys(byHash) {
+		if group := byHash[hash]; len(group) > 1 {
+			clusters = append(clusters, newDuplicateCluster("exact", group, 1))
+		}
+	}
+	sortDuplicateClusters(clusters)
+	return clusters
+}
+
+// nearDuplicates clusters notes whose similarity is at least threshold.
+// Candidate pairs share a band of their MinHash signatures; exact
+// duplicates are left to exactDuplicates.
+func nearDuplicates(notes []*dupNote, threshold float64, minWords int) []DuplicateCluster {
+	var eligible []*dupNote
+	for _, n := range notes {
+		if n.words >= minWords {
+			eligible = append(eligible, n)
+		}
+	}
+
+	parent := make([]int, len(eligible))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	type pair struct{ a, b int }
+	scores := make(map[pair]float64)
+	for band := 0; band < minHashBands; band++ {
+		buckets := make(map[string][]int)
+		for i, n := range eligible {
+			key := fmt.Sprint(n.signature[band*minHashRows : (band+1)*minHashRows])
+			buckets[key] = append(buckets[key], i)
+		}
+		for _, bucket := range buckets {
+			for x := 0; x < len(bucket); x++ {
+				for y := x + 1; y < len(bucket); y++ {
+					p := pair{bucket[x], bucket[y]}
+					if _, done := scores[p]; done {
+						continue
+					}
+					a, b := eligible[p.a], eligible[p.b]
+					scores[p] = -1
+					if a.hash == b.hash {
+						continue
+					}
+					if s := jaccard(a, b); s >= threshold {
+						scores[p] = s
+						parent[find(p.a)] = find(p.b)
+					}
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]*dupNote)
+	lowest := make(map[int]float64)
+	for p, s := range scores {
+		if s < 0 {
+			continue
+		}
+		root := find(p.a)
+		if l, ok := lowest[root]; !ok || s < l {
+			lowest[root] = s
+		}
+	}
+	for i, n := range eligible {
+		if _, ok := lowest[find(i)]; ok {
+			groups[find(i)] = append(groups[find(i)], n)
+		}
+	}
+	var clusters []DuplicateCluster
+	for root, group := range groups {
+		clusters = append(clusters, newDuplicateCluster("near", group, lowest[root]))
+	}
+	sortDuplicateClusters(clusters)
+	return clusters
+}
+
+// titleDuplicates groups notes with the same name in different folders.
+func titleDuplicates(notes []*dupNote) []DuplicateCluster {
+	byName := make(map[string][]*dupNote)
+	for _, n := range notes {
+		name := strings.ToLower(strings.TrimSuffix(path.Base(n.path), path.Ext(n.path)))
+		byName[name] = append(byName[name], n)
+	}
+	var clusters []DuplicateCluster
+	for _, name := range sortedKeys(byName) {
+		group := byName[name]
+		if len(group) < 2 {
+			continue
+		}
+		clusters = append(clusters, newDuplicateCluster("title", group, 0))
+	}
+	sortDuplicateClusters(clusters)
+	return clusters
+}
+
+// newDuplicateCluster builds a cluster, scoring each note against the
+// first. Title clusters match by name, so their notes aren't scored.
+func newDuplicateCluster(kind string, group []*dupNote, similarity float64) DuplicateCluster {
+	sort.Slice(group, func(i, j int) bool { return group[i].path < group[j].path })
+	c := DuplicateCluster{Kind: kind, Similarity: roundSimilarity(similarity)}
+	for i, n := range group {
+		note := DuplicateNote{Path: n.path, Words: n.words}
+		if kind != "title" {
+			s := 1.0
+			if i > 0 && n.hash != group[0].hash {
+				s = jaccard(group[0], n)
+			}
+			note.Similarity = roundSimilarity(s)
+		}
+		c.Notes = append(c.Notes, note)
+	}
+	return c
+}
+
+// roundSimilarity rounds a similarity to 3 decimals for output.
+func roundSimilarity(s float64) float64 {
+	return float64(int(s*1000+0.5)) / 1000
+}
+
+// sortDuplicateClusters orders clusters by similarity, then size.
+func sortDuplicateClusters(clusters []DuplicateCluster) {
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Similarity != clusters[j].Similarity {
+			return clusters[i].Similarity > clusters[j].Similarity
+		}
+		if len(clusters[i].Notes) != len(clusters[j].Notes) {
+			return len(clusters[i].Notes) > len(clusters[j].Notes)
+		}
+		return clusters[i].Notes[0].Path < clusters[j].Notes[0].Path
+	})
+}
+
+func printDuplicates(result *DuplicatesResult) {
+	titles := map[string]string{
+		"exact": "Exact Duplicates",
+		"near":  fmt.Sprintf("Near Duplicates (similarity >= %g)", result.Threshold),
+		"title": "Same Name in Different Folders",
+	}
+	if len(result.Clusters) == 0 {
+		fmt.Printf("%s No duplicates among %d notes\n\n", colors.Green("✓"), result.NotesScanned)
+		return
+	}
+	for _, kind := range []string{"exact", "near", "title"} {
+		var clusters []DuplicateCluster
+		for _, c := range result.Clusters {
+			if c.Kind == kind {
+				clusters = append(clusters, c)
+			}
+		}
+		if len(clusters) == 0 {
+			continue
+		}
+		fmt.Printf("%s %s %s\n\n", colors.Yellow("!"), titles[kind], colors.Dim(fmt.Sprintf("(%d clusters)", len(clusters))))
+		for _, c := range clusters {
+			label := fmt.Sprintf("%.0f%%", c.Similarity*100)
+			if kind == "title" {
+				label = strings.TrimSuffix(path.Base(c.Notes[0].Path), path.Ext(c.Notes[0].Path))
+			}
+			fmt.Printf("  %s %s\n", colors.Cyan(label), colors.Dim(fmt.Sprintf("%d notes", len(c.Notes))))
+			for i, n := range c.Notes {
+				score := ""
+				if i > 0 && kind == "near" {
+					score = fmt.Sprintf(" %.0f%%", n.Similarity*100)
+				}
+				fmt.Printf("    %s %s\n", n.Path, colors.Dim(fmt.Sprintf("(%d words)%s", n.Words, score)))
+			}
+			fmt.Println()
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDuplicateClusters tests exact, near and title clusters
+func TestDuplicateClusters(t *testing.T) {
+	text := "the quick brown fox jumps over the lazy dog while the farmer watches from the old wooden porch"
+	notes := []*dupNote{
+		newDupNote("a.md", text),
+		newDupNote("b.md", "  "+strings.ToUpper(text)+"\n\n"),
+		newDupNote("c.md", strings.Replace(text, "porch", "barn", 1)),
+		newDupNote("x/a.md", "something else entirely"),
+	}
+
+	exact := exactDuplicates(notes)
+	if len(exact) != 1 || len(exact[0].Notes) != 2 || exact[0].Notes[1].Path != "b.md" {
+		t.Errorf("exactDuplicates = %+v, want a.md and b.md", exact)
+	}
+	near := nearDuplicates(notes, 0.8, 10)
+	if len(near) != 1 || len(near[0].Notes) != 3 || near[0].Similarity < 0.8 || near[0].Similarity >= 1 {
+		t.Errorf("nearDuplicates = %+v, want a.md, b.md and c.md", near)
+	}
+	title := titleDuplicates(notes)
+	if len(title) != 1 || title[0].Notes[1].Path != "x/a.md" || title[0].Similarity != 0 || title[0].Notes[1].Similarity != 0 {
+		t.Errorf("titleDuplicates = %+v, want a.md and x/a.md", title)
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// epubBook is an EPUB 3 book whose chapters are rendered XHTML.
+type epubBook struct {
+	ID       string
+	Title    string
+	Modified time.Time // Also the timestamp of every file in the archive
+	Chapters []*bundleChapter
+	Media    []epubResource
+}
+
+// epubResource is a file in the book, relative to the package document.
+type epubResource struct {
+	Href string
+	Type string
+	Data []byte
+}
+
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`
+
+var epubPackageTemplate = template.Must(template.New("opf").Parse(`<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:identifier id="book-id">{{.ID}}</dc:identifier>
+<dc:title>{{.Title}}</dc:title>
+<dc:language>en</dc:language>
+<meta property="dcterms:modified">{{.Modified}}</meta>
+</metadata>
+<manifest>
+<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+<item id="style" href="style.css" media-type="text/css"/>
+{{- range .Chapters}}
+<item id="{{.ID}}" href="{{.File}}" media-type="application/xhtml+xml"/>
+{{- end}}
+{{- range $i, $m := .Media}}
+<item id="media{{$i}}" href="{{$m.Href}}" media-type="{{$m.Type}}"/>
+{{- end}}
+</manifest>
+<spine>
+{{- range .Chapters}}
+<itemref idref="{{.ID}}"/>
+{{- end}}
+</spine>
+</package>
+`))
+
+var epubNavTemplate = template.Must(template.New("nav").Parse(`<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en">
+<head>
+<meta charset="utf-8"/>
+<title>{{.Title}}</title>
+</head>
+<body>
+<nav epub:type="toc" id="toc">
+<h1>{{.Title}}</h1>
+<ol>
+{{- range .Chapters}}
+<li><a href="{{.File}}#{{.ID}}">{{.Title}}</a></li>
+{{- end}}
+</ol>
+</nav>
+</body>
+</html>
+`))
+
+var epubChapterTemplate = template.Must(template.New("chapter").Parse(`<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" lang="en" xml:lang="en">
+<head>
+<meta charset="utf-8"/>
+<title>{{.Title}}</title>
+<link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+<section id="{{.ID}}">
+{{- if .Heading}}
+<h1>{{.Title}}</h1>
+{{- end}}
+{{.Content}}
+</section>
+</body>
+</html>
+`))
+
+// writeEPUB writes the book as an EPUB archive. The output only depends on
+// the book, so the same notes give the same file.
+func writeEPUB(w io.Writer, book epubBook) error {
+	zw := zip.NewWriter(w)
+	add := func(name string, method uint16, data []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: book.Modified})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	render := func(name string, t *template.Template, data any) error {
+		// html/template would escape the XML declaration
+		var b strings.Builder
+		b.WriteString(xml.Header)
+		if err := t.Execute(&b, data); err != nil {
+			return fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		return add(name, zip.Deflate, []byte(b.String()))
+	}
+
+	// The mimetype must come first and be stored uncompressed
+	if err := add("mimetype", zip.Store, []byte("application/epub+zip")); err != nil {
+		return err
+	}
+	if err := add("META-INF/container.xml", zip.Deflate, []byte(epubContainer)); err != nil {
+		return err
+	}
+	if err := render("OEBPS/content.opf", epubPackageTemplate, struct {
+		epubBook
+		Modified string
+	}{book, book.Modified.Format("2006-01-02T15:04:05Z")}); err != nil {
+		return err
+	}
+	if err := render("OEBPS/nav.xhtml", epubNavTemplate, book); err != nil {
+		return err
+	}
+	if err := add("OEBPS/style.css", zip.Deflate, []byte(exportStylesheet)); err != nil {
+		return err
+	}
+	for _, ch := range book.Chapters {
+		if err := render(path.Join("OEBPS", ch.File), epubChapterTemplate, ch); err != nil {
+			return err
+		}
+	}
+	for _, m := range book.Media {
+		if err := add(path.Join("OEBPS", m.Href), zip.Store, m.Data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
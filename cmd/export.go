@@ -0,0 +1,699 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kofifort/obsidian-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export notes to a static site or portable markdown",
+	Long: `Exports a selection of notes outside the vault with Obsidian-specific
+syntax (wikilinks, embeds, callouts, comments) converted.
+
+Notes are selected with --folder, --tag and --published; with none of
+them the whole vault is exported. Notes with publish: false in their
+frontmatter are never exported.`,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+}
+
+// Maximum depth of nested transclusions; deeper embeds become links
+const exportMaxEmbedDepth = 5
+
+// Marks an output directory as created by export, so it can be replaced
+const exportMarker = ".obsidian-cli-export"
+
+// exportSelection chooses the notes to export.
+type exportSelection struct {
+	Folder    string
+	Tags      []string
+	Published bool
+}
+
+// addExportSelectionFlags registers the flags shared by export subcommands.
+func addExportSelectionFlags(cmd *cobra.Command, sel *exportSelection) {
+	cmd.Flags().StringVarP(&sel.Folder, "folder", "f", "", "Export only notes in this folder")
+	cmd.Flags().StringSliceVarP(&sel.Tags, "tag", "t", nil, "Export only notes with one of these tags (repeatable)")
+	cmd.Flags().BoolVar(&sel.Published, "published", false, "Export only notes with publish: true in frontmatter")
+}
+
+// matches reports whether a note is selected. Tags match nested tags, so
+// "project" selects notes tagged project/alpha.
+func (sel *exportSelection) matches(note *noteFile) bool {
+	publish := strings.ToLower(strings.Join(note.Frontmatter.Values("publish"), ""))
+	if publish == "false" || (sel.Published && publish != "true") {
+		return false
+	}
+	if !folderMatches(note.RelPath, sel.Folder) {
+		return false
+	}
+	if len(sel.Tags) == 0 {
+		return true
+	}
+	for _, occ := range noteTagOccurrences(note) {
+		tag := strings.ToLower(occ.Tag)
+		for _, want := range sel.Tags {
+			want = strings.ToLower(strings.TrimPrefix(want, "#"))
+			if tag == want || strings.HasPrefix(tag, want+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExportLink is a link that was not exported as a link because its
+// target is not part of the export or doesn't exist.
+type ExportLink struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Line   int    `json:"line"`
+	Reason string `json:"reason"` // "unpublished" or "dead"
+}
+
+// ExportResult is the summary of an export.
+type ExportResult struct {
+	Output  string       `json:"output"`
+	Notes   int          `json:"notes"`
+	Assets  int          `json:"assets"`
+	Skipped []ExportLink `json:"skipped_links"`
+}
+
+// exportSite converts the selected notes of a vault from Obsidian markdown
+// to CommonMark, with links rewritten relative to each note's output path.
+// Notes are identified by their slash-separated path without .md, as in
+// noteIndex.
+type exportSite struct {
+	absPath     string
+	html        bool          // Convert for HTML rendering rather than markdown output
+	ext         string        // Output extension of notes
+	unpublished string        // Links to notes outside the export: "strip" or "flag"
+	linkEmbeds  bool          // Link to embedded notes instead of inlining them
+	tagsPage    string        // Output path of the tag index that inline tags link to, if any
+	bundle      *exportBundle // Set when the notes are written into one document
+
+	notes    map[string]*noteFile
+	resolver *noteIndex // All notes in the vault
+	assets   *noteIndex // All other files in the vault, paths with extension
+	selected map[string]bool
+
+	usedAssets map[string]bool            // Assets referenced by exported notes
+	backlinks  map[string]map[string]bool // Exported note -> exported notes linking to it
+	skipped    []ExportLink
+}
+
+// exportContext is the position of the converter: the note the text comes
+// from, and the exported note it is written into.
+type exportContext struct {
+	src   *noteFile
+	path  string // Path of src
+	out   string // Path of the note being written; links are relative to it
+	depth int
+	stack map[string]bool // Notes being transcluded, for cycle detection
+}
+
+// newExportSite loads the vault and selects the notes to export.
+func newExportSite(absPath string, sel *exportSelection, forHTML bool) (*exportSite, error) {
+	notes, err := loadNotes(absPath)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := collectAssetFiles(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &exportSite{
+		absPath:     absPath,
+		html:        forHTML,
+		ext:         ".md",
+		unpublished: "strip",
+		notes:       make(map[string]*noteFile, len(notes)),
+		resolver:    newNoteIndexFromNotes(notes),
+		assets:      newNoteIndex(assets),
+		selected:    make(map[string]bool),
+		usedAssets:  make(map[string]bool),
+		backlinks:   make(map[string]map[string]bool),
+	}
+	if forHTML {
+		s.ext = ".html"
+	}
+	for _, note := range notes {
+		p := strings.TrimSuffix(filepath.ToSlash(note.RelPath), ".md")
+		s.notes[p] = note
+		if sel.matches(note) {
+			s.selected[p] = true
+		}
+	}
+	return s, nil
+}
+
+// collectAssetFiles returns the vault-relative paths of all non-markdown files.
+func collectAssetFiles(absPath string) ([]string, error) {
+	var assets []string
+	err := filepath.WalkDir(absPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skip, skipDir := shouldSkipEntry(path, d, absPath); skip {
+			if skipDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && !strings.HasPrefix(d.Name(), ".") && !strings.HasSuffix(strings.ToLower(path), ".md") {
+			assets = append(assets, mustRelPath(absPath, path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk failed: %w", err)
+	}
+	return assets, nil
+}
+
+// selectedPaths returns the exported notes in order.
+func (s *exportSite) selectedPaths() []string {
+	return sortedKeys(s.selected)
+}
+
+// outPath returns the output path of a note.
+func (s *exportSite) outPath(p string) string {
+	return p + s.ext
+}
+
+// title returns a note's frontmatter title, or its name.
+func (s *exportSite) title(p string) string {
+	if t := s.notes[p].Frontmatter.Values("title"); len(t) > 0 {
+		return t[0]
+	}
+	return s.notes[p].Name
+}
+
+// convertNote returns the body of a note converted to CommonMark.
+func (s *exportSite) convertNote(p string) string {
+	note := s.notes[p]
+	c := exportContext{src: note, path: p, out: p, stack: map[string]bool{p: true}}
+	return s.convertText(c, note.Body, note.BodyLine)
+}
+
+var (
+	// Matches an Obsidian comment: %%text%%
+	obsidianCommentRegex = regexp.MustCompile(`(?s)%%.*?%%`)
+	// Matches the first line of a callout: "> [!type]- Title"
+	calloutRegex = regexp.MustCompile(`(?m)^((?:>[ \t]?)+)\[!([\w-]+)\][+-]?[ \t]*(.*?)(\r?)$`)
+	// Matches [text](dest "title"), ![alt](dest) and [text](<dest with spaces>)
+	markdownLinkRegex = regexp.MustCompile(`(!?)\[([^\[\]]*)\]\((?:<([^<>\n]+)>|([^()\s]+))((?:\s+"[^"\n]*")?)\)`)
+	// Matches a block ID at the end of a line
+	exportBlockIDRegex = regexp.MustCompile(`(?m)(?:^|[ \t])\^([A-Za-z0-9-]+)[ \t]*(\r?)$`)
+	// Matches an embed size: "300" or "300x200"
+	embedSizeRegex = regexp.MustCompile(`^(\d+)(?:x(\d+))?$`)
+)
+
+// convertText converts text from c.src starting on line firstLine.
+// Fenced code blocks and inline code are copied unchanged.
+func (s *exportSite) convertText(c exportContext, text string, firstLine int) string {
+	return mapOutsideFences(text, firstLine, func(chunk string, line int) string {
+		// Comments are private; keep their newlines so line numbers still match
+		chunk = obsidianCommentRegex.ReplaceAllStringFunc(chunk, func(m string) string {
+			return strings.Repeat("\n", strings.Count(m, "\n"))
+		})
+		chunk = calloutRegex.ReplaceAllStringFunc(chunk, func(m string) string {
+			sm := calloutRegex.FindStringSubmatch(m)
+			title := sm[3]
+			if title == "" {
+				title = strings.ToUpper(sm[2][:1]) + sm[2][1:]
+			}
+			return sm[1] + "**" + title + "**" + sm[4]
+		})
+		return mapOutsideInlineCode(chunk, line, func(piece string, line int) string {
+			return s.convertInline(c, piece, line)
+		})
+	})
+}
+
+// exportSpan is a replacement of text[start:end].
+type exportSpan struct {
+	start, end int
+	text       string
+}
+
+// convertInline rewrites the links, embeds, block IDs and (for HTML)
+// tags in text that contains no code.
+func (s *exportSite) convertInline(c exportContext, text string, firstLine int) string {
+	lineAt := func(pos int) int { return firstLine + strings.Count(text[:pos], "\n") }
+
+	var spans []exportSpan
+	for _, l := range parseWikilinks(text) {
+		spans = append(spans, exportSpan{l.Start, l.End, s.convertWikilink(c, l, lineAt(l.Start))})
+	}
+	for _, m := range markdownLinkRegex.FindAllStringSubmatchIndex(text, -1) {
+		// Unchanged links are kept as spans so tags don't match inside them
+		replacement, ok := s.convertMarkdownLink(c, text, m, lineAt(m[0]))
+		if !ok {
+			replacement = text[m[0]:m[1]]
+		}
+		spans = append(spans, exportSpan{m[0], m[1], replacement})
+	}
+	for _, m := range exportBlockIDRegex.FindAllStringSubmatchIndex(text, -1) {
+		anchor := ""
+		if s.html {
+			anchor = fmt.Sprintf(` <span id="%s"></span>`, s.blockAnchor(c.out, text[m[2]:m[3]]))
+		}
+		spans = append(spans, exportSpan{m[0], m[1], anchor + text[m[4]:m[5]]})
+	}
+	if s.html && s.tagsPage != "" {
+		for _, m := range inlineTagRegex.FindAllStringSubmatchIndex(text, -1) {
+			tag := text[m[2]:m[3]]
+			href := relURL(s.outPath(c.out), s.tagsPage) + "#" + headingSlug(strings.ToLower(tag))
+			spans = append(spans, exportSpan{m[2] - 1, m[3], fmt.Sprintf(`<a class="tag" href="%s">#%s</a>`, html.EscapeString(href), html.EscapeString(tag))})
+		}
+	}
+	if len(spans) == 0 {
+		return text
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	var b strings.Builder
+	last := 0
+	for _, sp := range spans {
+		if sp.start < last {
+			continue // Overlaps an earlier replacement
+		}
+		b.WriteString(text[last:sp.start])
+		b.WriteString(sp.text)
+		last = sp.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// convertWikilink converts a wikilink or embed to markdown.
+func (s *exportSite) convertWikilink(c exportContext, l wikilink, line int) string {
+	display := l.Alias
+	if !l.HasAlias {
+		display = wikilinkDisplay(l)
+	}
+	if l.Target == "" {
+		return markdownLink(display, s.noteURL(c.out, c.out, l.Fragment))
+	}
+
+	if p, ok := s.resolver.resolve(l.Target); ok {
+		if !s.selected[p] {
+			return s.skip(c, l, line, "unpublished", display)
+		}
+		if l.Embed {
+			if embedded, ok := s.embedNote(c, p, l.Fragment); ok {
+				return embedded
+			}
+		}
+		s.addBacklink(p, c.out)
+		return markdownLink(display, s.noteURL(c.out, p, l.Fragment))
+	}
+	if a, ok := s.assets.resolve(l.Target); ok {
+		return s.assetLink(c, a, l)
+	}
+	return s.skip(c, l, line, "dead", display)
+}
+
+// wikilinkDisplay returns the text Obsidian shows for a link without an
+// alias: "note", "note > Heading" or "Heading" for same-note links.
+func wikilinkDisplay(l wikilink) string {
+	name, isBlock := fragmentTarget(l.Fragment)
+	if isBlock {
+		name = "^" + name
+	}
+	switch {
+	case l.Fragment == "":
+		return l.Target
+	case l.Target == "":
+		return name
+	default:
+		return l.Target + " > " + name
+	}
+}
+
+// embedNote inlines the note or section an embed points to. ok is false
+// when it should be a link instead: embeds are linked, the embed is a cycle
+// or too deep, or the section doesn't exist.
+func (s *exportSite) embedNote(c exportContext, p, fragment string) (string, bool) {
+	if s.linkEmbeds || c.stack[p] || c.depth >= exportMaxEmbedDepth {
+		return "", false
+	}
+	note := s.notes[p]
+	section, firstLine, ok := noteSection(note.Content, fragment)
+	if !ok {
+		return "", false
+	}
+
+	c.stack[p] = true
+	defer delete(c.stack, p)
+	body := strings.TrimSpace(s.convertText(exportContext{
+		src: note, path: p, out: c.out, depth: c.depth + 1, stack: c.stack,
+	}, section, firstLine))
+	s.addBacklink(p, c.out)
+
+	if s.html {
+		return fmt.Sprintf("\n\n<div class=\"embed\">\n\n%s\n\n<p class=\"embed-source\"><a href=\"%s\">%s</a></p>\n\n</div>\n\n",
+			body, html.EscapeString(s.noteURL(c.out, p, fragment)), html.EscapeString(s.title(p))), true
+	}
+	return body, true
+}
+
+// assetLink converts a link or embed of a vault file.
+func (s *exportSite) assetLink(c exportContext, asset string, l wikilink) string {
+	s.usedAssets[asset] = true
+	href := s.assetURL(c.out, asset)
+	if !l.Embed {
+		display := l.Alias
+		if !l.HasAlias {
+			display = pathBase(asset)
+		}
+		return markdownLink(display, href)
+	}
+
+	// The alias of an embed is alt text, a size, or "alt|size"
+	alt, width, height := l.Alias, "", ""
+	if idx := strings.LastIndex(l.Alias, "|"); idx != -1 || embedSizeRegex.MatchString(l.Alias) {
+		if m := embedSizeRegex.FindStringSubmatch(l.Alias[idx+1:]); m != nil {
+			alt, width, height = l.Alias[:max(idx, 0)], m[1], m[2]
+		}
+	}
+	if alt == "" {
+		alt = strings.TrimSuffix(pathBase(asset), path.Ext(asset))
+	}
+
+	switch strings.ToLower(path.Ext(asset)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp", ".bmp", ".avif":
+		if s.html && width != "" {
+			size := fmt.Sprintf(` width="%s"`, width)
+			if height != "" {
+				size += fmt.Sprintf(` height="%s"`, height)
+			}
+			return fmt.Sprintf(`<img src="%s" alt="%s"%s />`, html.EscapeString(href), html.EscapeString(alt), size)
+		}
+		return "![" + escapeLinkText(alt) + "](" + href + ")"
+	case ".mp3", ".wav", ".m4a", ".ogg", ".flac":
+		if s.html {
+			return fmt.Sprintf(`<audio controls src="%s"></audio>`, html.EscapeString(href))
+		}
+	case ".mp4", ".webm", ".mov":
+		if s.html {
+			return fmt.Sprintf(`<video controls src="%s"></video>`, html.EscapeString(href))
+		}
+	}
+	return markdownLink(pathBase(asset), href)
+}
+
+// convertMarkdownLink rewrites a [text](dest) link whose destination is a
+// note or file in the vault. ok is false for external and unresolved
+// destinations, which are left unchanged.
+func (s *exportSite) convertMarkdownLink(c exportContext, text string, m []int, line int) (string, bool) {
+	ml := parseMarkdownLink(text, m)
+	if vault.IsExternalLink(ml.Dest) || strings.HasPrefix(ml.Dest, "#") {
+		return "", false
+	}
+	target, fragment, label, title := ml.Target, ml.Fragment, ml.Label, ml.Title
+	prefix := ""
+	if ml.Embed {
+		prefix = "!"
+	}
+
+	p, asset, ok := s.resolveMarkdownTarget(c.path, target)
+	switch {
+	case ok && p != "":
+		if !s.selected[p] {
+			return s.skip(c, wikilink{Target: target, Fragment: fragment, Alias: label, HasAlias: true}, line, "unpublished", label), true
+		}
+		s.addBacklink(p, c.out)
+		return fmt.Sprintf("%s[%s](%s%s)", prefix, label, s.noteURL(c.out, p, fragment), title), true
+	case ok:
+		s.usedAssets[asset] = true
+		return fmt.Sprintf("%s[%s](%s%s)", prefix, label, s.assetURL(c.out, asset), title), true
+	case strings.EqualFold(path.Ext(target), ".md"):
+		return s.skip(c, wikilink{Target: target, Fragment: fragment, Alias: label, HasAlias: true}, line, "dead", label), true
+	}
+	return "", false
+}
+
+// markdownLinkMatch is a parsed markdownLinkRegex match.
+type markdownLinkMatch struct {
+	Embed    bool
+	Label    string
+	Dest     string // As written
+	Target   string // Dest before any #, unescaped
+	Fragment string // "#..." unescaped, or ""
+	Title    string // ` "title"` as written, or ""
+}
+
+// parseMarkdownLink parses match m of markdownLinkRegex in text.
+func parseMarkdownLink(text string, m []int) markdownLinkMatch {
+	ml := markdownLinkMatch{Embed: m[3] > m[2], Label: text[m[4]:m[5]], Title: text[m[10]:m[11]]}
+	if m[6] != -1 {
+		ml.Dest = text[m[6]:m[7]]
+	} else {
+		ml.Dest = text[m[8]:m[9]]
+	}
+	target, fragment, _ := strings.Cut(ml.Dest, "#")
+	if u, err := url.PathUnescape(target); err == nil {
+		target = u
+	}
+	if u, err := url.PathUnescape(fragment); err == nil {
+		fragment = u
+	}
+	ml.Target = target
+	if fragment != "" {
+		ml.Fragment = "#" + fragment
+	}
+	return ml
+}
+
+// resolveMarkdownTarget resolves the decoded destination of a markdown link
+// in note src to a note path or an asset, trying it relative to the note and
+// then from the vault root.
+func (s *exportSite) resolveMarkdownTarget(src, target string) (notePath, asset string, ok bool) {
+	candidates := []string{path.Join(path.Dir(src), target), strings.TrimPrefix(target, "/")}
+	if ext := strings.ToLower(path.Ext(target)); ext == ".md" || ext == "" {
+		for _, cand := range candidates {
+			if p, ok := s.resolver.resolve(cand); ok {
+				return p, "", true
+			}
+		}
+		return "", "", false
+	}
+	for _, cand := range candidates {
+		if a, ok := s.assets.resolve(cand); ok {
+			return "", a, true
+		}
+	}
+	return "", "", false
+}
+
+// skip records a link that can't be exported and returns what replaces it:
+// the display text, or with "flag" a marked-up span (HTML) or the original
+// link (markdown). Stripped embeds are removed entirely.
+func (s *exportSite) skip(c exportContext, l wikilink, line int, reason, display string) string {
+	link := ExportLink{
+		Source: filepath.ToSlash(c.src.RelPath),
+		Target: l.Target + l.Fragment,
+		Line:   line,
+		Reason: reason,
+	}
+	// Transcluded notes are converted once per embed; report links once
+	if !slices.Contains(s.skipped, link) {
+		s.skipped = append(s.skipped, link)
+	}
+	if s.unpublished == "flag" {
+		if s.html {
+			return fmt.Sprintf(`<span class="%s-link" title="%s">%s</span>`,
+				reason, html.EscapeString(l.Target), html.EscapeString(display))
+		}
+		return l.String()
+	}
+	if l.Embed {
+		return ""
+	}
+	return display
+}
+
+// addBacklink records that out links to p.
+func (s *exportSite) addBacklink(p, out string) {
+	if p == out {
+		return
+	}
+	if s.backlinks[p] == nil {
+		s.backlinks[p] = make(map[string]bool)
+	}
+	s.backlinks[p][out] = true
+}
+
+// noteURL returns the URL of note p (and fragment) relative to note from.
+func (s *exportSite) noteURL(from, p, fragment string) string {
+	if s.bundle != nil {
+		return s.bundle.noteURL(p, fragment)
+	}
+	anchor := ""
+	if name, isBlock := fragmentTarget(fragment); name != "" {
+		switch {
+		case !isBlock:
+			anchor = "#" + headingSlug(name)
+		case s.html:
+			anchor = "#^" + name
+		}
+	}
+	if p == from && anchor != "" {
+		return anchor
+	}
+	return relURL(s.outPath(from), s.outPath(p)) + anchor
+}
+
+// assetURL returns the URL of a vault file from note from.
+func (s *exportSite) assetURL(from, asset string) string {
+	if s.bundle != nil {
+		return s.bundle.assetURL(asset)
+	}
+	return relURL(s.outPath(from), asset)
+}
+
+// blockAnchor returns the HTML id of block id in note out.
+func (s *exportSite) blockAnchor(out, id string) string {
+	if s.bundle != nil {
+		return s.bundle.chapters[out].ID + "-^" + id
+	}
+	return "^" + id
+}
+
+// relURL returns the URL of output file to relative to output file from,
+// with each path segment escaped.
+func relURL(from, to string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(from)), filepath.FromSlash(to))
+	if err != nil {
+		rel = to
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// markdownLink returns [text](href).
+func markdownLink(text, href string) string {
+	return "[" + escapeLinkText(text) + "](" + href + ")"
+}
+
+// escapeLinkText escapes brackets in link text.
+func escapeLinkText(text string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+}
+
+// headingSlug returns the anchor for a heading the way GitHub generates
+// them: lowercase, spaces to hyphens, punctuation removed.
+func headingSlug(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// prepareExportDir creates or empties the output directory. A non-empty
+// directory is only emptied when a previous export created it.
+func prepareExportDir(dir, absVaultPath string) error {
+	if isPathWithinVault(dir, absVaultPath) || isPathWithinVault(absVaultPath, dir) {
+		return fmt.Errorf("output directory must be outside the vault: %s", dir)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(entries) > 0 {
+		if _, err := os.Stat(filepath.Join(dir, exportMarker)); err != nil {
+			return fmt.Errorf("output directory is not empty and was not created by export: %s", dir)
+		}
+		for _, e := range entries {
+			if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, exportMarker), []byte("Created by obsidian-cli export. Contents are replaced on every export.\n"), 0644)
+}
+
+// writeExportFile writes an output file, creating its directory.
+func writeExportFile(dir, rel, content string) error {
+	dest := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, []byte(content), 0644)
+}
+
+// copyExportAssets copies the assets used by exported notes.
+func (s *exportSite) copyExportAssets(dir string) error {
+	for _, asset := range sortedKeys(s.usedAssets) {
+		src, err := os.Open(filepath.Join(s.absPath, filepath.FromSlash(asset)))
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(dir, filepath.FromSlash(asset))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			src.Close()
+			return err
+		}
+		out, err := os.Create(dest)
+		if err == nil {
+			_, err = io.Copy(out, src)
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		src.Close()
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", asset, err)
+		}
+	}
+	return nil
+}
+
+// printExportResult prints the summary of an export.
+func printExportResult(result *ExportResult, mode string) {
+	fmt.Printf("%s Exported %d notes and %d assets to %s\n", colors.Green("✓"), result.Notes, result.Assets, result.Output)
+	if len(result.Skipped) == 0 {
+		fmt.Println()
+		return
+	}
+	action := "removed"
+	if mode == "flag" {
+		action = "flagged"
+	}
+	fmt.Printf("\n%s %d links to notes outside the export %s\n", colors.Yellow("!"), len(result.Skipped), action)
+	for _, l := range result.Skipped {
+		fmt.Printf("  %s:%d %s %s %s\n", l.Source, l.Line, colors.Dim("→"), l.Target, colors.Dim("("+l.Reason+")"))
+	}
+	fmt.Println()
+}
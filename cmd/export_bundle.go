@@ -0,0 +1,461 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+var (
+	exportBundleDepth    int
+	exportBundleDocument string
+	exportBundleOutput   string
+	exportBundleTitle    string
+)
+
+var exportBundleCmd = &cobra.Command{
+	Use:   "bundle <note>",
+	Short: "Export a note and the notes it links to as one document",
+	Long: `Gathers a note and the notes it links to, up to --depth links away, into
+one self-contained file: an EPUB book, a single HTML page or a single
+markdown file.
+
+  - The root note comes first, then linked notes in the order they are
+    reached (breadth first); each note is a chapter
+  - Embedded notes are included at the depth of the note embedding them,
+    so transclusions are never cut off
+  - Links between chapters become internal anchors; links to notes
+    outside the bundle are reduced to their text
+  - Images and other files are embedded: as EPUB resources, or as data
+    URIs in HTML and markdown
+  - Notes with publish: false in their frontmatter are left out
+
+The output defaults to the root note's title with the format's extension
+in the current directory, and must be outside the vault.
+
+Examples:
+  obsidian-cli export bundle "Project Alpha" --vault ~/notes --format epub
+  obsidian-cli export bundle Home --vault ~/notes --depth 2 --format html -o ~/home.html
+  obsidian-cli export bundle Home --vault ~/notes --depth 0 --format md --title "Handbook"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExportBundle,
+}
+
+func init() {
+	exportCmd.AddCommand(exportBundleCmd)
+	exportBundleCmd.Flags().IntVar(&exportBundleDepth, "depth", 1, "Follow links this many levels from the root note")
+	exportBundleCmd.Flags().StringVar(&exportBundleDocument, "format", "epub", "Document format: epub, html, md")
+	exportBundleCmd.Flags().StringVarP(&exportBundleOutput, "output", "o", "", "Output file (default: <title>.<format> in the current directory)")
+	exportBundleCmd.Flags().StringVar(&exportBundleTitle, "title", "", "Document title (default: root note title)")
+}
+
+// bundleChapter is a note in a bundle.
+type bundleChapter struct {
+	Path    string
+	ID      string // Anchor of the chapter, prefixed to its heading and block anchors
+	File    string // EPUB content document, "" for single-file formats
+	Title   string
+	Heading bool // Add the title as a heading; false when the note starts with it
+	Content template.HTML
+}
+
+// exportBundle maps notes and assets to their place in a single document.
+type exportBundle struct {
+	format   string // epub, html or md
+	absPath  string
+	chapters map[string]*bundleChapter
+	media    map[string]string // Asset -> EPUB resource or data URI
+	err      error             // First asset that couldn't be read
+}
+
+// noteURL returns the internal URL of note p and fragment.
+func (b *exportBundle) noteURL(p, fragment string) string {
+	ch := b.chapters[p]
+	anchor := ch.ID
+	name, isBlock := fragmentTarget(fragment)
+	switch {
+	case name == "":
+	case b.format == "md" && isBlock:
+		// Block IDs are removed from markdown; link the chapter
+	case isBlock:
+		anchor += "-^" + name
+	default:
+		anchor += "-" + headingSlug(name)
+	}
+	return ch.File + "#" + anchor
+}
+
+// assetURL returns the URL of an embedded vault file: a resource in the
+// EPUB, numbered by first use, or a data URI.
+func (b *exportBundle) assetURL(asset string) string {
+	if u, ok := b.media[asset]; ok {
+		return u
+	}
+	ext := strings.ToLower(path.Ext(asset))
+	var u string
+	if b.format == "epub" {
+		u = fmt.Sprintf("media/%03d%s", len(b.media)+1, ext)
+	} else {
+		data, err := os.ReadFile(filepath.Join(b.absPath, filepath.FromSlash(asset)))
+		if err != nil {
+			if b.err == nil {
+				b.err = fmt.Errorf("failed to read %s: %w", asset, err)
+			}
+			return ""
+		}
+		u = "data:" + bundleMediaType(ext) + ";base64," + base64.StdEncoding.EncodeToString(data)
+	}
+	b.media[asset] = u
+	return u
+}
+
+// bundleMediaType returns the media type of a file extension.
+func bundleMediaType(ext string) string {
+	if t := mime.TypeByExtension(ext); t != "" {
+		t, _, _ = strings.Cut(t, ";")
+		return t
+	}
+	return "application/octet-stream"
+}
+
+func runExportBundle(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	ext := map[string]string{"epub": ".epub", "html": ".html", "md": ".md"}[exportBundleDocument]
+	if ext == "" {
+		return fmt.Errorf("invalid --format %q (expected epub, html or md)", exportBundleDocument)
+	}
+	if exportBundleDepth < 0 {
+		return fmt.Errorf("--depth must be 0 or more")
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+
+	printScanHeader("Bundling " + args[0])
+
+	rootFile, err := findNoteFile(absPath, args[0])
+	if err != nil {
+		return err
+	}
+	site, err := newExportSite(absPath, &exportSelection{}, exportBundleDocument != "md")
+	if err != nil {
+		return err
+	}
+	root := strings.TrimSuffix(filepath.ToSlash(mustRelPath(absPath, rootFile)), ".md")
+	if !site.selected[root] {
+		return fmt.Errorf("note has publish: false: %s", args[0])
+	}
+
+	title := exportBundleTitle
+	if title == "" {
+		title = site.title(root)
+	}
+	out := exportBundleOutput
+	if out == "" {
+		out = strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(title) + ext
+	}
+	if out, err = filepath.Abs(out); err != nil {
+		return err
+	}
+	if isPathWithinVault(out, absPath) {
+		return fmt.Errorf("output file must be outside the vault: %s", out)
+	}
+
+	paths := site.bundleNotes(root, exportBundleDepth)
+	bundle := &exportBundle{
+		format:   exportBundleDocument,
+		absPath:  absPath,
+		chapters: make(map[string]*bundleChapter, len(paths)),
+		media:    make(map[string]string),
+	}
+	site.bundle = bundle
+	site.selected = make(map[string]bool, len(paths))
+	chapters := make([]*bundleChapter, len(paths))
+	for i, p := range paths {
+		ch := &bundleChapter{Path: p, ID: fmt.Sprintf("ch%d", i+1), Title: site.title(p)}
+		if bundle.format == "epub" {
+			ch.File = fmt.Sprintf("ch%03d.xhtml", i+1)
+		}
+		bundle.chapters[p] = ch
+		chapters[i] = ch
+		site.selected[p] = true
+	}
+
+	bodies := make([]string, len(chapters))
+	for i, ch := range chapters {
+		bodies[i] = site.convertNote(ch.Path)
+		first, _, _ := strings.Cut(strings.TrimSpace(bodies[i]), "\n")
+		ch.Heading = !strings.EqualFold(strings.TrimSpace(first), "# "+ch.Title)
+	}
+	if bundle.err != nil {
+		return bundle.err
+	}
+
+	switch bundle.format {
+	case "md":
+		err = writeMarkdownBundle(out, title, chapters, bodies)
+	default:
+		for i, ch := range chapters {
+			md := newExportMarkdown(bundle.format == "epub", ch.ID+"-")
+			content, err := renderExportMarkdown(md, bodies[i], ch.ID+"-")
+			if err != nil {
+				return fmt.Errorf("failed to render %s: %w", site.notes[ch.Path].RelPath, err)
+			}
+			ch.Content = template.HTML(content)
+		}
+		if bundle.format == "html" {
+			err = writeHTMLBundle(out, title, chapters)
+		} else {
+			err = site.writeEPUBBundle(out, title, chapters)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, ch := range chapters {
+		fmt.Printf("  %s %s\n", colors.Dim(fmt.Sprintf("%2d.", i+1)), ch.Title)
+	}
+	fmt.Println()
+	printExportResult(&ExportResult{Output: out, Notes: len(chapters), Assets: len(site.usedAssets), Skipped: site.skipped}, "strip")
+	return nil
+}
+
+// bundleNotes returns the notes to bundle in chapter order: root, then the
+// notes it links to breadth first, up to depth links away. Embeds don't
+// count towards the depth. Only selected notes are followed.
+func (s *exportSite) bundleNotes(root string, depth int) []string {
+	type queued struct {
+		path  string
+		depth int
+	}
+	order := []string{root}
+	best := map[string]int{root: 0}
+	queue := []queued{{root, 0}}
+	for len(queue) > 0 {
+		q := queue[0]
+		queue = queue[1:]
+		if q.depth > best[q.path] {
+			continue // Reached again by a shorter path
+		}
+		for _, l := range s.noteLinks(q.path) {
+			d := q.depth
+			if !l.embed {
+				d++
+			}
+			if d > depth || !s.selected[l.path] {
+				continue
+			}
+			prev, seen := best[l.path]
+			if seen && prev <= d {
+				continue
+			}
+			if !seen {
+				order = append(order, l.path)
+			}
+			best[l.path] = d
+			queue = append(queue, queued{l.path, d})
+		}
+	}
+	return order
+}
+
+// bundleLink is a link from one note to another.
+type bundleLink struct {
+	path  string
+	embed bool
+}
+
+// noteLinks returns the notes note p links to or embeds, in order of
+// appearance, ignoring code and comments.
+func (s *exportSite) noteLinks(p string) []bundleLink {
+	type found struct {
+		pos int
+		bundleLink
+	}
+	var links []found
+	mapOutsideFences(s.notes[p].Body, 1, func(chunk string, _ int) string {
+		chunk = obsidianCommentRegex.ReplaceAllStringFunc(chunk, func(m string) string {
+			return strings.Repeat(" ", len(m))
+		})
+		offset := len(links)
+		mapOutsideInlineCode(chunk, 1, func(piece string, _ int) string {
+			for _, l := range parseWikilinks(piece) {
+				if target, ok := s.resolver.resolve(l.Target); ok && l.Target != "" {
+					links = append(links, found{l.Start, bundleLink{target, l.Embed}})
+				}
+			}
+			for _, m := range markdownLinkRegex.FindAllStringSubmatchIndex(piece, -1) {
+				ml := parseMarkdownLink(piece, m)
+				if target, _, ok := s.resolveMarkdownTarget(p, ml.Target); ok && target != "" {
+					links = append(links, found{m[0], bundleLink{target, ml.Embed}})
+				}
+			}
+			return piece
+		})
+		// Wikilinks and markdown links were collected separately
+		added := links[offset:]
+		sort.SliceStable(added, func(i, j int) bool { return added[i].pos < added[j].pos })
+		return chunk
+	})
+
+	result := make([]bundleLink, 0, len(links))
+	for _, l := range links {
+		if l.path != p {
+			result = append(result, l.bundleLink)
+		}
+	}
+	return result
+}
+
+// writeMarkdownBundle writes the chapters as one markdown file with a
+// table of contents.
+func writeMarkdownBundle(out, title string, chapters []*bundleChapter, bodies []string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n## Contents\n\n", title)
+	for i, ch := range chapters {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, markdownLink(ch.Title, "#"+ch.ID))
+	}
+	for i, ch := range chapters {
+		fmt.Fprintf(&b, "\n<a id=\"%s\"></a>\n\n", ch.ID)
+		if ch.Heading {
+			fmt.Fprintf(&b, "# %s\n\n", ch.Title)
+		}
+		fmt.Fprintf(&b, "%s\n", strings.TrimSpace(anchorMarkdownHeadings(bodies[i], ch.ID+"-")))
+	}
+	return os.WriteFile(out, []byte(b.String()), 0644)
+}
+
+// anchorMarkdownHeadings adds an <a id> anchor before each heading of
+// source, with the ID the heading gets in the HTML and EPUB bundles.
+// Renderers slug headings their own way, so links between chapters point
+// at these anchors instead.
+func anchorMarkdownHeadings(source, idPrefix string) string {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(&exportHeadingIDs{prefix: idPrefix, used: make(map[string]bool)}))
+	doc := newExportMarkdown(false, idPrefix).Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var b strings.Builder
+	last := 0
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok || h.Lines().Len() == 0 {
+			return ast.WalkContinue, nil
+		}
+		id, _ := h.AttributeString("id")
+		start := bytes.LastIndexByte(src[:h.Lines().At(0).Start], '\n') + 1
+		// Keep the anchor inside the blockquote or callout of the heading
+		quote := len(src[start:]) - len(bytes.TrimLeft(src[start:], "> "))
+		b.Write(src[last:start])
+		fmt.Fprintf(&b, "%s<a id=\"%s\"></a>\n", src[start:start+quote], id)
+		last = start
+		return ast.WalkSkipChildren, nil
+	})
+	b.Write(src[last:])
+	return b.String()
+}
+
+var bundlePageTemplate = template.Must(template.New("bundle").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+{{.Style}}</style>
+</head>
+<body>
+<main>
+<h1 class="page-title">{{.Title}}</h1>
+<nav class="toc">
+<h2>Contents</h2>
+<ol>
+{{- range .Chapters}}
+<li><a href="#{{.ID}}">{{.Title}}</a></li>
+{{- end}}
+</ol>
+</nav>
+{{- range .Chapters}}
+<section class="chapter" id="{{.ID}}">
+{{- if .Heading}}
+<h1>{{.Title}}</h1>
+{{- end}}
+{{.Content}}
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+`))
+
+// writeHTMLBundle writes the chapters as one HTML page with a table of
+// contents and the stylesheet inlined.
+func writeHTMLBundle(out, title string, chapters []*bundleChapter) error {
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	err = bundlePageTemplate.Execute(f, struct {
+		Title    string
+		Style    template.CSS
+		Chapters []*bundleChapter
+	}{title, template.CSS(exportStylesheet), chapters})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeEPUBBundle writes the chapters as an EPUB book.
+func (s *exportSite) writeEPUBBundle(out, title string, chapters []*bundleChapter) error {
+	book := epubBook{Title: title, Chapters: chapters}
+
+	// The newest note is the modification date, so unchanged notes give
+	// an identical file
+	ids := make([]string, 0, len(chapters))
+	for _, ch := range chapters {
+		ids = append(ids, ch.Path)
+		if info, err := os.Stat(s.notes[ch.Path].Path); err == nil && info.ModTime().After(book.Modified) {
+			book.Modified = info.ModTime()
+		}
+	}
+	book.Modified = book.Modified.UTC().Truncate(time.Second)
+	book.ID = "urn:obsidian-cli:" + contentHash([]byte(strings.Join(ids, "\n")))[:32]
+
+	assets := sortedKeys(s.bundle.media)
+	sort.Slice(assets, func(i, j int) bool { return s.bundle.media[assets[i]] < s.bundle.media[assets[j]] })
+	for _, asset := range assets {
+		data, err := os.ReadFile(filepath.Join(s.absPath, filepath.FromSlash(asset)))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", asset, err)
+		}
+		href := s.bundle.media[asset]
+		book.Media = append(book.Media, epubResource{Href: href, Type: bundleMediaType(path.Ext(href)), Data: data})
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	err = writeEPUB(f, book)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
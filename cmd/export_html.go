@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+var (
+	exportHTMLSelection   exportSelection
+	exportHTMLOutput      string
+	exportHTMLUnpublished string
+	exportHTMLTitle       string
+	exportHTMLFormat      string
+)
+
+var exportHTMLCmd = &cobra.Command{
+	Use:   "html",
+	Short: "Export notes as a static HTML site",
+	Long: `Renders the selected notes to a static HTML site in --output.
+
+  - Wikilinks and markdown links to exported notes become relative links,
+    with headings and blocks as anchors
+  - Embedded notes and sections are rendered inline (up to 5 levels deep)
+  - Embedded and linked files are copied, keeping their vault paths
+  - Each page lists its tags and the exported notes that link to it
+  - index.html lists every page by folder and tags.html indexes tags
+  - Links to notes outside the export are reduced to their text
+    (--unpublished strip) or marked with a CSS class (--unpublished flag)
+  - %%comments%% are removed and callouts become blockquotes
+
+The output is deterministic, so exports can be diffed. The output
+directory is replaced on every export; a non-empty directory that wasn't
+created by export is refused.
+
+Examples:
+  obsidian-cli export html --vault ~/notes -o ~/site
+  obsidian-cli export html --vault ~/notes -o ~/site --published
+  obsidian-cli export html --vault ~/notes -o ~/site --folder Public --tag guide
+  obsidian-cli export html --vault ~/notes -o ~/site --unpublished flag`,
+	RunE: runExportHTML,
+}
+
+func init() {
+	exportCmd.AddCommand(exportHTMLCmd)
+	addExportSelectionFlags(exportHTMLCmd, &exportHTMLSelection)
+	exportHTMLCmd.Flags().StringVarP(&exportHTMLOutput, "output", "o", "", "Output directory (required)")
+	exportHTMLCmd.Flags().StringVar(&exportHTMLUnpublished, "unpublished", "strip", "Links to notes outside the export: strip, flag")
+	exportHTMLCmd.Flags().StringVar(&exportHTMLTitle, "title", "", "Site title (default: vault folder name)")
+	exportHTMLCmd.Flags().StringVar(&exportHTMLFormat, "format", "text", "Output format: text, json")
+	_ = exportHTMLCmd.MarkFlagRequired("output")
+}
+
+// exportPage is the data of an HTML page.
+type exportPage struct {
+	SiteTitle string
+	Title     string
+	Root      string // Relative URL of the site root, "" or "../.."
+	IndexURL  string
+	TagsURL   string
+	Tags      []exportPageLink
+	Content   template.HTML
+	Backlinks []exportPageLink
+}
+
+type exportPageLink struct {
+	Title string
+	URL   string
+}
+
+var exportPageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} - {{.SiteTitle}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<nav><a href="{{.IndexURL}}">{{.SiteTitle}}</a> · <a href="{{.TagsURL}}">Tags</a></nav>
+<main>
+<h1 class="page-title">{{.Title}}</h1>
+{{- if .Tags}}
+<p class="tags">{{range .Tags}}<a class="tag" href="{{.URL}}">#{{.Title}}</a> {{end}}</p>
+{{- end}}
+{{.Content}}
+{{- if .Backlinks}}
+<section class="backlinks">
+<h2>Backlinks</h2>
+<ul>
+{{- range .Backlinks}}
+<li><a href="{{.URL}}">{{.Title}}</a></li>
+{{- end}}
+</ul>
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+`))
+
+const exportStylesheet = `body { max-width: 46rem; margin: 2rem auto; padding: 0 1rem; font: 16px/1.6 system-ui, sans-serif; color: #222; }
+nav { margin-bottom: 2rem; font-size: 0.9rem; }
+a { color: #6c3fc5; }
+pre, code { background: #f4f4f4; border-radius: 3px; }
+pre { padding: 0.75rem; overflow-x: auto; }
+blockquote { margin: 0; padding-left: 1rem; border-left: 3px solid #ccc; color: #444; }
+img { max-width: 100%; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ddd; padding: 0.25rem 0.5rem; }
+.tags .tag, a.tag { font-size: 0.85rem; text-decoration: none; }
+.embed { border-left: 3px solid #6c3fc5; padding-left: 1rem; margin: 1rem 0; }
+.embed-source { font-size: 0.8rem; }
+.unpublished-link, .dead-link { color: #999; text-decoration: underline dotted; }
+.backlinks { margin-top: 3rem; border-top: 1px solid #ddd; font-size: 0.9rem; }
+`
+
+func runExportHTML(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	if exportHTMLUnpublished != "strip" && exportHTMLUnpublished != "flag" {
+		return fmt.Errorf("invalid --unpublished %q (expected strip or flag)", exportHTMLUnpublished)
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	outDir, err := filepath.Abs(exportHTMLOutput)
+	if err != nil {
+		return err
+	}
+	siteTitle := exportHTMLTitle
+	if siteTitle == "" {
+		siteTitle = filepath.Base(absPath)
+	}
+
+	if exportHTMLFormat == "text" {
+		printScanHeader("Exporting vault")
+	}
+
+	site, err := newExportSite(absPath, &exportHTMLSelection, true)
+	if err != nil {
+		return err
+	}
+	site.unpublished = exportHTMLUnpublished
+	paths := site.selectedPaths()
+	if len(paths) == 0 {
+		return fmt.Errorf("no notes match the selection")
+	}
+
+	if err := prepareExportDir(outDir, absPath); err != nil {
+		return err
+	}
+
+	// Generated pages give way to notes with the same output path
+	taken := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		taken[strings.ToLower(site.outPath(p))] = true
+	}
+	generatedPath := func(name string) string {
+		for taken[strings.ToLower(name)] {
+			name = "_" + name
+		}
+		taken[strings.ToLower(name)] = true
+		return name
+	}
+	indexPage, tagsPage := generatedPath("index.html"), generatedPath("tags.html")
+	site.tagsPage = tagsPage
+
+	// Convert everything first: backlinks are only known afterwards
+	bodies := make(map[string]string, len(paths))
+	for _, p := range paths {
+		bodies[p] = site.convertNote(p)
+	}
+
+	md := newExportMarkdown(false, "")
+	newPage := func(out, title string) exportPage {
+		root := strings.Repeat("../", strings.Count(out, "/"))
+		return exportPage{
+			SiteTitle: siteTitle,
+			Title:     title,
+			Root:      root,
+			IndexURL:  relURL(out, indexPage),
+			TagsURL:   relURL(out, tagsPage),
+		}
+	}
+
+	tagNotes := make(map[string][]string)
+	for _, p := range paths {
+		out := site.outPath(p)
+		page := newPage(out, site.title(p))
+		for _, tag := range exportNoteTags(site.notes[p]) {
+			tagNotes[tag] = append(tagNotes[tag], p)
+			page.Tags = append(page.Tags, exportPageLink{tag, relURL(out, tagsPage) + "#" + headingSlug(tag)})
+		}
+		for _, src := range sortedKeys(site.backlinks[p]) {
+			page.Backlinks = append(page.Backlinks, exportPageLink{site.title(src), relURL(out, site.outPath(src))})
+		}
+		sort.SliceStable(page.Backlinks, func(i, j int) bool { return page.Backlinks[i].Title < page.Backlinks[j].Title })
+
+		content, err := renderExportMarkdown(md, bodies[p], "")
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", site.notes[p].RelPath, err)
+		}
+		page.Content = template.HTML(content)
+		if err := writeExportPage(outDir, out, page); err != nil {
+			return err
+		}
+	}
+
+	// Index of pages by folder
+	var index strings.Builder
+	lastFolder := ""
+	for _, p := range exportPathsByFolder(paths) {
+		if folder := path.Dir(p); folder != "." && folder != lastFolder {
+			fmt.Fprintf(&index, "<h2>%s</h2>\n", template.HTMLEscapeString(folder))
+			lastFolder = folder
+		}
+		fmt.Fprintf(&index, "<p><a href=\"%s\">%s</a></p>\n",
+			template.HTMLEscapeString(site.outPath(p)), template.HTMLEscapeString(site.title(p)))
+	}
+	page := newPage(indexPage, siteTitle)
+	page.Content = template.HTML(index.String())
+	if err := writeExportPage(outDir, indexPage, page); err != nil {
+		return err
+	}
+
+	// Tag index
+	var tags strings.Builder
+	for _, tag := range sortedKeys(tagNotes) {
+		fmt.Fprintf(&tags, "<h2 id=\"%s\">#%s</h2>\n<ul>\n", template.HTMLEscapeString(headingSlug(tag)), template.HTMLEscapeString(tag))
+		for _, p := range tagNotes[tag] {
+			fmt.Fprintf(&tags, "<li><a href=\"%s\">%s</a></li>\n",
+				template.HTMLEscapeString(relURL(tagsPage, site.outPath(p))), template.HTMLEscapeString(site.title(p)))
+		}
+		tags.WriteString("</ul>\n")
+	}
+	page = newPage(tagsPage, "Tags")
+	page.Content = template.HTML(tags.String())
+	if err := writeExportPage(outDir, tagsPage, page); err != nil {
+		return err
+	}
+
+	if err := writeExportFile(outDir, "style.css", exportStylesheet); err != nil {
+		return err
+	}
+	if err := site.copyExportAssets(outDir); err != nil {
+		return err
+	}
+
+	result := &ExportResult{Output: outDir, Notes: len(paths), Assets: len(site.usedAssets), Skipped: site.skipped}
+	if result.Skipped == nil {
+		result.Skipped = []ExportLink{}
+	}
+	if exportHTMLFormat == "json" {
+		return encodeJSON(cmd, result)
+	}
+	printExportResult(result, exportHTMLUnpublished)
+	return nil
+}
+
+// exportNoteTags returns a note's tags, lowercase and unique, in order.
+func exportNoteTags(note *noteFile) []string {
+	seen := make(map[string]bool)
+	for _, occ := range noteTagOccurrences(note) {
+		seen[strings.ToLower(occ.Tag)] = true
+	}
+	return sortedKeys(seen)
+}
+
+// exportPathsByFolder orders note paths with root notes first, then by folder.
+func exportPathsByFolder(paths []string) []string {
+	sorted := append([]string(nil), paths...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		di, dj := path.Dir(sorted[i]), path.Dir(sorted[j])
+		if (di == ".") != (dj == ".") {
+			return di == "."
+		}
+		if di != dj {
+			return di < dj
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+// writeExportPage renders a page to out.
+func writeExportPage(dir, out string, page exportPage) error {
+	var buf bytes.Buffer
+	if err := exportPageTemplate.Execute(&buf, page); err != nil {
+		return err
+	}
+	return writeExportFile(dir, out, buf.String())
+}
+
+// newExportMarkdown returns a CommonMark renderer with GitHub extensions
+// and footnotes, whose IDs start with footnotePrefix. Raw HTML is passed
+// through, as Obsidian does.
+func newExportMarkdown(xhtml bool, footnotePrefix string) goldmark.Markdown {
+	options := []renderer.Option{gmhtml.WithUnsafe()}
+	if xhtml {
+		options = append(options, gmhtml.WithXHTML())
+	}
+	return goldmark.New(
+		goldmark.WithExtensions(extension.GFM, extension.NewFootnote(extension.WithFootnoteIDPrefix(footnotePrefix))),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(options...),
+	)
+}
+
+// renderExportMarkdown renders converted markdown to HTML. Heading IDs
+// start with idPrefix.
+func renderExportMarkdown(md goldmark.Markdown, source, idPrefix string) (string, error) {
+	var buf bytes.Buffer
+	ctx := parser.NewContext(parser.WithIDs(&exportHeadingIDs{prefix: idPrefix, used: make(map[string]bool)}))
+	if err := md.Convert([]byte(source), &buf, parser.WithContext(ctx)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Matches a markdown link, for reducing heading text to what is displayed
+var headingLinkRegex = regexp.MustCompile(`!?\[([^\[\]]*)\]\([^()]*\)`)
+
+// exportHeadingIDs generates heading IDs with headingSlug, so that links
+// converted by exportSite point at them. Repeated IDs get -1, -2... suffixes.
+type exportHeadingIDs struct {
+	prefix string
+	used   map[string]bool
+}
+
+func (ids *exportHeadingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := headingSlug(headingLinkRegex.ReplaceAllString(string(value), "$1"))
+	if base == "" {
+		base = "heading"
+	}
+	base = ids.prefix + base
+	id := base
+	for i := 1; ids.used[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	ids.used[id] = true
+	return []byte(id)
+}
+
+func (ids *exportHeadingIDs) Put(value []byte) {
+	ids.used[string(value)] = true
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportMarkdownSelection     exportSelection
+	exportMarkdownOutput        string
+	exportMarkdownUnpublished   string
+	exportMarkdownEmbeds        string
+	exportMarkdownNoFrontmatter bool
+	exportMarkdownFormat        string
+)
+
+var exportMarkdownCmd = &cobra.Command{
+	Use:   "markdown",
+	Short: "Export notes as portable CommonMark",
+	Long: `Writes a copy of the selected notes to --output in plain CommonMark, for
+GitHub wikis, static site generators and other markdown tools.
+
+  - [[note|alias]] becomes [alias](relative/path/note.md), with headings
+    as GitHub-style anchors
+  - ![[image.png|300]] becomes ![image](relative/path/image.png)
+  - ![[note]] and ![[note#Heading]] are inlined (--embeds inline) or
+    become links (--embeds link)
+  - Callouts become blockquotes with a bold title, %%comments%% and
+    ^block-ids are removed
+  - Embedded and linked files are copied, keeping their vault paths
+  - Links to notes outside the export are reduced to their text
+    (--unpublished strip) or left as wikilinks (--unpublished flag)
+
+The output directory is replaced on every export; a non-empty directory
+that wasn't created by export is refused.
+
+Examples:
+  obsidian-cli export markdown --vault ~/notes -o ~/wiki
+  obsidian-cli export markdown --vault ~/notes -o ~/wiki --folder Docs --no-frontmatter
+  obsidian-cli export markdown --vault ~/notes -o ~/wiki --embeds link`,
+	RunE: runExportMarkdown,
+}
+
+func init() {
+	exportCmd.AddCommand(exportMarkdownCmd)
+	addExportSelectionFlags(exportMarkdownCmd, &exportMarkdownSelection)
+	exportMarkdownCmd.Flags().StringVarP(&exportMarkdownOutput, "output", "o", "", "Output directory (required)")
+	exportMarkdownCmd.Flags().StringVar(&exportMarkdownUnpublished, "unpublished", "strip", "Links to notes outside the export: strip, flag")
+	exportMarkdownCmd.Flags().StringVar(&exportMarkdownEmbeds, "embeds", "inline", "Embedded notes: inline, link")
+	exportMarkdownCmd.Flags().BoolVar(&exportMarkdownNoFrontmatter, "no-frontmatter", false, "Drop YAML frontmatter")
+	exportMarkdownCmd.Flags().StringVar(&exportMarkdownFormat, "format", "text", "Output format: text, json")
+	_ = exportMarkdownCmd.MarkFlagRequired("output")
+}
+
+func runExportMarkdown(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	if exportMarkdownUnpublished != "strip" && exportMarkdownUnpublished != "flag" {
+		return fmt.Errorf("invalid --unpublished %q (expected strip or flag)", exportMarkdownUnpublished)
+	}
+	if exportMarkdownEmbeds != "inline" && exportMarkdownEmbeds != "link" {
+		return fmt.Errorf("invalid --embeds %q (expected inline or link)", exportMarkdownEmbeds)
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	outDir, err := filepath.Abs(exportMarkdownOutput)
+	if err != nil {
+		return err
+	}
+
+	if exportMarkdownFormat == "text" {
+		printScanHeader("Exporting vault")
+	}
+
+	site, err := newExportSite(absPath, &exportMarkdownSelection, false)
+	if err != nil {
+		return err
+	}
+	site.unpublished = exportMarkdownUnpublished
+	site.linkEmbeds = exportMarkdownEmbeds == "link"
+	paths := site.selectedPaths()
+	if len(paths) == 0 {
+		return fmt.Errorf("no notes match the selection")
+	}
+	if err := prepareExportDir(outDir, absPath); err != nil {
+		return err
+	}
+
+	for _, p := range paths {
+		note := site.notes[p]
+		content := site.convertNote(p)
+		if note.HasFrontmatter && !exportMarkdownNoFrontmatter {
+			content = note.Content[:len(note.Content)-len(note.Body)] + content
+		}
+		if err := writeExportFile(outDir, site.outPath(p), content); err != nil {
+			return err
+		}
+	}
+	if err := site.copyExportAssets(outDir); err != nil {
+		return err
+	}
+
+	result := &ExportResult{Output: outDir, Notes: len(paths), Assets: len(site.usedAssets), Skipped: site.skipped}
+	if result.Skipped == nil {
+		result.Skipped = []ExportLink{}
+	}
+	if exportMarkdownFormat == "json" {
+		return encodeJSON(cmd, result)
+	}
+	printExportResult(result, exportMarkdownUnpublished)
+	return nil
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestExportConvert tests link rewriting, transclusion and unpublished links
+func TestExportConvert(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pub/a.md":     "---\npublish: true\n---\n[[b#Two|see b]] [[private]] ![[b#Two]] `[[code]]` ![[img.png|200]]\n",
+		"pub/sub/b.md": "---\npublish: true\n---\n# One\n## Two\ntext ![[a]]\n",
+		"private.md":   "secret\n",
+		"img.png":      "png",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	site, err := newExportSite(dir, &exportSelection{Published: true}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := site.selectedPaths(); len(got) != 2 {
+		t.Fatalf("selected = %v, want pub/a and pub/sub/b", got)
+	}
+
+	got := site.convertNote("pub/a")
+	for _, want := range []string{
+		"[see b](sub/b.md#two)",  // Relative link with heading anchor
+		" private ",              // Unpublished link stripped to its text
+		"## Two\ntext [a](a.md)", // Section inlined; the cycle back to a is a link
+		"`[[code]]`",             // Code unchanged
+		"![img](../img.png)",     // Image embed with size dropped
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("converted note missing %q:\n%s", want, got)
+		}
+	}
+	if len(site.skipped) != 1 || site.skipped[0].Target != "private" || site.skipped[0].Line != 4 {
+		t.Errorf("skipped = %+v, want private on line 4", site.skipped)
+	}
+	if !site.backlinks["pub/sub/b"]["pub/a"] || !site.usedAssets["img.png"] {
+		t.Errorf("backlinks = %v, assets = %v", site.backlinks, site.usedAssets)
+	}
+}
+
+// TestBundleNotes tests chapter order, depth and embeds
+func TestBundleNotes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"root.md":   "[[b]] `[[code]]` [a](a.md) ![[e]] [[hidden]]\n",
+		"a.md":      "[[deep]]\n",
+		"b.md":      "[[root]]\n",
+		"e.md":      "![[e2]] [[deep]]\n",
+		"e2.md":     "embedded\n",
+		"deep.md":   "deep\n",
+		"code.md":   "code\n",
+		"hidden.md": "---\npublish: false\n---\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	site, err := newExportSite(dir, &exportSelection{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Embeds are followed at the depth of the embedding note
+	got := strings.Join(site.bundleNotes("root", 1), " ")
+	if want := "root b a e e2 deep"; got != want {
+		t.Errorf("depth 1 = %q, want %q", got, want)
+	}
+	got = strings.Join(site.bundleNotes("root", 0), " ")
+	if want := "root e e2"; got != want {
+		t.Errorf("depth 0 = %q, want %q", got, want)
+	}
+}
+
+// TestExportMarkdown tests the frontmatter, embed and unpublished link
+// options of export markdown
+func TestExportMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pub/a.md":   "---\npublish: true\n---\nSee [[private]].\n![[b]]\n",
+		"pub/b.md":   "---\npublish: true\n---\nB text\n",
+		"private.md": "secret\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldVault := vaultPath
+	defer func() {
+		vaultPath = oldVault
+		exportMarkdownSelection, exportMarkdownUnpublished, exportMarkdownEmbeds, exportMarkdownNoFrontmatter = exportSelection{}, "strip", "inline", false
+		exportMarkdownOutput, exportMarkdownFormat = "", "text"
+	}()
+	vaultPath = dir
+
+	export := func(unpublished, embeds string, noFrontmatter bool) string {
+		exportMarkdownSelection = exportSelection{Published: true}
+		exportMarkdownUnpublished, exportMarkdownEmbeds, exportMarkdownNoFrontmatter = unpublished, embeds, noFrontmatter
+		exportMarkdownOutput = filepath.Join(t.TempDir(), "out")
+		exportMarkdownFormat = "json"
+		cmd := &cobra.Command{}
+		cmd.SetOut(&bytes.Buffer{})
+		if err := runExportMarkdown(cmd, nil); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(exportMarkdownOutput, "pub", "a.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	tests := []struct {
+		name                string
+		unpublished, embeds string
+		noFrontmatter       bool
+		want                string
+	}{
+		{"defaults", "strip", "inline", false, "---\npublish: true\n---\nSee private.\nB text\n"},
+		{"no frontmatter", "strip", "inline", true, "See private.\nB text\n"},
+		{"embeds link", "strip", "link", false, "---\npublish: true\n---\nSee private.\n[b](b.md)\n"},
+		{"unpublished flag", "flag", "inline", false, "---\npublish: true\n---\nSee [[private]].\nB text\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := export(tt.unpublished, tt.embeds, tt.noFrontmatter); got != tt.want {
+				t.Errorf("a.md = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAnchorMarkdownHeadings tests that markdown bundle headings get the
+// chapter-prefixed IDs that links between chapters point at
+func TestAnchorMarkdownHeadings(t *testing.T) {
+	source := "Intro\n\n## Sec Two\n\n```\n# not a heading\n```\n\n## Sec Two\n\n> ## [Quoted](x.md)\n"
+	want := "Intro\n\n<a id=\"ch2-sec-two\"></a>\n## Sec Two\n\n```\n# not a heading\n```\n\n" +
+		"<a id=\"ch2-sec-two-1\"></a>\n## Sec Two\n\n> <a id=\"ch2-quoted\"></a>\n> ## [Quoted](x.md)\n"
+	if got := anchorMarkdownHeadings(source, "ch2-"); got != want {
+		t.Errorf("anchorMarkdownHeadings = %q, want %q", got, want)
+	}
+
+	b := &exportBundle{format: "md", chapters: map[string]*bundleChapter{"b": {Path: "b", ID: "ch2"}}}
+	for fragment, want := range map[string]string{"": "#ch2", "Sec Two": "#ch2-sec-two", "^block": "#ch2"} {
+		if got := b.noteURL("b", fragment); got != want {
+			t.Errorf("noteURL(%q) = %q, want %q", fragment, got, want)
+		}
+	}
+}
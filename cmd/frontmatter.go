@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+// frontmatterField is a single top-level key in a note's YAML frontmatter.
+// Only the subset of YAML that Obsidian properties use is understood:
+// scalars, inline [a, b] lists and block "- item" lists. Anything else
+// (nested maps, multi-line strings) is preserved verbatim in Raw.
+type frontmatterField struct {
+	Key    string
+	Value  string   // Scalar value with surrounding quotes removed
+	List   []string // Items for list values
+	IsList bool
+	Raw    []string // Original lines, re-emitted unchanged unless the field is modified
+}
+
+// frontmatter is an ordered set of top-level frontmatter fields.
+type frontmatter struct {
+	Fields []*frontmatterField
+}
+
+var (
+	// Matches a top-level "key: value" line
+	frontmatterKeyRegex = regexp.MustCompile(`^([^\s#\-][^:]*):(?:\s+(.*))?$`)
+	// Matches a block list item: "  - value"
+	frontmatterItemRegex = regexp.MustCompile(`^\s*-\s+(.*)$`)
+)
+
+// splitFrontmatter splits note content into the frontmatter block (without
+// the --- delimiters) and the body that follows it. ok is false when the note
+// has no well-formed frontmatter, in which case body is the full content.
+func splitFrontmatter(content string) (fm, body string, ok bool) {
+	first, rest, found := strings.Cut(content, "\n")
+	if !found || strings.TrimRight(first, "\r") != "---" {
+		return "", content, false
+	}
+
+	offset := 0
+	for {
+		line, next, more := strings.Cut(rest[offset:], "\n")
+		trimmed := strings.TrimRight(line, "\r")
+		if trimmed == "---" || trimmed == "..." {
+			fm = rest[:offset]
+			if more {
+				body = next
+			}
+			return strings.TrimSuffix(fm, "\n"), body, true
+		}
+		if !more {
+			return "", content, false
+		}
+		offset += len(line) + 1
+	}
+}
+
+// parseFrontmatter parses the text between the --- delimiters.
+func parseFrontmatter(text string) *frontmatter {
+	fm := &frontmatter{}
+	if text == "" {
+		return fm
+	}
+
+	var current *frontmatterField
+	for _, rawLine := range strings.Split(text, "\n") {
+		line := strings.TrimRight(rawLine, "\r")
+
+		if m := frontmatterKeyRegex.FindStringSubmatch(line); m != nil {
+			current = &frontmatterField{Key: strings.TrimSpace(m[1]), Raw: []string{line}}
+			value := strings.TrimSpace(m[2])
+			if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") && !strings.HasPrefix(value, "[[") {
+				current.IsList = true
+				current.List = splitInlineList(value[1 : len(value)-1])
+			} else {
+				current.Value = unquoteYAML(value)
+			}
+			fm.Fields = append(fm.Fields, current)
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		current.Raw = append(current.Raw, line)
+
+		if m := frontmatterItemRegex.FindStringSubmatch(line); m != nil && current.Value == "" {
+			current.IsList = true
+			current.List = append(current.List, unquoteYAML(strings.TrimSpace(m[1])))
+		}
+	}
+	return fm
+}
+
+func splitInlineList(s string) []string {
+	var items []string
+	for _, part := range strings.Split(s, ",") {
+		if item := unquoteYAML(strings.TrimSpace(part)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func unquoteYAML(s string) string {
+	if len(s) >= 2 {
+		if (s[0] == '"' && s[len(s)-1] == '"') || (s[0] == '\'' && s[len(s)-1] == '\'') {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
+
+// quoteYAML quotes a scalar when plain YAML would misread it
+// (wikilinks, leading indicators, embedded ": " or " #").
+func quoteYAML(s string) string {
+	if s == "" {
+		return `""`
+	}
+	needsQuote := strings.ContainsAny(s[:1], "[]{}#&*!|>'\"%@`,-?:") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") ||
+		s != strings.TrimSpace(s)
+	if !needsQuote {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Get returns the field with the given key (case-insensitive), or nil.
+func (f *frontmatter) Get(key string) *frontmatterField {
+	for _, field := range f.Fields {
+		if strings.EqualFold(field.Key, key) {
+			return field
+		}
+	}
+	return nil
+}
+
+// Has reports whether key is present with a non-empty value.
+func (f *frontmatter) Has(key string) bool {
+	field := f.Get(key)
+	if field == nil {
+		return false
+	}
+	return len(field.List) > 0 || field.Value != "" || len(field.Raw) > 1
+}
+
+// Values returns the list items of key, or its scalar as a one-element list.
+func (f *frontmatter) Values(key string) []string {
+	field := f.Get(key)
+	if field == nil {
+		return nil
+	}
+	if field.IsList {
+		return field.List
+	}
+	if field.Value != "" {
+		return []string{field.Value}
+	}
+	return nil
+}
+
+// Map returns the fields as a map of scalar strings and string lists,
+// for JSON output.
+func (f *frontmatter) Map() map[string]any {
+	m := make(map[string]any, len(f.Fields))
+	for _, field := range f.Fields {
+		if field.IsList {
+			m[field.Key] = append([]string{}, field.List...)
+		} else {
+			m[field.Key] = field.Value
+		}
+	}
+	return m
+}
+
+// SetScalar sets key to a scalar value, appending the field if absent.
+func (f *frontmatter) SetScalar(key, value string) {
+	field := f.getOrAdd(key)
+	field.IsList = false
+	field.List = nil
+	field.Value = value
+	field.Raw = []string{field.Key + ": " + quoteYAML(value)}
+}
+
+// SetList sets key to a block list, appending the field if absent.
+func (f *frontmatter) SetList(key string, items []string) {
+	field := f.getOrAdd(key)
+	field.IsList = true
+	field.Value = ""
+	field.List = items
+	field.Raw = []string{field.Key + ":"}
+	for _, item := range items {
+		field.Raw = append(field.Raw, "  - "+quoteYAML(item))
+	}
+}
+
+// Delete removes key if present.
+func (f *frontmatter) Delete(key string) {
+	for i, field := range f.Fields {
+		if strings.EqualFold(field.Key, key) {
+			f.Fields = append(f.Fields[:i], f.Fields[i+1:]...)
+			return
+		}
+	}
+}
+
+func (f *frontmatter) getOrAdd(key string) *frontmatterField {
+	if field := f.Get(key); field != nil {
+		return field
+	}
+	field := &frontmatterField{Key: key}
+	f.Fields = append(f.Fields, field)
+	return field
+}
+
+// String renders the fields without the --- delimiters.
+func (f *frontmatter) String() string {
+	var lines []string
+	for _, field := range f.Fields {
+		lines = append(lines, field.Raw...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Block renders the fields wrapped in --- delimiters with a trailing newline.
+func (f *frontmatter) Block() string {
+	if len(f.Fields) == 0 {
+		return "---\n---\n"
+	}
+	return "---\n" + f.String() + "\n---\n"
+}
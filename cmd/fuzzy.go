@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+)
+
+// levenshtein returns the edit distance between a and b, counted in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// maxTypoDistance is the largest edit distance still considered a typo
+// for a name of the given length: 1 for short names, up to 3 for long ones.
+func maxTypoDistance(name string) int {
+	return max(1, min(3, len([]rune(name))/5))
+}
+
+// closeNoteMatches returns the note paths from ix whose basename is within
+// typo distance of target's basename, closest first.
+func closeNoteMatches(ix *noteIndex, target string) []string {
+	name := strings.ToLower(pathBase(strings.TrimSuffix(target, ".md")))
+	limit := maxTypoDistance(name)
+
+	type scored struct {
+		path string
+		dist int
+	}
+	var matches []scored
+	for _, p := range ix.paths {
+		d := levenshtein(name, strings.ToLower(pathBase(p)))
+		if d <= limit {
+			matches = append(matches, scored{p, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].dist < matches[j].dist
+	})
+
+	paths := make([]string, len(matches))
+	for i, m := range matches {
+		paths[i] = m.path
+	}
+	return paths
+}
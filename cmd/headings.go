@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+// noteHeading is an ATX heading (# Heading) in a note.
+type noteHeading struct {
+	Text  string // Heading text without the #s and trailing #s
+	Level int
+	Line  int // 1-based
+}
+
+// noteBlock is a block with an ID (a line ending in ^block-id).
+type noteBlock struct {
+	ID   string // Without the ^
+	Text string // Line text without the ID
+	Line int    // 1-based
+}
+
+var (
+	// Matches "# Heading" through "###### Heading"
+	headingRegex = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+	// Matches a block ID at the end of a line: "text ^block-id"
+	blockIDRegex = regexp.MustCompile(`(?:^|\s)\^([A-Za-z0-9-]+)\s*$`)
+)
+
+// forEachContentLine calls fn with every line of content (1-based) that is
+// outside fenced code blocks and frontmatter.
+func forEachContentLine(content string, fn func(line string, lineNum int)) {
+	lines := strings.Split(content, "\n")
+	start := 0
+	if _, body, ok := splitFrontmatter(content); ok {
+		start = strings.Count(content[:len(content)-len(body)], "\n")
+	}
+	inCodeBlock := false
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if !inCodeBlock {
+			fn(line, i+1)
+		}
+	}
+}
+
+// parseHeadings returns the headings of a note in order.
+func parseHeadings(content string) []noteHeading {
+	var headings []noteHeading
+	forEachContentLine(content, func(line string, lineNum int) {
+		if m := headingRegex.FindStringSubmatch(line); m != nil && m[2] != "" {
+			headings = append(headings, noteHeading{Text: m[2], Level: len(m[1]), Line: lineNum})
+		}
+	})
+	return headings
+}
+
+// parseBlocks returns the blocks with IDs in a note.
+func parseBlocks(content string) []noteBlock {
+	var blocks []noteBlock
+	forEachContentLine(content, func(line string, lineNum int) {
+		if m := blockIDRegex.FindStringSubmatchIndex(line); m != nil {
+			blocks = append(blocks, noteBlock{
+				ID:   line[m[2]:m[3]],
+				Text: strings.TrimSpace(line[:m[0]]),
+				Line: lineNum,
+			})
+		}
+	})
+	return blocks
+}
+
+// findHeading returns the heading matching text, compared the way Obsidian
+// resolves [[note#Heading]] links (case-insensitively, ignoring surrounding
+// space).
+func findHeading(headings []noteHeading, text string) (noteHeading, bool) {
+	text = strings.TrimSpace(text)
+	for _, h := range headings {
+		if strings.EqualFold(h.Text, text) {
+			return h, true
+		}
+	}
+	return noteHeading{}, false
+}
+
+// findBlock returns the block with the given ID (without ^).
+func findBlock(blocks []noteBlock, id string) (noteBlock, bool) {
+	for _, b := range blocks {
+		if strings.EqualFold(b.ID, id) {
+			return b, true
+		}
+	}
+	return noteBlock{}, false
+}
+
+// fragmentTarget splits a link fragment ("#Heading", "#^id" or "^id") into
+// the heading text or block ID it names. isBlock reports a block reference.
+// For nested heading links (#Parent#Child) the last heading is returned.
+func fragmentTarget(fragment string) (name string, isBlock bool) {
+	fragment = strings.TrimPrefix(fragment, "#")
+	if idx := strings.LastIndex(fragment, "#"); idx != -1 {
+		fragment = fragment[idx+1:]
+	}
+	if strings.HasPrefix(fragment, "^") {
+		return fragment[1:], true
+	}
+	return fragment, false
+}
+
+// noteSection returns the part of a note named by a link fragment: the
+// block's line, or the heading and everything up to the next heading of
+// the same or a higher level. Without a fragment it returns the body after
+// frontmatter. firstLine is the 1-based line the section starts on; ok is
+// false when the heading or block doesn't exist.
+func noteSection(content, fragment string) (section string, firstLine int, ok bool) {
+	lines := strings.Split(content, "\n")
+	start, end := 0, len(lines)
+	if _, body, hasFM := splitFrontmatter(content); hasFM {
+		start = strings.Count(content[:len(content)-len(body)], "\n")
+		if body == "" {
+			start = len(lines)
+		}
+	}
+
+	if fragment != "" {
+		name, isBlock := fragmentTarget(fragment)
+		if isBlock {
+			b, found := findBlock(parseBlocks(content), name)
+			if !found {
+				return "", 0, false
+			}
+			return lines[b.Line-1], b.Line, true
+		}
+		headings := parseHeadings(content)
+		h, found := findHeading(headings, name)
+		if !found {
+			return "", 0, false
+		}
+		start = h.Line - 1
+		for _, next := range headings {
+			if next.Line > h.Line && next.Level <= h.Level {
+				end = next.Line - 1
+				break
+			}
+		}
+	}
+	return strings.Join(lines[start:end], "\n"), start + 1, true
+}
+
+// headingSection finds the section under heading, given as "## Log" (that
+// level only) or just "Log". end is the 0-based index of the line after the
+// section. level and name are parsed from heading.
+func headingSection(content, heading string) (h noteHeading, end, level int, name string, found bool) {
+	name = strings.TrimSpace(heading)
+	if m := headingRegex.FindStringSubmatch(name); m != nil {
+		level, name = len(m[1]), m[2]
+	}
+	headings := parseHeadings(content)
+	for _, candidate := range headings {
+		if strings.EqualFold(candidate.Text, name) && (level == 0 || candidate.Level == level) {
+			h, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		return h, 0, level, name, false
+	}
+	end = strings.Count(content, "\n") + 1
+	for _, next := range headings {
+		if next.Line > h.Line && next.Level <= h.Level {
+			end = next.Line - 1
+			break
+		}
+	}
+	return h, end, level, name, true
+}
+
+// insertUnderHeading inserts text at the end of the section under heading,
+// given as "## Log" or just "Log", before the blank lines that end it. A
+// missing heading is added at the end of the note (at level 2 unless
+// given); found reports whether it existed.
+func insertUnderHeading(content, heading, text string) (out string, found bool) {
+	text = strings.TrimRight(text, "\n")
+	h, end, level, name, found := headingSection(content, heading)
+	if !found {
+		if level == 0 {
+			level = 2
+		}
+		sep := ""
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			sep = "\n"
+		}
+		if strings.TrimSpace(content) != "" {
+			sep += "\n"
+		}
+		return content + sep + strings.Repeat("#", level) + " " + name + "\n" + text + "\n", false
+	}
+
+	lines := strings.Split(content, "\n")
+	at := end
+	for at > h.Line && strings.TrimSpace(lines[at-1]) == "" {
+		at--
+	}
+	result := append(lines[:at:at], strings.Split(text, "\n")...)
+	return strings.Join(append(result, lines[at:]...), "\n"), true
+}
+
+// replaceSection replaces the content of the section under heading,
+// keeping the heading and the blank line before the next one. found is
+// false when the heading doesn't exist.
+func replaceSection(content, heading, text string) (out string, found bool) {
+	h, end, _, _, found := headingSection(content, heading)
+	if !found {
+		return content, false
+	}
+	lines := strings.Split(content, "\n")
+	var section []string
+	if text = strings.Trim(text, "\n"); text != "" {
+		section = strings.Split(text, "\n")
+	}
+	// Keep the blank lines that separate the section from what follows
+	trailing := end
+	for trailing > h.Line && strings.TrimSpace(lines[trailing-1]) == "" {
+		trailing--
+	}
+	result := append(lines[:h.Line:h.Line], section...)
+	return strings.Join(append(result, lines[trailing:]...), "\n"), true
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/fatih/color"
@@ -9,100 +10,309 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	healthMaxErrors   int
+	healthMaxWarnings int
+	healthLimit       int
+	healthListRules   bool
+	healthFormat      string
+	healthFix         bool
+	healthDryRun      bool
+)
+
 var healthCmd = &cobra.Command{
 	Use:   "health",
 	Short: "Run vault health check",
-	Long: `Performs a quick health check on your Obsidian vault.
+	Long: `Performs a health check on your Obsidian vault using a set of rules.
+
+Each rule has an ID and a severity (error, warn, info). Rules, severities
+and options can be configured per folder in <vault>/.obsidian-cli.json:
+
+  {
+    "health": {
+      "max_errors": 0,
+      "max_warnings": 50,
+      "rules": {
+        "orphans": {"severity": "info"},
+        "required-frontmatter": {
+          "options": {"keys": ["title"]},
+          "folders": {
+            "projects": {"options": {"keys": ["title", "status"]}},
+            "inbox": {"enabled": false}
+          }
+        }
+      }
+    }
+  }
+
+The command exits non-zero when errors exceed max_errors (default 0) or
+warnings exceed max_warnings (default unlimited), so it can gate commits.
+
+Use --list-rules to see all available rules.
+
+Machine-readable output is available with --format json, sarif (for code
+scanning annotations) or junit (for CI test reporters). Issue locations
+are vault-relative paths with line numbers where known.
+
+With --fix, fixable rules rewrite the notes they flagged:
+
+  missing-frontmatter   add a skeleton (options.template per folder, with
+                        {{title}} and {{date}}, plus required keys)
+  required-frontmatter  add required keys with a known default
+  dead-links            retarget links with exactly one close note match
+  link-case             match the linked note's filename casing
+  trailing-whitespace   trim line ends (hard breaks kept by default)
+  tag-casing            use the vault's most common spelling of each tag
 
-Checks for:
-  - Total note count
-  - Orphan files (no incoming links)
-  - Dead links (broken [[wikilinks]])
-  - Missing frontmatter
+Use --dry-run to preview a diff. Applied fixes are journaled and can be
+reverted with "obsidian-cli undo".
 
 Example:
-  obsidian-cli health --vault ~/Documents/Obsidian`,
-	RunE: runHealth,
+  obsidian-cli health --vault ~/Documents/Obsidian
+  obsidian-cli health --vault ~/Documents/Obsidian --fix --dry-run
+  obsidian-cli health --vault ~/Documents/Obsidian --max-warnings 0
+  obsidian-cli health --vault ~/Documents/Obsidian --format sarif > health.sarif`,
+	// A failed check is an expected outcome, not a usage error
+	SilenceUsage: true,
+	RunE:         runHealth,
 }
 
 func init() {
 	rootCmd.AddCommand(healthCmd)
+	healthCmd.Flags().IntVar(&healthMaxErrors, "max-errors", 0, "Fail when errors exceed this count (-1 = never fail)")
+	healthCmd.Flags().IntVar(&healthMaxWarnings, "max-warnings", -1, "Fail when warnings exceed this count (-1 = never fail)")
+	healthCmd.Flags().IntVarP(&healthLimit, "limit", "n", 10, "Max issues shown per rule (0 = no limit)")
+	healthCmd.Flags().BoolVar(&healthListRules, "list-rules", false, "List available rules and exit")
+	healthCmd.Flags().StringVar(&healthFormat, "format", "text", "Output format: text, json, sarif, junit")
+	healthCmd.Flags().BoolVar(&healthFix, "fix", false, "Apply automatic fixes for fixable rules")
+	healthCmd.Flags().BoolVar(&healthDryRun, "dry-run", false, "With --fix, preview fixes without modifying files")
+}
+
+// HealthReport holds the results of a health check.
+type HealthReport struct {
+	Notes    int64          `json:"notes"`
+	Files    int64          `json:"files"`
+	Issues   []HealthIssue  `json:"issues"`
+	Counts   map[string]int `json:"counts"` // Issue count per severity
+	ByRule   map[string]int `json:"by_rule"`
+	Passed   bool           `json:"passed"`
+	Failures []string       `json:"failures,omitempty"` // Threshold violations
+	Elapsed  time.Duration  `json:"-"`
 }
 
 func runHealth(cmd *cobra.Command, args []string) error {
+	if healthListRules {
+		printHealthRules()
+		return nil
+	}
+
 	if err := RequireVault(); err != nil {
 		return err
 	}
+	if healthDryRun && !healthFix {
+		return fmt.Errorf("--dry-run requires --fix")
+	}
+	if healthFix && healthFormat != "text" && healthFormat != "json" {
+		return fmt.Errorf("--fix supports --format text or json, not %s", healthFormat)
+	}
 
-	green := color.New(color.FgGreen).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-	cyan := color.New(color.FgCyan).SprintFunc()
-	bold := color.New(color.Bold).SprintFunc()
+	if healthFormat == "text" {
+		fmt.Printf("\n%s Scanning vault: %s\n\n", colors.Cyan("=>"), vaultPath)
+	}
 
-	fmt.Printf("\n%s Scanning vault: %s\n\n", cyan("=>"), vaultPath)
+	report, rc, err := checkVaultHealth(cmd)
+	if err != nil {
+		return err
+	}
 
+	if healthFix {
+		return runHealthFix(cmd, rc, report)
+	}
+
+	if healthFormat == "text" {
+		printHealthText(report)
+	} else if err := outputHealthReport(cmd, report, healthFormat); err != nil {
+		return err
+	}
+
+	// Return error if thresholds exceeded (allows Cobra to handle exit)
+	if !report.Passed {
+		return fmt.Errorf("health check failed: %s", report.Failures[0])
+	}
+	return nil
+}
+
+// checkVaultHealth scans the vault, runs all rules and evaluates thresholds.
+// The rule context is returned for --fix.
+func checkVaultHealth(cmd *cobra.Command) (*HealthReport, *ruleContext, error) {
 	start := time.Now()
-	result, err := vault.ScanVault(vaultPath)
+
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid vault path: %w", err)
+	}
+
+	cfg, err := loadVaultConfig(absPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateHealthConfig(cfg.Health); err != nil {
+		return nil, nil, err
+	}
+
+	scan, err := vault.ScanVault(absPath)
 	if err != nil {
-		return fmt.Errorf("scan failed: %w", err)
+		return nil, nil, fmt.Errorf("scan failed: %w", err)
+	}
+	notes, err := loadNotes(absPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc := &ruleContext{
+		absPath: absPath,
+		scan:    scan,
+		notes:   notes,
+		config:  cfg.Health,
+	}
+	if rc.assets, err = newAssetResolver(absPath, rc.noteIndex()); err != nil {
+		return nil, nil, err
+	}
+	issues := runHealthRules(rc)
+
+	report := &HealthReport{
+		Notes:  scan.MarkdownFiles,
+		Files:  scan.TotalFiles,
+		Issues: issues,
+		Counts: map[string]int{severityError: 0, severityWarn: 0, severityInfo: 0},
+		ByRule: make(map[string]int),
+	}
+	if report.Issues == nil {
+		report.Issues = []HealthIssue{}
+	}
+	for _, issue := range issues {
+		report.Counts[issue.Severity]++
+		report.ByRule[issue.Rule]++
+	}
+
+	// Flags override config thresholds only when set explicitly
+	maxErrors, maxWarnings := 0, -1
+	if cfg.Health.MaxErrors != nil {
+		maxErrors = *cfg.Health.MaxErrors
+	}
+	if cfg.Health.MaxWarnings != nil {
+		maxWarnings = *cfg.Health.MaxWarnings
+	}
+	if cmd.Flags().Changed("max-errors") {
+		maxErrors = healthMaxErrors
+	}
+	if cmd.Flags().Changed("max-warnings") {
+		maxWarnings = healthMaxWarnings
+	}
+
+	if maxErrors >= 0 && report.Counts[severityError] > maxErrors {
+		report.Failures = append(report.Failures, fmt.Sprintf("%d errors (max %d)", report.Counts[severityError], maxErrors))
+	}
+	if maxWarnings >= 0 && report.Counts[severityWarn] > maxWarnings {
+		report.Failures = append(report.Failures, fmt.Sprintf("%d warnings (max %d)", report.Counts[severityWarn], maxWarnings))
 	}
-	elapsed := time.Since(start)
+	report.Passed = len(report.Failures) == 0
+	report.Elapsed = time.Since(start)
+
+	return report, rc, nil
+}
+
+func printHealthText(report *HealthReport) {
+	bold := color.New(color.Bold).SprintFunc()
 
 	// Determine overall health
-	issues := len(result.Orphans) + len(result.DeadLinks) + len(result.FrontmatterErrs)
 	var statusIcon string
-	if issues == 0 {
-		statusIcon = green("✓")
-	} else if issues < 10 {
-		statusIcon = yellow("!")
-	} else {
-		statusIcon = red("✗")
+	switch {
+	case report.Counts[severityError] > 0:
+		statusIcon = colors.Red("✗")
+	case report.Counts[severityWarn] > 0:
+		statusIcon = colors.Yellow("!")
+	default:
+		statusIcon = colors.Green("✓")
 	}
 
 	fmt.Printf("%s %s\n\n", statusIcon, bold("Vault Health Check"))
 
 	// Summary stats
-	fmt.Printf("  %s %d\n", cyan("Notes:"), result.MarkdownFiles)
+	fmt.Printf("  %s %d\n", colors.Cyan("Notes:"), report.Notes)
 
-	// Helper to format count with color based on severity
-	formatCount := func(count int, warnColor, okColor func(a ...interface{}) string) string {
-		if count == 0 {
-			return okColor("0")
-		}
-		return warnColor(fmt.Sprintf("%d", count))
+	severityColor := map[string]func(a ...interface{}) string{
+		severityError: colors.Red,
+		severityWarn:  colors.Yellow,
+		severityInfo:  colors.Dim,
 	}
 
-	orphanCount := len(result.Orphans)
-	deadLinkCount := len(result.DeadLinks)
-	fmErrCount := len(result.FrontmatterErrs)
-
-	fmt.Printf("  %s %s\n", cyan("Orphans:"), formatCount(orphanCount, yellow, green))
-	fmt.Printf("  %s %s\n", cyan("Dead Links:"), formatCount(deadLinkCount, red, green))
-	fmt.Printf("  %s %s\n", cyan("Frontmatter Issues:"), formatCount(fmErrCount, yellow, green))
+	// One count line per registered rule
+	byRule := make(map[string][]HealthIssue)
+	for _, issue := range report.Issues {
+		byRule[issue.Rule] = append(byRule[issue.Rule], issue)
+	}
+	for _, rule := range healthRules {
+		issues := byRule[rule.ID]
+		count := colors.Green("0")
+		if len(issues) > 0 {
+			count = severityColor[highestSeverity(issues)](fmt.Sprintf("%d", len(issues)))
+		}
+		fmt.Printf("  %s %s\n", colors.Cyan(rule.Title+":"), count)
+	}
 
-	// Show dead link details if any exist
-	if deadLinkCount > 0 {
-		showCount := deadLinkCount
-		if showCount > 10 {
-			showCount = 10
-			fmt.Printf("\n  %s (%d total, showing first 10)\n", bold("Dead Links:"), deadLinkCount)
+	// Show details for rules reporting errors or warnings
+	for _, rule := range healthRules {
+		issues := byRule[rule.ID]
+		if len(issues) == 0 || highestSeverity(issues) == severityInfo {
+			continue
+		}
+		shown := applyLimit(issues, healthLimit)
+		if len(shown) < len(issues) {
+			fmt.Printf("\n  %s %s (%d total, showing first %d)\n", bold(rule.Title+":"), colors.Dim("["+rule.ID+"]"), len(issues), len(shown))
 		} else {
-			fmt.Printf("\n  %s\n", bold("Dead Links:"))
+			fmt.Printf("\n  %s %s\n", bold(rule.Title+":"), colors.Dim("["+rule.ID+"]"))
 		}
-		for i := 0; i < showCount; i++ {
-			dl := result.DeadLinks[i]
-			fmt.Printf("    %s:%d -> [[%s]]\n", dl.SourceFile, dl.Line, dl.Target)
+		for _, issue := range shown {
+			location := issue.File
+			if issue.Line > 0 {
+				location = fmt.Sprintf("%s:%d", issue.File, issue.Line)
+			}
+			fmt.Printf("    %s %s %s\n", severityColor[issue.Severity](issue.Severity), location, colors.Dim(issue.Message))
 		}
 	}
 
+	// Threshold summary
+	fmt.Printf("\n  %s %d errors, %d warnings, %d info\n",
+		colors.Cyan("Issues:"),
+		report.Counts[severityError], report.Counts[severityWarn], report.Counts[severityInfo])
+
 	// Performance info
-	fmt.Printf("\n  %s %s (%d files)\n", cyan("Scanned in:"), elapsed.Round(time.Millisecond), result.TotalFiles)
+	fmt.Printf("  %s %s (%d files)\n", colors.Cyan("Scanned in:"), report.Elapsed.Round(time.Millisecond), report.Files)
+}
 
-	// Return error if critical issues found (allows Cobra to handle exit)
-	if deadLinkCount > 0 {
-		return fmt.Errorf("vault has %d dead links", deadLinkCount)
+// highestSeverity returns the most severe level among issues, which can
+// differ within a rule through folder overrides.
+func highestSeverity(issues []HealthIssue) string {
+	highest := severityInfo
+	for _, issue := range issues {
+		switch issue.Severity {
+		case severityError:
+			return severityError
+		case severityWarn:
+			highest = severityWarn
+		}
 	}
+	return highest
+}
 
-	return nil
+func printHealthRules() {
+	fmt.Printf("\n%s Health Rules %s\n\n", colors.Cyan("=>"), colors.Dim(fmt.Sprintf("(%d)", len(healthRules))))
+	for _, rule := range healthRules {
+		fixable := ""
+		if rule.Fix != nil {
+			fixable = "fix"
+		}
+		fmt.Printf("  %-26s %-6s %-4s %s\n", rule.ID, rule.DefaultSeverity, fixable, colors.Dim(rule.Description))
+	}
+	fmt.Println()
 }
@@ -0,0 +1,532 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// HealthFix is a single change made by health --fix.
+type HealthFix struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// HealthFixResult holds the outcome of health --fix.
+type HealthFixResult struct {
+	Fixes         []HealthFix    `json:"fixes"`
+	ByRule        map[string]int `json:"by_rule"`
+	FilesModified int            `json:"files_modified"`
+	Executed      bool           `json:"executed"`
+	JournalID     string         `json:"journal_id,omitempty"`
+}
+
+// fixedNote is a note rewritten by one or more rule fixes.
+type fixedNote struct {
+	note    *noteFile
+	content string
+	fixes   []HealthFix
+}
+
+// applyHealthFixes runs the fix of every fixable rule that reported an
+// issue for a note, in registry order, each on the output of the previous
+// one. Lines the fixes leave alone keep their line endings and changed
+// lines get the note's usual one (see restoreLineEndings). Nothing is
+// written to disk.
+func applyHealthFixes(rc *ruleContext, issues []HealthIssue) ([]*fixedNote, error) {
+	flagged := make(map[string]map[string]bool)
+	for _, issue := range issues {
+		if flagged[issue.File] == nil {
+			flagged[issue.File] = make(map[string]bool)
+		}
+		flagged[issue.File][issue.Rule] = true
+	}
+
+	var fixed []*fixedNote
+	for _, note := range rc.notes {
+		rules := flagged[note.RelPath]
+		if rules == nil {
+			continue
+		}
+
+		current := note
+		var fixes []HealthFix
+		for _, rule := range healthRules {
+			if rule.Fix == nil || !rules[rule.ID] {
+				continue
+			}
+			content, messages, err := rule.Fix(rc, rule, current)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", rule.ID, note.RelPath, err)
+			}
+			if content == current.Content {
+				continue
+			}
+			for _, msg := range messages {
+				fixes = append(fixes, HealthFix{Rule: rule.ID, File: note.RelPath, Message: msg})
+			}
+			current = parseNote(rc.absPath, note.Path, content)
+		}
+		if current != note {
+			content := restoreLineEndings(note.Content, strings.ReplaceAll(current.Content, "\r\n", "\n"))
+			fixed = append(fixed, &fixedNote{note: note, content: content, fixes: fixes})
+		}
+	}
+	return fixed, nil
+}
+
+func runHealthFix(cmd *cobra.Command, rc *ruleContext, report *HealthReport) error {
+	start := time.Now()
+
+	fixed, err := applyHealthFixes(rc, report.Issues)
+	if err != nil {
+		return err
+	}
+
+	result := &HealthFixResult{
+		Fixes:         []HealthFix{},
+		ByRule:        make(map[string]int),
+		FilesModified: len(fixed),
+		Executed:      !healthDryRun,
+	}
+	for _, f := range fixed {
+		for _, fix := range f.fixes {
+			result.Fixes = append(result.Fixes, fix)
+			result.ByRule[fix.Rule]++
+		}
+	}
+
+	if healthFormat == "json" {
+		if !healthDryRun {
+			result.JournalID, err = writeHealthFixes(rc.absPath, fixed)
+			if err != nil {
+				return err
+			}
+		}
+		return encodeJSON(cmd, result)
+	}
+
+	printHealthFixPreview(fixed, result, time.Since(start))
+
+	if len(fixed) == 0 {
+		return nil
+	}
+	if healthDryRun {
+		fmt.Printf("  %s Run without --dry-run to apply fixes\n\n", colors.Yellow("!"))
+		return nil
+	}
+
+	journalID, err := writeHealthFixes(rc.absPath, fixed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s Fixed %d issues in %d files\n", colors.Green("✓"), len(result.Fixes), len(fixed))
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n\n", colors.Dim("i"), journalID)
+	return nil
+}
+
+// writeHealthFixes writes fixed notes through a journal so the run can be
+// undone. Partial runs are journaled too. Returns the journal ID.
+func writeHealthFixes(absPath string, fixed []*fixedNote) (journalID string, err error) {
+	if len(fixed) == 0 {
+		return "", nil
+	}
+
+	j := newJournal(absPath, "fix", fmt.Sprintf("health --fix (%d files)", len(fixed)))
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	for _, f := range fixed {
+		if err := j.writeFile(f.note.RelPath, []byte(f.content), 0644); err != nil {
+			return j.ID, err
+		}
+	}
+	return j.ID, nil
+}
+
+func printHealthFixPreview(fixed []*fixedNote, result *HealthFixResult, elapsed time.Duration) {
+	title := "Health Fixes"
+	if healthDryRun {
+		title += " (dry run)"
+	}
+	fmt.Printf("%s %s\n\n", colors.Green("→"), title)
+
+	if len(fixed) == 0 {
+		fmt.Printf("  Nothing to fix.\n\n")
+		fmt.Printf("  %s %s\n", colors.Cyan("Analyzed in:"), elapsed.Round(time.Millisecond))
+		return
+	}
+
+	for _, f := range fixed {
+		fmt.Printf("    %s\n", colors.Cyan(f.note.RelPath))
+		for _, fix := range f.fixes {
+			fmt.Printf("      %s %s\n", colors.Dim("["+fix.Rule+"]"), fix.Message)
+		}
+		if healthDryRun {
+			printDiff(diffLines(f.note.Content, f.content), 1)
+		}
+	}
+
+	fmt.Printf("\n  %s\n", colors.Cyan("Fixes by rule:"))
+	for _, rule := range healthRules {
+		if n := result.ByRule[rule.ID]; n > 0 {
+			fmt.Printf("    %-26s %d\n", rule.ID, n)
+		}
+	}
+	fmt.Printf("\n  %s %d fixes in %d files\n", colors.Cyan("Total:"), len(result.Fixes), result.FilesModified)
+	fmt.Printf("  %s %s\n\n", colors.Cyan("Analyzed in:"), elapsed.Round(time.Millisecond))
+}
+
+// frontmatterSkeleton returns the frontmatter a note should start with: the
+// frontmatter of the missing-frontmatter template option for its folder
+// (with {{title}} and {{date}} filled in), plus any keys required by
+// required-frontmatter. Required keys not in the template are left empty,
+// except title which defaults to the note name.
+func frontmatterSkeleton(rc *ruleContext, note *noteFile) (*frontmatter, error) {
+	fm := &frontmatter{}
+
+	tmpl := rc.settings(findHealthRule("missing-frontmatter"), note.RelPath).Options.String("template", "")
+	if tmpl != "" {
+		text, err := loadTemplate(rc.absPath, tmpl)
+		if err != nil {
+			return nil, err
+		}
+		if fmText, _, ok := splitFrontmatter(text); ok {
+			fm = parseFrontmatter(applyTemplateVars(fmText, note.Name))
+		}
+	}
+
+	for _, key := range rc.settings(findHealthRule("required-frontmatter"), note.RelPath).Options.Strings("keys") {
+		if fm.Get(key) != nil {
+			continue
+		}
+		if strings.EqualFold(key, "title") {
+			fm.SetScalar(key, note.Name)
+		} else {
+			fm.Fields = append(fm.Fields, &frontmatterField{Key: key, Raw: []string{key + ":"}})
+		}
+	}
+	return fm, nil
+}
+
+// frontmatterBlock renders fm as the frontmatter block of note, closing it
+// with "..." when the note's block was closed that way.
+func frontmatterBlock(note *noteFile, fm *frontmatter) string {
+	block := fm.Block()
+	if note.HasFrontmatter {
+		head := strings.TrimRight(note.Content[:len(note.Content)-len(note.Body)], "\r\n")
+		if strings.HasSuffix(head, "\n...") {
+			block = strings.TrimSuffix(block, "---\n") + "...\n"
+		}
+	}
+	return block
+}
+
+func fixMissingFrontmatter(rc *ruleContext, rule *healthRule, note *noteFile) (string, []string, error) {
+	// A note starting with --- has a malformed block; don't stack another one
+	if note.HasFrontmatter || strings.HasPrefix(note.Content, "---") {
+		return note.Content, nil, nil
+	}
+	fm, err := frontmatterSkeleton(rc, note)
+	if err != nil {
+		return "", nil, err
+	}
+	msg := "added empty frontmatter"
+	if len(fm.Fields) > 0 {
+		keys := make([]string, len(fm.Fields))
+		for i, field := range fm.Fields {
+			keys[i] = field.Key
+		}
+		msg = fmt.Sprintf("added frontmatter (%s)", strings.Join(keys, ", "))
+	}
+	return frontmatterBlock(note, fm) + note.Content, []string{msg}, nil
+}
+
+// fixRequiredFrontmatter adds missing required keys whose value is known
+// (from the template, or title from the note name). Keys without a
+// default need a human and are left reported.
+func fixRequiredFrontmatter(rc *ruleContext, rule *healthRule, note *noteFile) (string, []string, error) {
+	if !note.HasFrontmatter && strings.HasPrefix(note.Content, "---") {
+		return note.Content, nil, nil
+	}
+	skeleton, err := frontmatterSkeleton(rc, note)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fm := parseFrontmatter(note.Frontmatter.String())
+	var messages []string
+	for _, key := range rc.settings(rule, note.RelPath).Options.Strings("keys") {
+		if fm.Has(key) || !skeleton.Has(key) {
+			continue
+		}
+		field := *skeleton.Get(key)
+		if existing := fm.Get(key); existing != nil {
+			*existing = field
+		} else {
+			fm.Fields = append(fm.Fields, &field)
+		}
+		messages = append(messages, fmt.Sprintf("added %s: %s", key, strings.Join(skeleton.Values(key), ", ")))
+	}
+	if len(messages) == 0 {
+		return note.Content, nil, nil
+	}
+	return frontmatterBlock(note, fm) + note.Body, messages, nil
+}
+
+// fixDeadLinks retargets dead links that have exactly one close match
+// among note names. Ambiguous or distant targets are left alone.
+func fixDeadLinks(rc *ruleContext, rule *healthRule, note *noteFile) (string, []string, error) {
+	dead := make(map[string]bool)
+	for _, dl := range rc.scan.DeadLinks {
+		if dl.SourceFile == note.RelPath {
+			dead[dl.Target] = true
+		}
+	}
+
+	ix := rc.noteIndex()
+	var messages []string
+	content, _ := rewriteWikilinks(note.Content, func(l wikilink) (string, bool) {
+		if !dead[l.Target] || strings.HasSuffix(l.Target, "/") {
+			return "", false
+		}
+		if ext := strings.ToLower(filepath.Ext(l.Target)); l.Embed && ext != "" && ext != ".md" {
+			return "", false
+		}
+		matches := closeNoteMatches(ix, l.Target)
+		if len(matches) != 1 {
+			return "", false
+		}
+		old := l.Target
+		l.Target = ix.linkText(matches[0])
+		messages = append(messages, fmt.Sprintf("[[%s]] -> [[%s]]", old, l.Target))
+		return l.String(), true
+	})
+	return content, messages, nil
+}
+
+// caseCorrectTarget returns target with the casing of the note it resolves
+// to, and whether that differs from target. Leading slashes and .md
+// extensions are kept as written.
+func caseCorrectTarget(ix *noteIndex, target string) (string, bool) {
+	prefix, ext := "", ""
+	t := target
+	if strings.HasPrefix(t, "/") {
+		prefix, t = "/", t[1:]
+	}
+	if strings.HasSuffix(strings.ToLower(t), ".md") {
+		ext, t = t[len(t)-3:], t[:len(t)-3]
+	}
+	if t == "" || strings.HasSuffix(t, "/") {
+		return "", false
+	}
+
+	resolved, ok := ix.resolve(t)
+	if !ok || len(resolved) < len(t) {
+		return "", false
+	}
+	// The written target always matches a suffix of the resolved path
+	want := resolved[len(resolved)-len(t):]
+	if want == t || !strings.EqualFold(want, t) {
+		return "", false
+	}
+	return prefix + want + ext, true
+}
+
+func fixLinkCase(rc *ruleContext, rule *healthRule, note *noteFile) (string, []string, error) {
+	ix := rc.noteIndex()
+	var messages []string
+	content, _ := rewriteWikilinks(note.Content, func(l wikilink) (string, bool) {
+		fixed, ok := caseCorrectTarget(ix, l.Target)
+		if !ok {
+			return "", false
+		}
+		messages = append(messages, fmt.Sprintf("[[%s]] -> [[%s]]", l.Target, fixed))
+		l.Target = fixed
+		return l.String(), true
+	})
+	return content, messages, nil
+}
+
+// trimTrailingWhitespace strips spaces and tabs from line ends, keeping
+// CRLF endings. With keepHardBreaks, an exact two-space markdown hard
+// break after text is preserved. Returns the new text and the 1-based
+// lines that changed.
+func trimTrailingWhitespace(text string, keepHardBreaks bool) (string, []int) {
+	lines := strings.Split(text, "\n")
+	var changed []int
+	for i, line := range lines {
+		cr := ""
+		if strings.HasSuffix(line, "\r") {
+			line, cr = line[:len(line)-1], "\r"
+		}
+		trimmed := strings.TrimRight(line, " \t")
+		if trimmed == line {
+			continue
+		}
+		if keepHardBreaks && strings.TrimSpace(trimmed) != "" && line[len(trimmed):] == "  " {
+			continue
+		}
+		lines[i] = trimmed + cr
+		changed = append(changed, i+1)
+	}
+	if len(changed) == 0 {
+		return text, nil
+	}
+	return strings.Join(lines, "\n"), changed
+}
+
+func fixTrailingWhitespace(rc *ruleContext, rule *healthRule, note *noteFile) (string, []string, error) {
+	keep := rc.settings(rule, note.RelPath).Options.Bool("keep_hard_breaks", true)
+	content, lines := trimTrailingWhitespace(note.Content, keep)
+	if len(lines) == 0 {
+		return note.Content, nil, nil
+	}
+	return content, []string{fmt.Sprintf("trimmed %d lines", len(lines))}, nil
+}
+
+// tagOccurrence is one use of a tag in a note.
+type tagOccurrence struct {
+	Tag        string // As written, without #
+	Line       int
+	Start, End int // Byte range in the note body; -1 for frontmatter tags
+}
+
+// frontmatterTags returns the tags listed in a note's frontmatter, as
+// tags.go reads them: list items or comma-separated values, # optional.
+func frontmatterTags(fm *frontmatter) []string {
+	var tags []string
+	for _, value := range fm.Values("tags") {
+		for _, part := range strings.Split(value, ",") {
+			if tag := strings.TrimPrefix(strings.TrimSpace(part), "#"); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+func noteTagOccurrences(note *noteFile) []tagOccurrence {
+	var occs []tagOccurrence
+	for _, tag := range frontmatterTags(note.Frontmatter) {
+		occs = append(occs, tagOccurrence{Tag: tag, Line: 1, Start: -1, End: -1})
+	}
+	forEachInlineTag(note.Body, func(start, end int) {
+		occs = append(occs, tagOccurrence{
+			Tag:   note.Body[start:end],
+			Line:  note.BodyLine + lineNumberAt(note.Body, start) - 1,
+			Start: start,
+			End:   end,
+		})
+	})
+	return occs
+}
+
+// canonicalTag returns the spelling tag should use. In "lower" mode that is
+// the lowercase form; otherwise it is the spelling used most across the
+// vault, with ties going to lowercase, then alphabetical order.
+func (rc *ruleContext) canonicalTag(tag, mode string) string {
+	lower := strings.ToLower(tag)
+	if mode == "lower" {
+		return lower
+	}
+
+	if rc.tagCanon == nil {
+		counts := make(map[string]map[string]int)
+		for _, note := range rc.notes {
+			for _, occ := range noteTagOccurrences(note) {
+				key := strings.ToLower(occ.Tag)
+				if counts[key] == nil {
+					counts[key] = make(map[string]int)
+				}
+				counts[key][occ.Tag]++
+			}
+		}
+
+		rc.tagCanon = make(map[string]string, len(counts))
+		for key, spellings := range counts {
+			best, bestCount := "", 0
+			for _, spelling := range sortedKeys(spellings) {
+				n := spellings[spelling]
+				if n > bestCount || (n == bestCount && spelling == key) {
+					best, bestCount = spelling, n
+				}
+			}
+			rc.tagCanon[key] = best
+		}
+	}
+
+	if canon, ok := rc.tagCanon[lower]; ok {
+		return canon
+	}
+	return tag
+}
+
+// replaceYAMLTag replaces whole-word occurrences of tag in a frontmatter line.
+func replaceYAMLTag(line, tag, replacement string) string {
+	re := regexp.MustCompile(`(^|[\s\[,'"#])` + regexp.QuoteMeta(tag) + `([\s\],'"]|$)`)
+	// Adjacent matches share a separator, so repeat until stable
+	for {
+		next := re.ReplaceAllString(line, "${1}"+strings.ReplaceAll(replacement, "$", "$$")+"${2}")
+		if next == line {
+			return line
+		}
+		line = next
+	}
+}
+
+func fixTagCasing(rc *ruleContext, rule *healthRule, note *noteFile) (string, []string, error) {
+	mode := rc.settings(rule, note.RelPath).Options.String("case", "majority")
+	renames := make(map[string]string)
+
+	var body strings.Builder
+	last := 0
+	fmChanged := false
+	fm := parseFrontmatter(note.Frontmatter.String())
+	for _, occ := range noteTagOccurrences(note) {
+		want := rc.canonicalTag(occ.Tag, mode)
+		if want == occ.Tag {
+			continue
+		}
+		renames[occ.Tag] = want
+		if occ.Start < 0 {
+			if field := fm.Get("tags"); field != nil {
+				for i, line := range field.Raw {
+					field.Raw[i] = replaceYAMLTag(line, occ.Tag, want)
+				}
+				fmChanged = true
+			}
+			continue
+		}
+		body.WriteString(note.Body[last:occ.Start])
+		body.WriteString(want)
+		last = occ.End
+	}
+	if len(renames) == 0 {
+		return note.Content, nil, nil
+	}
+	body.WriteString(note.Body[last:])
+
+	content := body.String()
+	if note.HasFrontmatter {
+		prefix := note.Content[:len(note.Content)-len(note.Body)]
+		if fmChanged {
+			prefix = frontmatterBlock(note, fm)
+		}
+		content = prefix + content
+	}
+
+	var messages []string
+	for _, old := range sortedKeys(renames) {
+		messages = append(messages, fmt.Sprintf("#%s -> #%s", old, renames[old]))
+	}
+	return content, messages, nil
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// writeHealthVault writes files into a new vault and points the health
+// command at it, restoring its flags when the test ends.
+func writeHealthVault(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldVault, oldFormat, oldDryRun := vaultPath, healthFormat, healthDryRun
+	t.Cleanup(func() { vaultPath, healthFormat, healthDryRun = oldVault, oldFormat, oldDryRun })
+	vaultPath = dir
+	return dir
+}
+
+// TestHealthFixers tests each fixer, on LF and CRLF notes
+func TestHealthFixers(t *testing.T) {
+	const noMissing = `"missing-frontmatter": {"enabled": false}`
+	tests := []struct {
+		name  string
+		rules string // Rules of .obsidian-cli.json
+		files map[string]string
+		want  map[string]string
+	}{
+		{"missing frontmatter", "", map[string]string{"a.md": "body\n"},
+			map[string]string{"a.md": "---\n---\nbody\n"}},
+		{"missing frontmatter crlf", `"required-frontmatter": {"options": {"keys": ["title"]}}`, map[string]string{"a.md": "body\r\nmore\r\n"},
+			map[string]string{"a.md": "---\r\ntitle: a\r\n---\r\nbody\r\nmore\r\n"}},
+		{"required frontmatter", noMissing + `, "required-frontmatter": {"options": {"keys": ["title", "status"]}}`,
+			map[string]string{"a.md": "---\ntags: [x]\n---\nbody\n"},
+			map[string]string{"a.md": "---\ntags: [x]\ntitle: a\n---\nbody\n"}},
+		{"required frontmatter crlf and dots", noMissing + `, "required-frontmatter": {"options": {"keys": ["title"]}}`,
+			map[string]string{"a.md": "---\r\ntags: [x]\r\n...\r\nbody\r\n"},
+			map[string]string{"a.md": "---\r\ntags: [x]\r\ntitle: a\r\n...\r\nbody\r\n"}},
+		{"dead links crlf", noMissing, map[string]string{"a.md": "x\r\n[[Meeting Note]]\r\n", "Meeting Notes.md": "[[a]]\n"},
+			map[string]string{"a.md": "x\r\n[[Meeting Notes]]\r\n"}},
+		{"link case", noMissing, map[string]string{"a.md": "[[meeting notes]]\n", "Meeting Notes.md": "[[a]]\n"},
+			map[string]string{"a.md": "[[Meeting Notes]]\n"}},
+		{"trailing whitespace crlf", noMissing, map[string]string{"a.md": "a \r\nhard  \r\nb\t\r\n"},
+			map[string]string{"a.md": "a\r\nhard  \r\nb\r\n"}},
+		{"tag casing", noMissing, map[string]string{"a.md": "#Work\n", "b.md": "#work\n", "c.md": "#work\n"},
+			map[string]string{"a.md": "#work\n"}},
+		{"tag casing frontmatter crlf", noMissing,
+			map[string]string{"a.md": "---\r\ntags: [Work]\r\n---\r\nx\r\n", "b.md": "#work\n", "c.md": "#work\n"},
+			map[string]string{"a.md": "---\r\ntags: [work]\r\n---\r\nx\r\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{defaultConfigName: `{"health": {"rules": {` + tt.rules + `}}}`}
+			for name, content := range tt.files {
+				files[name] = content
+			}
+			writeHealthVault(t, files)
+
+			report, rc, err := checkVaultHealth(healthCmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fixed, err := applyHealthFixes(rc, report.Issues)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			for _, f := range fixed {
+				got[filepath.ToSlash(f.note.RelPath)] = f.content
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %q, want %q", name, got[name], want)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("fixed %v, want %v", sortedKeys(got), sortedKeys(tt.want))
+			}
+		})
+	}
+}
+
+// TestRunHealthFix tests that a dry run writes nothing and that undo
+// restores the original bytes
+func TestRunHealthFix(t *testing.T) {
+	files := map[string]string{
+		"a.md":             "---\r\ntags: [Work]\r\n---\r\n[[meeting notes]] \r\n",
+		"b.md":             "---\n---\n#work\n",
+		"c.md":             "---\n---\n#work\n",
+		"Meeting Notes.md": "---\n---\n[[a]]\n",
+	}
+	dir := writeHealthVault(t, files)
+	healthFormat = "json"
+
+	fix := func(dryRun bool) HealthFixResult {
+		t.Helper()
+		healthDryRun = dryRun
+		report, rc, err := checkVaultHealth(healthCmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		if err := runHealthFix(cmd, rc, report); err != nil {
+			t.Fatal(err)
+		}
+		var result HealthFixResult
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	preview := fix(true)
+	if preview.Executed || preview.JournalID != "" || preview.FilesModified != 1 {
+		t.Errorf("dry run = %+v, want 1 file and nothing executed", preview)
+	}
+	for _, rule := range []string{"link-case", "trailing-whitespace", "tag-casing"} {
+		if preview.ByRule[rule] != 1 {
+			t.Errorf("dry run by_rule = %v, want one %s fix", preview.ByRule, rule)
+		}
+	}
+	if got := read("a.md"); got != files["a.md"] {
+		t.Errorf("dry run wrote a.md: %q", got)
+	}
+
+	result := fix(false)
+	if want := "---\r\ntags: [work]\r\n---\r\n[[Meeting Notes]]\r\n"; read("a.md") != want {
+		t.Errorf("a.md = %q, want %q", read("a.md"), want)
+	}
+	journals, err := loadJournals(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != 1 || journals[0].ID != result.JournalID {
+		t.Fatalf("journals = %d, want %s", len(journals), result.JournalID)
+	}
+	if err := journals[0].undo(false); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range files {
+		if got := read(name); got != want {
+			t.Errorf("after undo %s = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIF 2.1.0 subset used for code-scanning annotations.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifLevels maps rule severities to SARIF result levels.
+var sarifLevels = map[string]string{
+	severityError: "error",
+	severityWarn:  "warning",
+	severityInfo:  "note",
+}
+
+// toSARIF converts a health report to a SARIF log. File URIs are relative
+// to the vault root (%SRCROOT%) so CI can map them onto the checkout.
+func toSARIF(report *HealthReport) *sarifLog {
+	driver := sarifDriver{
+		Name:           "obsidian-cli",
+		InformationURI: "https://github.com/kofifort/obsidian-cli",
+	}
+	for _, rule := range healthRules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			Name:                 rule.Title,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevels[rule.DefaultSeverity]},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		loc := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: fileURI(issue.File), URIBaseID: "%SRCROOT%"},
+		}
+		if issue.Line > 0 {
+			loc.Region = &sarifRegion{StartLine: issue.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:    issue.Rule,
+			Level:     sarifLevels[issue.Severity],
+			Message:   sarifMessage{Text: issue.Message},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+
+	return &sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
+
+// fileURI percent-encodes each segment of a vault-relative path.
+func fileURI(relPath string) string {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// JUnit XML subset understood by common CI test reporters.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// toJUnit converts a health report to JUnit XML with one suite per rule.
+// Errors and warnings become failures; info issues pass with their message
+// in system-out. Rules without issues get a single passing test case.
+func toJUnit(report *HealthReport) *junitTestSuites {
+	byRule := make(map[string][]HealthIssue)
+	for _, issue := range report.Issues {
+		byRule[issue.Rule] = append(byRule[issue.Rule], issue)
+	}
+
+	suites := &junitTestSuites{Name: "obsidian-cli health"}
+	for _, rule := range healthRules {
+		suite := junitTestSuite{Name: rule.ID}
+		issues := byRule[rule.ID]
+		if len(issues) == 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: "no issues", ClassName: rule.ID})
+		}
+		for _, issue := range issues {
+			name := issue.File
+			if issue.Line > 0 {
+				name = fmt.Sprintf("%s:%d", issue.File, issue.Line)
+			}
+			tc := junitTestCase{Name: name, ClassName: rule.ID, File: filepath.ToSlash(issue.File)}
+			if issue.Severity == severityInfo {
+				tc.SystemOut = issue.Message
+			} else {
+				tc.Failure = &junitFailure{Message: issue.Message, Type: issue.Severity, Text: name + ": " + issue.Message}
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
+
+// writeJUnit writes the report as indented JUnit XML.
+func writeJUnit(w io.Writer, report *HealthReport) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(toJUnit(report)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// outputHealthReport writes the report in a machine-readable format.
+func outputHealthReport(cmd *cobra.Command, report *HealthReport, format string) error {
+	switch format {
+	case "json":
+		return encodeJSON(cmd, report)
+	case "sarif":
+		return encodeJSON(cmd, toSARIF(report))
+	case "junit":
+		return writeJUnit(cmd.OutOrStdout(), report)
+	}
+	return fmt.Errorf("unknown format %q (valid: text, json, sarif, junit)", format)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testHealthReport has an issue of each severity, one in a path that needs
+// escaping
+var testHealthReport = &HealthReport{Issues: []HealthIssue{
+	{Rule: "dead-links", Severity: severityError, File: filepath.Join("Projects", "Q1 #1.md"), Line: 3, Message: "dead link [[x]]"},
+	{Rule: "dead-links", Severity: severityWarn, File: "a.md", Line: 0, Message: "dead link [[y]]"},
+	{Rule: "orphans", Severity: severityInfo, File: "b.md", Message: "orphan note"},
+}}
+
+// TestToSARIF tests result levels, regions and escaped URIs
+func TestToSARIF(t *testing.T) {
+	log := toSARIF(testHealthReport)
+	if len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != len(healthRules) {
+		t.Fatalf("runs = %+v, want one run listing every rule", log.Runs)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 3 {
+		t.Fatalf("results = %d, want 3", len(results))
+	}
+
+	wantLevels := []string{"error", "warning", "note"}
+	for i, r := range results {
+		if r.Level != wantLevels[i] {
+			t.Errorf("result %d level = %q, want %q", i, r.Level, wantLevels[i])
+		}
+	}
+	loc := results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "Projects/Q1%20%231.md" || loc.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+		t.Errorf("artifact = %+v, want an escaped URI relative to %%SRCROOT%%", loc.ArtifactLocation)
+	}
+	if loc.Region == nil || loc.Region.StartLine != 3 {
+		t.Errorf("region = %+v, want startLine 3", loc.Region)
+	}
+
+	// Issues without a line have no region
+	data, err := json.Marshal(results[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "region") {
+		t.Errorf("result without a line = %s, want no region", data)
+	}
+}
+
+// TestToJUnit tests failure counts per suite and in total
+func TestToJUnit(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeJUnit(&out, testHealthReport); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(out.Bytes(), &suites); err != nil {
+		t.Fatal(err)
+	}
+	if suites.Failures != 2 || suites.Tests != len(healthRules)+1 {
+		t.Errorf("tests = %d, failures = %d; want %d and 2", suites.Tests, suites.Failures, len(healthRules)+1)
+	}
+
+	for _, suite := range suites.Suites {
+		switch suite.Name {
+		case "dead-links":
+			if suite.Tests != 2 || suite.Failures != 2 || suite.TestCases[0].Name != filepath.Join("Projects", "Q1 #1.md")+":3" {
+				t.Errorf("dead-links = %+v", suite)
+			}
+		case "orphans":
+			if suite.Failures != 0 || suite.TestCases[0].SystemOut != "orphan note" {
+				t.Errorf("orphans = %+v, want the info issue passing", suite)
+			}
+		default:
+			if suite.Tests != 1 || suite.Failures != 0 || suite.TestCases[0].Name != "no issues" {
+				t.Errorf("%s = %+v, want a single passing case", suite.Name, suite)
+			}
+		}
+	}
+}
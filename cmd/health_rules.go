@@ -0,0 +1,435 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kofifort/obsidian-cli/internal/vault"
+)
+
+// Rule severities, ordered from most to least severe. "off" disables a rule.
+const (
+	severityError = "error"
+	severityWarn  = "warn"
+	severityInfo  = "info"
+	severityOff   = "off"
+)
+
+var validSeverities = map[string]bool{
+	severityError: true, severityWarn: true, severityInfo: true, severityOff: true,
+}
+
+// HealthIssue is a single problem reported by a health rule.
+type HealthIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// healthRule is an entry in the health rule registry.
+type healthRule struct {
+	ID              string
+	Title           string // Short label for text output
+	Description     string
+	DefaultSeverity string
+	DefaultOptions  ruleOptions
+	Check           func(rc *ruleContext, rule *healthRule) []HealthIssue
+
+	// Fix, when set, rewrites a note the rule reported issues for. It returns
+	// the new content and one message per change made.
+	Fix func(rc *ruleContext, rule *healthRule, note *noteFile) (string, []string, error)
+}
+
+// healthRules is the rule registry, in display order.
+var healthRules []*healthRule
+
+func registerHealthRule(rule *healthRule) {
+	healthRules = append(healthRules, rule)
+}
+
+func findHealthRule(id string) *healthRule {
+	for _, rule := range healthRules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return nil
+}
+
+// ruleSettings is a rule's effective configuration for one file.
+type ruleSettings struct {
+	Enabled  bool
+	Severity string
+	Options  ruleOptions
+}
+
+// ruleContext is the shared input for all rule checks.
+type ruleContext struct {
+	absPath string
+	scan    *vault.ScanResult
+	notes   []*noteFile
+	config  healthConfig
+	assets  *assetResolver
+
+	index    *noteIndex        // Built on first use
+	tagCanon map[string]string // Lowercase tag -> most used spelling, built on first use
+}
+
+// noteIndex returns the link resolver for the vault's notes.
+func (rc *ruleContext) noteIndex() *noteIndex {
+	if rc.index == nil {
+		rc.index = newNoteIndexFromNotes(rc.notes)
+	}
+	return rc.index
+}
+
+// settings resolves the configuration of rule for relPath by layering
+// defaults, the rule's top-level config, then folder overrides from least
+// to most specific.
+func (rc *ruleContext) settings(rule *healthRule, relPath string) ruleSettings {
+	s := ruleSettings{Enabled: true, Severity: rule.DefaultSeverity, Options: ruleOptions{}}
+	for k, v := range rule.DefaultOptions {
+		s.Options[k] = v
+	}
+
+	apply := func(c ruleConfig) {
+		if c.Enabled != nil {
+			s.Enabled = *c.Enabled
+		}
+		if c.Severity != "" {
+			s.Severity = c.Severity
+		}
+		for k, v := range c.Options {
+			s.Options[k] = v
+		}
+	}
+
+	cfg, ok := rc.config.Rules[rule.ID]
+	if !ok {
+		return s
+	}
+	apply(cfg)
+	for _, folder := range matchingFolders(cfg.Folders, relPath) {
+		apply(cfg.Folders[folder])
+	}
+	if s.Severity == severityOff {
+		s.Enabled = false
+	}
+	return s
+}
+
+// validateHealthConfig rejects unknown rule IDs and severities so typos
+// in the config file don't silently disable checks.
+func validateHealthConfig(cfg healthConfig) error {
+	for id, c := range cfg.Rules {
+		if findHealthRule(id) == nil {
+			return fmt.Errorf("unknown health rule in config: %s", id)
+		}
+		if c.Severity != "" && !validSeverities[c.Severity] {
+			return fmt.Errorf("invalid severity %q for rule %s (valid: error, warn, info, off)", c.Severity, id)
+		}
+		for folder, fc := range c.Folders {
+			if fc.Severity != "" && !validSeverities[fc.Severity] {
+				return fmt.Errorf("invalid severity %q for rule %s in folder %s", fc.Severity, id, folder)
+			}
+		}
+	}
+	return nil
+}
+
+// runHealthRules runs every registered rule and applies per-file settings.
+// Issues are sorted by file, line, then rule.
+func runHealthRules(rc *ruleContext) []HealthIssue {
+	var issues []HealthIssue
+	for _, rule := range healthRules {
+		for _, issue := range rule.Check(rc, rule) {
+			s := rc.settings(rule, issue.File)
+			if !s.Enabled {
+				continue
+			}
+			issue.Rule = rule.ID
+			issue.Severity = s.Severity
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Rule < issues[j].Rule
+	})
+	return issues
+}
+
+var defaultForbiddenChars = `#^[]|\:*"<>?`
+
+func init() {
+	registerHealthRule(&healthRule{
+		ID:              "orphans",
+		Title:           "Orphans",
+		Description:     "Notes with no incoming links",
+		DefaultSeverity: severityWarn,
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, o := range rc.scan.Orphans {
+				issues = append(issues, HealthIssue{File: o, Message: "no incoming links"})
+			}
+			return issues
+		},
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "dead-links",
+		Title:           "Dead Links",
+		Description:     "Wikilinks pointing to notes or folders that don't exist",
+		DefaultSeverity: severityError,
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, dl := range rc.scan.DeadLinks {
+				issues = append(issues, HealthIssue{
+					File:    dl.SourceFile,
+					Line:    dl.Line,
+					Message: fmt.Sprintf("dead link [[%s]]", dl.Target),
+				})
+			}
+			return issues
+		},
+		Fix: fixDeadLinks,
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "missing-frontmatter",
+		Title:           "Frontmatter Issues",
+		Description:     "Notes without a YAML frontmatter block (options: template)",
+		DefaultSeverity: severityWarn,
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, f := range rc.scan.FrontmatterErrs {
+				issues = append(issues, HealthIssue{File: f, Line: 1, Message: "missing frontmatter"})
+			}
+			return issues
+		},
+		Fix: fixMissingFrontmatter,
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "broken-embeds",
+		Title:           "Broken Embeds",
+		Description:     "Embedded attachments (![[image.png]], ![](img/photo.jpg)) whose file doesn't exist",
+		DefaultSeverity: severityError,
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, m := range rc.assets.findMissing(rc.notes) {
+				msg := fmt.Sprintf("embedded file not found: %s", m.Target)
+				if len(m.Suggestions) > 0 {
+					msg += fmt.Sprintf(" (did you mean %s?)", m.Suggestions[0].Path)
+				}
+				issues = append(issues, HealthIssue{File: m.Source, Line: m.Line, Message: msg})
+			}
+			return issues
+		},
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "required-frontmatter",
+		Title:           "Missing Keys",
+		Description:     "Frontmatter keys required per folder (options: keys)",
+		DefaultSeverity: severityError,
+		DefaultOptions:  ruleOptions{"keys": []string{}},
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, note := range rc.notes {
+				keys := rc.settings(rule, note.RelPath).Options.Strings("keys")
+				for _, key := range keys {
+					if !note.Frontmatter.Has(key) {
+						issues = append(issues, HealthIssue{
+							File:    note.RelPath,
+							Line:    1,
+							Message: fmt.Sprintf("missing required frontmatter key %q", key),
+						})
+					}
+				}
+			}
+			return issues
+		},
+		Fix: fixRequiredFrontmatter,
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "max-note-size",
+		Title:           "Oversized Notes",
+		Description:     "Notes larger than a byte limit (options: max_bytes)",
+		DefaultSeverity: severityWarn,
+		DefaultOptions:  ruleOptions{"max_bytes": 512000},
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, note := range rc.notes {
+				limit := rc.settings(rule, note.RelPath).Options.Int("max_bytes", 512000)
+				if limit > 0 && note.Size > int64(limit) {
+					issues = append(issues, HealthIssue{
+						File:    note.RelPath,
+						Message: fmt.Sprintf("note is %s (limit %s)", humanizeBytes(note.Size), humanizeBytes(int64(limit))),
+					})
+				}
+			}
+			return issues
+		},
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "forbidden-filename-chars",
+		Title:           "Bad Filenames",
+		Description:     "Note filenames containing characters that break links or sync (options: chars)",
+		DefaultSeverity: severityWarn,
+		DefaultOptions:  ruleOptions{"chars": defaultForbiddenChars},
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, note := range rc.notes {
+				chars := rc.settings(rule, note.RelPath).Options.String("chars", defaultForbiddenChars)
+				if idx := strings.IndexAny(note.Name, chars); idx != -1 {
+					r, _ := utf8.DecodeRuneInString(note.Name[idx:])
+					issues = append(issues, HealthIssue{
+						File:    note.RelPath,
+						Message: fmt.Sprintf("filename contains forbidden character %q", string(r)),
+					})
+				}
+			}
+			return issues
+		},
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "empty-note",
+		Title:           "Empty Notes",
+		Description:     "Notes with no content besides frontmatter",
+		DefaultSeverity: severityInfo,
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, note := range rc.notes {
+				if strings.TrimSpace(note.Body) == "" {
+					issues = append(issues, HealthIssue{File: note.RelPath, Message: "note is empty"})
+				}
+			}
+			return issues
+		},
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "duplicate-titles",
+		Title:           "Duplicate Titles",
+		Description:     "Notes sharing a title (frontmatter title or filename), which makes [[links]] ambiguous",
+		DefaultSeverity: severityWarn,
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			byTitle := make(map[string][]*noteFile)
+			for _, note := range rc.notes {
+				title := note.Name
+				if t := note.Frontmatter.Values("title"); len(t) > 0 && t[0] != "" {
+					title = t[0]
+				}
+				key := strings.ToLower(strings.TrimSpace(title))
+				byTitle[key] = append(byTitle[key], note)
+			}
+
+			var issues []HealthIssue
+			for _, key := range sortedKeys(byTitle) {
+				group := byTitle[key]
+				if len(group) < 2 {
+					continue
+				}
+				for _, note := range group {
+					var others []string
+					for _, other := range group {
+						if other != note {
+							others = append(others, other.RelPath)
+						}
+					}
+					issues = append(issues, HealthIssue{
+						File:    note.RelPath,
+						Message: fmt.Sprintf("title %q also used by %s", key, strings.Join(others, ", ")),
+					})
+				}
+			}
+			return issues
+		},
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "link-case",
+		Title:           "Link Case",
+		Description:     "Wikilinks that only resolve case-insensitively ([[my note]] for My Note.md)",
+		DefaultSeverity: severityWarn,
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			ix := rc.noteIndex()
+			var issues []HealthIssue
+			for _, note := range rc.notes {
+				for _, l := range parseWikilinks(note.Content) {
+					if fixed, ok := caseCorrectTarget(ix, l.Target); ok {
+						issues = append(issues, HealthIssue{
+							File:    note.RelPath,
+							Line:    lineNumberAt(note.Content, l.Start),
+							Message: fmt.Sprintf("link [[%s]] should be [[%s]]", l.Target, fixed),
+						})
+					}
+				}
+			}
+			return issues
+		},
+		Fix: fixLinkCase,
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "trailing-whitespace",
+		Title:           "Trailing Whitespace",
+		Description:     "Lines ending in spaces or tabs (options: keep_hard_breaks)",
+		DefaultSeverity: severityInfo,
+		DefaultOptions:  ruleOptions{"keep_hard_breaks": true},
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, note := range rc.notes {
+				keep := rc.settings(rule, note.RelPath).Options.Bool("keep_hard_breaks", true)
+				if _, lines := trimTrailingWhitespace(note.Content, keep); len(lines) > 0 {
+					issues = append(issues, HealthIssue{
+						File:    note.RelPath,
+						Line:    lines[0],
+						Message: fmt.Sprintf("%d lines with trailing whitespace", len(lines)),
+					})
+				}
+			}
+			return issues
+		},
+		Fix: fixTrailingWhitespace,
+	})
+
+	registerHealthRule(&healthRule{
+		ID:              "tag-casing",
+		Title:           "Tag Casing",
+		Description:     "Tags spelled with different casing across the vault (options: case = majority|lower)",
+		DefaultSeverity: severityInfo,
+		DefaultOptions:  ruleOptions{"case": "majority"},
+		Check: func(rc *ruleContext, rule *healthRule) []HealthIssue {
+			var issues []HealthIssue
+			for _, note := range rc.notes {
+				mode := rc.settings(rule, note.RelPath).Options.String("case", "majority")
+				for _, occ := range noteTagOccurrences(note) {
+					if want := rc.canonicalTag(occ.Tag, mode); want != occ.Tag {
+						issues = append(issues, HealthIssue{
+							File:    note.RelPath,
+							Line:    occ.Line,
+							Message: fmt.Sprintf("tag #%s should be #%s", occ.Tag, want),
+						})
+					}
+				}
+			}
+			return issues
+		},
+		Fix: fixTagCasing,
+	})
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"testing"
+)
+
+// TestRuleSettingsFolderOverrides tests layering of rule config by folder specificity
+func TestRuleSettingsFolderOverrides(t *testing.T) {
+	disabled := false
+	rule := &healthRule{ID: "test-rule", DefaultSeverity: severityWarn, DefaultOptions: ruleOptions{"max": 10}}
+	rc := &ruleContext{config: healthConfig{Rules: map[string]ruleConfig{
+		"test-rule": {
+			Severity: severityError,
+			Options:  map[string]interface{}{"max": float64(20)},
+			Folders: map[string]ruleConfig{
+				"projects":         {Options: map[string]interface{}{"max": float64(30)}},
+				"projects/archive": {Enabled: &disabled},
+				"daily":            {Severity: severityOff},
+			},
+		},
+	}}}
+
+	tests := []struct {
+		name        string
+		relPath     string
+		wantEnabled bool
+		wantSev     string
+		wantMax     int
+	}{
+		{"root note uses top-level config", "note.md", true, severityError, 20},
+		{"folder override", "projects/plan.md", true, severityError, 30},
+		{"nested folder disables", "projects/archive/old.md", false, severityError, 30},
+		{"severity off disables", "daily/2024-01-01.md", false, severityOff, 20},
+		{"prefix is not a folder match", "projectsX/note.md", true, severityError, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := rc.settings(rule, tt.relPath)
+			if s.Enabled != tt.wantEnabled {
+				t.Errorf("Enabled = %v, want %v", s.Enabled, tt.wantEnabled)
+			}
+			if s.Severity != tt.wantSev {
+				t.Errorf("Severity = %q, want %q", s.Severity, tt.wantSev)
+			}
+			if got := s.Options.Int("max", 0); got != tt.wantMax {
+				t.Errorf("max = %d, want %d", got, tt.wantMax)
+			}
+		})
+	}
+}
+
+// TestValidateHealthConfig tests rejection of unknown rules and severities
+func TestValidateHealthConfig(t *testing.T) {
+	if err := validateHealthConfig(healthConfig{Rules: map[string]ruleConfig{"orphans": {Severity: severityInfo}}}); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+	if err := validateHealthConfig(healthConfig{Rules: map[string]ruleConfig{"no-such-rule": {}}}); err == nil {
+		t.Error("unknown rule accepted")
+	}
+	if err := validateHealthConfig(healthConfig{Rules: map[string]ruleConfig{"orphans": {Severity: "fatal"}}}); err == nil {
+		t.Error("invalid severity accepted")
+	}
+}
+
+// TestParseFrontmatter tests the frontmatter subset parser
+func TestParseFrontmatter(t *testing.T) {
+	content := "---\ntitle: \"My Note\"\ntags: [a, b]\naliases:\n  - first\n  - 'second'\nempty:\n---\nBody text\n"
+
+	fmText, body, ok := splitFrontmatter(content)
+	if !ok {
+		t.Fatal("splitFrontmatter found no frontmatter")
+	}
+	if body != "Body text\n" {
+		t.Errorf("body = %q, want %q", body, "Body text\n")
+	}
+
+	fm := parseFrontmatter(fmText)
+	if got := fm.Values("title"); len(got) != 1 || got[0] != "My Note" {
+		t.Errorf("title = %v, want [My Note]", got)
+	}
+	if got := fm.Values("tags"); len(got) != 2 || got[1] != "b" {
+		t.Errorf("tags = %v, want [a b]", got)
+	}
+	if got := fm.Values("aliases"); len(got) != 2 || got[1] != "second" {
+		t.Errorf("aliases = %v, want [first second]", got)
+	}
+	if fm.Has("empty") {
+		t.Error("empty key reported as present")
+	}
+	if fm.String() != fmText {
+		t.Errorf("round trip = %q, want %q", fm.String(), fmText)
+	}
+
+	fm.SetList("tags", []string{"c"})
+	if got := fm.Get("tags").Raw; len(got) != 2 || got[1] != "  - c" {
+		t.Errorf("SetList raw = %v", got)
+	}
+
+	if _, _, ok := splitFrontmatter("no frontmatter\n---\n"); ok {
+		t.Error("splitFrontmatter matched content without leading ---")
+	}
+}
+
+// TestHealthFixHelpers tests the text transforms used by health --fix
+func TestHealthFixHelpers(t *testing.T) {
+	ix := newNoteIndex([]string{"My Note.md", "projects/Plan.md"})
+
+	caseTests := []struct {
+		target string
+		want   string
+		ok     bool
+	}{
+		{"my note", "My Note", true},
+		{"My Note", "", false},
+		{"/PROJECTS/plan.md", "/projects/Plan.md", true},
+		{"missing", "", false},
+	}
+	for _, tt := range caseTests {
+		got, ok := caseCorrectTarget(ix, tt.target)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("caseCorrectTarget(%q) = %q, %v; want %q, %v", tt.target, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	text := "a  \nb \t\r\n  \nc"
+	if got, lines := trimTrailingWhitespace(text, true); got != "a  \nb\r\n\nc" || len(lines) != 2 {
+		t.Errorf("trimTrailingWhitespace(keep) = %q, %v", got, lines)
+	}
+	if got, _ := trimTrailingWhitespace(text, false); got != "a\nb\r\n\nc" {
+		t.Errorf("trimTrailingWhitespace = %q", got)
+	}
+
+	yamlTests := []struct{ line, want string }{
+		{"tags: [Work, work-log, Work]", "tags: [work, work-log, work]"},
+		{"  - Work", "  - work"},
+		{`tags: "#Work"`, `tags: "#work"`},
+		{"Work: Work", "Work: work"},
+	}
+	for _, tt := range yamlTests {
+		if got := replaceYAMLTag(tt.line, "Work", "work"); got != tt.want {
+			t.Errorf("replaceYAMLTag(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+// TestHighestSeverity tests that a rule's level is its most severe issue
+func TestHighestSeverity(t *testing.T) {
+	issues := []HealthIssue{{Severity: severityInfo}, {Severity: severityError}, {Severity: severityWarn}}
+	if got := highestSeverity(issues); got != severityError {
+		t.Errorf("highestSeverity = %s, want error", got)
+	}
+	if got := highestSeverity(issues[:1]); got != severityInfo {
+		t.Errorf("highestSeverity = %s, want info", got)
+	}
+}
+
+// TestForbiddenFilenameChars tests that a multibyte forbidden character is
+// reported whole
+func TestForbiddenFilenameChars(t *testing.T) {
+	writeHealthVault(t, map[string]string{
+		defaultConfigName: `{"health": {"rules": {"forbidden-filename-chars": {"options": {"chars": "→#"}}}}}`,
+		"a → b.md":        "---\n---\n[[a → b]]\n",
+	})
+	report, _, err := checkVaultHealth(healthCmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range report.Issues {
+		if issue.Rule == "forbidden-filename-chars" {
+			if want := `filename contains forbidden character "→"`; issue.Message != want {
+				t.Errorf("message = %q, want %q", issue.Message, want)
+			}
+			return
+		}
+	}
+	t.Errorf("issues = %+v, want a forbidden-filename-chars issue", report.Issues)
+}
@@ -0,0 +1,467 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// htmlConverter converts HTML, such as Evernote's ENML or a notes app's
+// HTML export, into markdown.
+type htmlConverter struct {
+	// media returns the markdown for an <img> or <en-media> element, or ""
+	// to drop it. Nil keeps images as markdown images.
+	media func(n *html.Node) string
+	// link returns the markdown for a link to href with the given text, or
+	// "" to write a markdown link. May be nil.
+	link func(href, text string) string
+}
+
+// Block-level elements; anything else is rendered inline
+var htmlBlockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true,
+	atom.Body: true, atom.Center: true, atom.Dd: true, atom.Details: true, atom.Div: true,
+	atom.Dl: true, atom.Dt: true, atom.Figcaption: true, atom.Figure: true, atom.Footer: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Header: true, atom.Hr: true, atom.Li: true, atom.Main: true, atom.Nav: true,
+	atom.Ol: true, atom.P: true, atom.Pre: true, atom.Section: true, atom.Summary: true,
+	atom.Table: true, atom.Ul: true,
+}
+
+var (
+	htmlSpaceRegex     = regexp.MustCompile(`[ \t\r\n\f]+`)
+	htmlBlankLineRegex = regexp.MustCompile(`\n{3,}`)
+	// Characters escaped in text so they aren't read as markdown
+	htmlTextEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`)
+)
+
+// htmlBlock is a rendered block. Consecutive tight blocks (the <div> per
+// line that note apps write) are separated by a line break rather than a
+// blank line.
+type htmlBlock struct {
+	text  string
+	tight bool
+}
+
+// convert renders the children of n as markdown.
+func (c *htmlConverter) convert(n *html.Node) string {
+	out := joinHTMLBlocks(c.blocks(n), "\n\n")
+	out = htmlBlankLineRegex.ReplaceAllString(out, "\n\n")
+	out = strings.Trim(out, "\n")
+	if out == "" {
+		return ""
+	}
+	return out + "\n"
+}
+
+// blocks renders the children of n, wrapping runs of inline content in
+// paragraphs.
+func (c *htmlConverter) blocks(n *html.Node) []htmlBlock {
+	var blocks []htmlBlock
+	var run strings.Builder
+	flush := func() {
+		if text := trimHTMLLines(run.String()); text != "" {
+			blocks = append(blocks, htmlBlock{text: text})
+		}
+		run.Reset()
+	}
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		if ch.Type == html.ElementNode && c.isBlock(ch) {
+			flush()
+			blocks = append(blocks, c.block(ch)...)
+			continue
+		}
+		run.WriteString(c.inline(ch))
+	}
+	flush()
+	return blocks
+}
+
+// isBlock reports whether an element is rendered as a block.
+func (c *htmlConverter) isBlock(n *html.Node) bool {
+	return htmlBlockElements[n.DataAtom] || n.Data == "en-note"
+}
+
+// block renders a block-level element.
+func (c *htmlConverter) block(n *html.Node) []htmlBlock {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := strings.Join(strings.Fields(c.inlineChildren(n)), " ")
+		if text == "" {
+			return nil
+		}
+		return []htmlBlock{{text: strings.Repeat("#", int(n.Data[1]-'0')) + " " + text}}
+	case atom.Hr:
+		return []htmlBlock{{text: "---"}}
+	case atom.Pre:
+		return []htmlBlock{{text: codeFence(htmlText(n))}}
+	case atom.Blockquote:
+		return []htmlBlock{{text: prefixLines(joinHTMLBlocks(c.blocks(n), "\n\n"), "> ", ">")}}
+	case atom.Ul, atom.Ol:
+		return []htmlBlock{{text: c.list(n)}}
+	case atom.Table:
+		return []htmlBlock{{text: c.table(n)}}
+	case atom.Div:
+		// Evernote writes code blocks as styled divs
+		if strings.Contains(htmlAttr(n, "style"), "-en-codeblock:true") {
+			return []htmlBlock{{text: codeFence(htmlText(n))}}
+		}
+		blocks := c.blocks(n)
+		if len(blocks) == 0 {
+			// <div><br></div> is an empty line
+			return []htmlBlock{{tight: true}}
+		}
+		if len(blocks) == 1 && !c.hasBlockChild(n) {
+			blocks[0].tight = true
+		}
+		return blocks
+	}
+	return c.blocks(n)
+}
+
+// hasBlockChild reports whether n has a block-level child element.
+func (c *htmlConverter) hasBlockChild(n *html.Node) bool {
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		if ch.Type == html.ElementNode && c.isBlock(ch) {
+			return true
+		}
+	}
+	return false
+}
+
+// list renders a list; nested lists are indented under their item.
+func (c *htmlConverter) list(n *html.Node) string {
+	var items []string
+	i := 1
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", i)
+			i++
+		}
+		var content string
+		if li.DataAtom == atom.Li {
+			content = joinHTMLBlocks(c.blocks(li), "\n")
+		} else {
+			// A nested list directly in the list
+			content = joinHTMLBlocks(c.block(li), "\n")
+			if li.DataAtom == atom.Ul || li.DataAtom == atom.Ol {
+				items = append(items, prefixLines(content, "\t", ""))
+				continue
+			}
+		}
+		items = append(items, marker+indentRest(content, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders a table; the first row is the header.
+func (c *htmlConverter) table(n *html.Node) string {
+	var rows [][]string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+			if ch.Type != html.ElementNode {
+				continue
+			}
+			if ch.DataAtom != atom.Tr {
+				walk(ch)
+				continue
+			}
+			var row []string
+			for cell := ch.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+					text := joinHTMLBlocks(c.blocks(cell), "\n")
+					row = append(row, strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(text))
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	walk(n)
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	if width == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for i, row := range rows {
+		cells := make([]string, width)
+		copy(cells, row)
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// inlineChildren renders the children of n as inline content.
+func (c *htmlConverter) inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		b.WriteString(c.inline(ch))
+	}
+	return b.String()
+}
+
+// inline renders a node as inline content. Runs of whitespace collapse to
+// one space; line breaks are kept.
+func (c *htmlConverter) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		text := strings.ReplaceAll(n.Data, "\u00a0", " ")
+		return escapeHTMLUnderscores(htmlTextEscaper.Replace(htmlSpaceRegex.ReplaceAllString(text, " ")))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return "\n"
+	case atom.Script, atom.Style, atom.Head, atom.Title:
+		return ""
+	case atom.Img:
+		if c.media != nil {
+			return c.media(n)
+		}
+		return fmt.Sprintf("![%s](%s)", htmlAttr(n, "alt"), htmlAttr(n, "src"))
+	case atom.Strong, atom.B:
+		return wrapInline(c.inlineChildren(n), "**")
+	case atom.Em, atom.I, atom.Cite:
+		return wrapInline(c.inlineChildren(n), "*")
+	case atom.S, atom.Strike, atom.Del:
+		return wrapInline(c.inlineChildren(n), "~~")
+	case atom.Mark:
+		return wrapInline(c.inlineChildren(n), "==")
+	case atom.Code, atom.Kbd, atom.Tt:
+		text := htmlText(n)
+		if strings.TrimSpace(text) == "" {
+			return text
+		}
+		fence := "`"
+		for strings.Contains(text, fence) {
+			fence += "`"
+		}
+		return fence + text + fence
+	case atom.A:
+		text := c.inlineChildren(n)
+		href := htmlAttr(n, "href")
+		if href == "" || strings.TrimSpace(text) == "" {
+			return text
+		}
+		if c.link != nil {
+			if link := c.link(href, text); link != "" {
+				return link
+			}
+		}
+		if strings.ContainsAny(href, " ()") {
+			href = "<" + href + ">"
+		}
+		return "[" + strings.TrimSpace(text) + "](" + href + ")"
+	case atom.Span, atom.Font:
+		// Note apps format with inline styles
+		text := c.inlineChildren(n)
+		style := strings.ReplaceAll(strings.ToLower(htmlAttr(n, "style")), " ", "")
+		if strings.Contains(style, "--en-highlight") {
+			text = wrapInline(text, "==")
+		}
+		if strings.Contains(style, "font-weight:bold") || strings.Contains(style, "font-weight:700") {
+			text = wrapInline(text, "**")
+		}
+		if strings.Contains(style, "font-style:italic") {
+			text = wrapInline(text, "*")
+		}
+		if strings.Contains(style, "line-through") {
+			text = wrapInline(text, "~~")
+		}
+		return text
+	}
+
+	switch n.Data {
+	case "en-media":
+		if c.media != nil {
+			return c.media(n)
+		}
+		return ""
+	case "en-todo":
+		box := "[ ] "
+		if htmlAttr(n, "checked") == "true" {
+			box = "[x] "
+		}
+		// Outside a list, the checkbox starts a task item
+		for p := n.Parent; p != nil; p = p.Parent {
+			if p.DataAtom == atom.Li {
+				return box
+			}
+		}
+		return "- " + box
+	case "en-crypt":
+		return "`[encrypted content not imported]`"
+	}
+	if c.isBlock(n) {
+		// A block inside inline content, such as a div in a link
+		return "\n" + joinHTMLBlocks(c.block(n), "\n") + "\n"
+	}
+	return c.inlineChildren(n)
+}
+
+// escapeHTMLUnderscores escapes the underscores of text that can start or
+// end emphasis. Underscores inside a word, as in snake_case, can't and are
+// kept as they are.
+func escapeHTMLUnderscores(text string) string {
+	if !strings.Contains(text, "_") {
+		return text
+	}
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	runes := []rune(text)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '_' && !(i > 0 && isWord(runes[i-1]) && i+1 < len(runes) && isWord(runes[i+1])) {
+			b.WriteString(`\`)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// joinHTMLBlocks joins blocks with sep, using a line break between tight
+// blocks.
+func joinHTMLBlocks(blocks []htmlBlock, sep string) string {
+	var b strings.Builder
+	for i, block := range blocks {
+		if i > 0 {
+			if block.tight && blocks[i-1].tight {
+				b.WriteString("\n")
+			} else {
+				b.WriteString(sep)
+			}
+		}
+		b.WriteString(block.text)
+	}
+	return b.String()
+}
+
+// wrapInline wraps text in a markdown delimiter, keeping surrounding
+// spaces outside it: "** bold **" doesn't render.
+func wrapInline(text, delim string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := strings.Index(text, trimmed)
+	return text[:start] + delim + trimmed + delim + text[start+len(trimmed):]
+}
+
+// trimHTMLLines trims the spaces around each line of a paragraph and the
+// blank lines around it.
+func trimHTMLLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// htmlText returns the text of n, with line breaks for <br> and after
+// block elements.
+func htmlText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(strings.ReplaceAll(n.Data, "\u00a0", " "))
+		case n.DataAtom == atom.Br:
+			b.WriteString("\n")
+		default:
+			for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+				walk(ch)
+			}
+			if n.Type == html.ElementNode && htmlBlockElements[n.DataAtom] && !strings.HasSuffix(b.String(), "\n") {
+				b.WriteString("\n")
+			}
+		}
+	}
+	walk(n)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// htmlAttr returns the value of an attribute of n.
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// findHTMLElement returns the first element named name in n, or nil.
+func findHTMLElement(n *html.Node, name string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == name {
+		return n
+	}
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		if found := findHTMLElement(ch, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findHTMLElements returns the elements named name in n.
+func findHTMLElements(n *html.Node, name string) []*html.Node {
+	var found []*html.Node
+	if n.Type == html.ElementNode && n.Data == name {
+		found = append(found, n)
+	}
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		found = append(found, findHTMLElements(ch, name)...)
+	}
+	return found
+}
+
+// codeFence wraps code in a fence longer than any backtick run in it.
+func codeFence(code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + code + "\n" + fence
+}
+
+// prefixLines prefixes each line of text, using blank for empty lines.
+func prefixLines(text, prefix, blank string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = blank
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentRest indents every line of text but the first.
+func indentRest(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// TestHTMLConverter tests the conversion of links, images, code, tables
+// and escaped text
+func TestHTMLConverter(t *testing.T) {
+	tests := []struct {
+		name, html, want string
+	}{
+		{"links", `<p><a href="https://example.com/a b">site</a> <a href="x (1).html">x</a> <a href="">bare</a></p>`,
+			"[site](<https://example.com/a b>) [x](<x (1).html>) bare\n"},
+		{"images", `<p><img src="img/a.png" alt="A"> text</p>`, "![A](img/a.png) text\n"},
+		{"code", "<p>run <code>a`b</code></p><pre><code>x := 1\n```\n</code></pre>",
+			"run ``a`b``\n\n````\nx := 1\n```\n````\n"},
+		{"evernote code", `<div style="-en-codeblock:true"><div>a</div><div>b</div></div>`, "```\na\nb\n```\n"},
+		{"table", `<table><tr><th>A</th><th>B</th></tr><tr><td>1|2</td><td>x<br>y</td></tr><tr><td>3</td></tr></table>`,
+			"| A | B |\n| --- | --- |\n| 1\\|2 | x<br>y |\n| 3 |  |\n"},
+		{"formatting", `<p><b>bold</b> <i>it </i><span style="font-weight: bold">s</span> <del>x</del></p>`,
+			"**bold** *it* **s** ~~x~~\n"},
+		{"escaping", `<p>*a* [b] \c` + "`d`" + ` &lt;e&gt; _f_ snake_case __init__ x_</p>`,
+			"\\*a\\* \\[b\\] \\\\c\\`d\\` \\<e> \\_f\\_ snake_case \\_\\_init\\_\\_ x\\_\n"},
+		{"lists", `<ol><li>a<ul><li>b</li></ul></li><li>c</li></ol>`, "1. a\n   - b\n2. c\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := html.Parse(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := &htmlConverter{}
+			if got := c.convert(findHTMLElement(root, "body")); got != tt.want {
+				t.Errorf("convert = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kofifort/obsidian-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import notes from other apps",
+	Long: `Converts notes exported from other apps into Obsidian markdown and adds
+them to a folder of the vault (--into).
+
+Links between imported notes become wikilinks, written the shortest way
+that resolves in the vault. Existing files are never overwritten: notes
+whose path is taken are skipped and listed. Links that don't resolve to
+a note or file in the vault after the import are reported. Imports are
+journaled (revert with "undo").`,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+}
+
+// importOptions are the flags shared by import subcommands.
+type importOptions struct {
+	Into   string
+	DryRun bool
+	Format string
+}
+
+// addImportFlags registers the flags shared by import subcommands.
+func addImportFlags(cmd *cobra.Command, opts *importOptions, defaultFolder string) {
+	cmd.Flags().StringVar(&opts.Into, "into", defaultFolder, "Vault folder to import into")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Preview the import without writing files")
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format: text, json")
+}
+
+// importSet collects the notes and files produced by an importer, at
+// slash-separated paths relative to the import folder, or to the vault
+// root when they start with "/". Links between imported notes are written
+// as wikilinks to these paths.
+type importSet struct {
+	notes  map[string]string    // Path (with .md) -> content
+	files  map[string][]byte    // Path -> data
+	taken  map[string]bool      // Lowercase paths, notes without .md
+	mtimes map[string]time.Time // Path (with .md) -> modification time to set
+
+	// Vault and import folder, for addAttachment
+	absPath string
+	folder  string
+}
+
+func newImportSet() *importSet {
+	return &importSet{
+		notes:  make(map[string]string),
+		files:  make(map[string][]byte),
+		taken:  make(map[string]bool),
+		mtimes: make(map[string]time.Time),
+	}
+}
+
+// reserve claims a path for a note (without .md) or file, adding " 2",
+// " 3"... before the extension when it is already taken. Callers reserve
+// in a sorted order so the numbering is deterministic.
+func (s *importSet) reserve(p string, isNote bool) string {
+	ext := ""
+	if !isNote {
+		ext = path.Ext(p)
+	}
+	base := strings.TrimSuffix(p, ext)
+	candidate := p
+	for i := 2; s.taken[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s %d%s", base, i, ext)
+	}
+	s.taken[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// addAttachment adds a file named name in dir, numbering the name when it
+// is taken by a different file. A file with the same content, imported or
+// already in the vault, is reused. Returns the file's path.
+func (s *importSet) addAttachment(dir, name string, data []byte) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		candidate := path.Join(dir, name)
+		if i > 1 {
+			candidate = path.Join(dir, fmt.Sprintf("%s %d%s", base, i, ext))
+		}
+		key := strings.ToLower(candidate)
+		if s.taken[key] {
+			if existing, ok := s.files[candidate]; ok && bytes.Equal(existing, data) {
+				return candidate
+			}
+			continue
+		}
+		if s.absPath != "" {
+			if existing, err := os.ReadFile(filepath.Join(s.absPath, filepath.FromSlash(s.vaultPath(candidate)))); err == nil {
+				if !bytes.Equal(existing, data) {
+					continue
+				}
+				// Link to the existing file without writing it
+				s.taken[key] = true
+				return candidate
+			}
+		}
+		s.taken[key] = true
+		s.files[candidate] = data
+		return candidate
+	}
+}
+
+// vaultPath returns the vault-relative path of an import path.
+func (s *importSet) vaultPath(p string) string {
+	if rooted, ok := strings.CutPrefix(p, "/"); ok {
+		return rooted
+	}
+	return path.Join(s.folder, p)
+}
+
+// attachmentDir returns the folder for the attachments of the note at
+// import path notePath, following Obsidian's attachment folder setting:
+// "/" is the vault root, "./" the note's folder, "./sub" a subfolder of
+// it, anything else a vault folder.
+func attachmentDir(setting, notePath string) string {
+	setting = strings.TrimSpace(setting)
+	switch {
+	case setting == "" || setting == "/":
+		return "/"
+	case setting == "." || setting == "./":
+		return path.Dir(notePath)
+	case strings.HasPrefix(setting, "./"):
+		return path.Join(path.Dir(notePath), setting[2:])
+	default:
+		return "/" + strings.Trim(setting, "/")
+	}
+}
+
+// importFolder returns the cleaned, slash-separated import folder.
+func importFolder(opts *importOptions) string {
+	folder := strings.Trim(filepath.ToSlash(filepath.Clean(opts.Into)), "/")
+	if folder == "." {
+		return ""
+	}
+	return folder
+}
+
+// Characters that can't appear in a file name on common filesystems, plus
+// the ones that break wikilinks
+const invalidImportChars = invalidStubChars + "/#^[]"
+
+// sanitizeImportName makes a note or file name from a title.
+func sanitizeImportName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(invalidImportChars, r) || r < ' ' {
+			return '-'
+		}
+		return r
+	}, name)
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if name == "" {
+		return "Untitled"
+	}
+	return name
+}
+
+// sanitizeImportPath sanitizes each segment of a slash-separated path.
+func sanitizeImportPath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = sanitizeImportName(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// ImportSkip is an imported note or file that was not written.
+type ImportSkip struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// ImportResult is the result of an import.
+type ImportResult struct {
+	Source     string         `json:"source"`
+	Folder     string         `json:"folder"`
+	Notes      []string       `json:"notes"`
+	Files      []string       `json:"files"`
+	Skipped    []ImportSkip   `json:"skipped"`
+	Unresolved []jsonDeadLink `json:"unresolved_links"`
+	Executed   bool           `json:"executed"`
+	JournalID  string         `json:"journal_id,omitempty"`
+}
+
+// runImport writes an import set into opts.Into and reports the result.
+// source names the app, for messages.
+func runImport(cmd *cobra.Command, absPath, source string, set *importSet, opts *importOptions) error {
+	folder := importFolder(opts)
+	set.folder = folder
+	if !isPathWithinVault(filepath.Join(absPath, filepath.FromSlash(folder)), absPath) {
+		return fmt.Errorf("import folder escapes vault boundary: %s", opts.Into)
+	}
+	inFolder := set.vaultPath
+
+	// Index the vault as it will be after the import
+	existing, err := collectMarkdownFiles(absPath)
+	if err != nil {
+		return err
+	}
+	notePaths := make([]string, 0, len(existing)+len(set.notes))
+	for _, f := range existing {
+		notePaths = append(notePaths, mustRelPath(absPath, f))
+	}
+	assetPaths, err := collectAssetFiles(absPath)
+	if err != nil {
+		return err
+	}
+
+	result := &ImportResult{Source: source, Folder: folder, Notes: []string{}, Files: []string{}, Skipped: []ImportSkip{}, Executed: !opts.DryRun}
+	writes := make(map[string][]byte)
+	mtimes := make(map[string]time.Time)
+	sources := make(map[string]string) // Vault path -> import path
+	for _, p := range sortedKeys(set.notes) {
+		rel := inFolder(p)
+		if _, err := os.Lstat(filepath.Join(absPath, filepath.FromSlash(rel))); err == nil {
+			result.Skipped = append(result.Skipped, ImportSkip{rel, "file already exists"})
+			continue
+		}
+		result.Notes = append(result.Notes, rel)
+		notePaths = append(notePaths, rel)
+		sources[rel] = p
+		if t, ok := set.mtimes[p]; ok {
+			mtimes[rel] = t
+		}
+	}
+	for _, p := range sortedKeys(set.files) {
+		rel := inFolder(p)
+		if _, err := os.Lstat(filepath.Join(absPath, filepath.FromSlash(rel))); err == nil {
+			result.Skipped = append(result.Skipped, ImportSkip{rel, "file already exists"})
+			continue
+		}
+		result.Files = append(result.Files, rel)
+		assetPaths = append(assetPaths, rel)
+		writes[rel] = set.files[p]
+	}
+	notes, assets := newNoteIndex(notePaths), newNoteIndex(assetPaths)
+
+	// Point links between imported notes at their new paths, then report
+	// the links that still don't resolve
+	var unresolved []vault.DeadLink
+	for _, rel := range result.Notes {
+		content := rewriteImportLinks(set.notes[sources[rel]], func(l wikilink) (string, bool) {
+			if set.taken[strings.ToLower(l.Target)] {
+				target := inFolder(l.Target)
+				if p, ok := notes.resolve(target); ok && strings.EqualFold(p, target) {
+					l.Target = notes.linkText(p)
+				} else if a, ok := assets.resolve(target); ok {
+					l.Target = assets.linkText(a)
+				}
+				return l.String(), true
+			}
+			return "", false
+		})
+		writes[rel] = []byte(content)
+		forEachContentLine(content, func(line string, lineNum int) {
+			for _, l := range parseWikilinks(line) {
+				if l.Target == "" || vault.IsExternalLink(l.Target) {
+					continue
+				}
+				if _, ok := notes.resolve(l.Target); ok {
+					continue
+				}
+				if _, ok := assets.resolve(l.Target); ok {
+					continue
+				}
+				unresolved = append(unresolved, vault.DeadLink{SourceFile: rel, Target: l.Target, Line: lineNum})
+			}
+			for _, m := range markdownLinkRegex.FindAllStringSubmatchIndex(line, -1) {
+				ml := parseMarkdownLink(line, m)
+				if ml.Target == "" || strings.Contains(ml.Dest, "://") || strings.HasPrefix(ml.Dest, "mailto:") {
+					continue
+				}
+				if !resolvesMarkdownLink(notes, assets, rel, ml.Target) {
+					unresolved = append(unresolved, vault.DeadLink{SourceFile: rel, Target: ml.Target, Line: lineNum})
+				}
+			}
+		})
+	}
+	result.Unresolved = toJSONDeadLinks(unresolved, nil)
+
+	if !opts.DryRun && len(writes) > 0 {
+		if result.JournalID, err = writeImport(absPath, source, result, writes, mtimes); err != nil {
+			return err
+		}
+	}
+	if opts.Format == "json" {
+		return encodeJSON(cmd, result)
+	}
+	printImportResult(result, unresolved, opts.DryRun)
+	return nil
+}
+
+// rewriteImportLinks rewrites the wikilinks of a note outside code.
+func rewriteImportLinks(content string, fn func(l wikilink) (string, bool)) string {
+	return mapOutsideFences(content, 1, func(chunk string, _ int) string {
+		return mapOutsideInlineCode(chunk, 1, func(piece string, _ int) string {
+			out, _ := rewriteWikilinks(piece, fn)
+			return out
+		})
+	})
+}
+
+// resolvesMarkdownLink reports whether the decoded destination of a
+// markdown link in note src names a note or file, relative to the note or
+// from the vault root.
+func resolvesMarkdownLink(notes, assets *noteIndex, src, target string) bool {
+	candidates := []string{path.Join(path.Dir(src), target), strings.TrimPrefix(target, "/")}
+	for _, cand := range candidates {
+		if ext := strings.ToLower(path.Ext(cand)); ext == ".md" || ext == "" {
+			if _, ok := notes.resolve(cand); ok {
+				return true
+			}
+		} else if _, ok := assets.resolve(cand); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writeImport writes the imported notes and files through a journal, then
+// sets the modification times the source app recorded. Returns the
+// journal ID.
+func writeImport(absPath, source string, result *ImportResult, writes map[string][]byte, mtimes map[string]time.Time) (journalID string, err error) {
+	j := newJournal(absPath, "import", fmt.Sprintf("import %d notes and %d files from %s", len(result.Notes), len(result.Files), source))
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	for _, rel := range sortedKeys(writes) {
+		// Re-check right before writing; never overwrite
+		if _, err := os.Lstat(filepath.Join(absPath, filepath.FromSlash(rel))); err == nil {
+			return j.ID, fmt.Errorf("file already exists: %s", rel)
+		}
+		if err := j.writeFile(filepath.FromSlash(rel), writes[rel], 0644); err != nil {
+			return j.ID, err
+		}
+		if t, ok := mtimes[rel]; ok {
+			if err := os.Chtimes(filepath.Join(absPath, filepath.FromSlash(rel)), t, t); err != nil {
+				return j.ID, err
+			}
+		}
+	}
+	return j.ID, nil
+}
+
+// printImportResult prints the imported paths, skipped files and
+// unresolved links.
+func printImportResult(result *ImportResult, unresolved []vault.DeadLink, dryRun bool) {
+	title := "Import from " + result.Source
+	if dryRun {
+		title += " (dry run)"
+	}
+	fmt.Printf("%s %s %s\n\n", colors.Green("→"), title,
+		colors.Dim(fmt.Sprintf("(%d notes, %d files)", len(result.Notes), len(result.Files))))
+	for _, p := range result.Notes {
+		fmt.Printf("  %s %s\n", colors.Green("+"), p)
+	}
+	for _, p := range result.Files {
+		fmt.Printf("  %s %s\n", colors.Green("+"), colors.Dim(p))
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Printf("\n  %s\n", colors.Yellow("Skipped:"))
+		for _, s := range result.Skipped {
+			fmt.Printf("    %s %s\n", s.Path, colors.Dim(s.Reason))
+		}
+	}
+	fmt.Println()
+
+	if len(unresolved) > 0 {
+		fmt.Printf("%s Unresolved Links %s\n\n", colors.Red("!"), colors.Dim(fmt.Sprintf("(%d total)", len(unresolved))))
+		printDeadLinksBySource(unresolved, nil)
+	}
+
+	if len(result.Notes)+len(result.Files) == 0 {
+		return
+	}
+	if dryRun {
+		fmt.Printf("  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
+		return
+	}
+	fmt.Printf("  %s Imported %d notes and %d files into %s\n", colors.Green("✓"), len(result.Notes), len(result.Files), result.Folder)
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n\n", colors.Dim("i"), result.JournalID)
+}
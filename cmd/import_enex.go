@@ -0,0 +1,364 @@
+package cmd
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/html"
+)
+
+var importEnexOptions importOptions
+
+var importEnexCmd = &cobra.Command{
+	Use:   "enex <file.enex|folder>...",
+	Short: "Import Evernote ENEX exports",
+	Long: `Imports notebooks exported from Evernote as .enex files. A folder imports
+every .enex file in it.
+
+  - Each notebook (.enex file) becomes a folder, each note a markdown note
+    named after its title; notes with the same title are numbered in order
+    of creation ("Title", "Title 2")
+  - Formatting, lists, checklists, tables and code blocks are converted
+  - Attachments are decoded into the vault's attachment folder (Settings →
+    Files and links) and embedded where they appear; a file already in the
+    vault with the same content is reused
+  - Tags, author and source URL go into frontmatter, with the created and
+    updated dates; the updated date also becomes the file's modification time
+  - Links to other Evernote notes become wikilinks to their title
+
+Examples:
+  obsidian-cli import enex Work.enex --vault ~/notes --dry-run
+  obsidian-cli import enex ~/Exports --vault ~/notes --into Evernote`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runImportEnex,
+}
+
+func init() {
+	importCmd.AddCommand(importEnexCmd)
+	addImportFlags(importEnexCmd, &importEnexOptions, "Evernote")
+}
+
+// enexNote is a note in an ENEX export.
+type enexNote struct {
+	Title      string   `xml:"title"`
+	Content    string   `xml:"content"`
+	Created    string   `xml:"created"`
+	Updated    string   `xml:"updated"`
+	Tags       []string `xml:"tag"`
+	Attributes struct {
+		Author    string `xml:"author"`
+		SourceURL string `xml:"source-url"`
+	} `xml:"note-attributes"`
+	Resources []enexResource `xml:"resource"`
+
+	notebook string
+}
+
+// enexResource is an attachment of an ENEX note.
+type enexResource struct {
+	Data       string `xml:"data"` // Base64
+	Mime       string `xml:"mime"`
+	Attributes struct {
+		FileName string `xml:"file-name"`
+	} `xml:"resource-attributes"`
+}
+
+// ENEX timestamp layout: 20261018T093000Z
+const enexTimeLayout = "20060102T150405Z"
+
+// Matches the self-closing ENML elements, which an HTML parser would
+// leave open
+var enmlSelfClosingRegex = regexp.MustCompile(`<(en-media|en-todo)\b([^>]*?)\s*/>`)
+
+func runImportEnex(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	settings, err := loadObsidianAppSettings(absPath)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	for _, arg := range args {
+		found, err := collectImportFiles(arg, ".enex")
+		if err != nil {
+			return err
+		}
+		files = append(files, found...)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no .enex files found")
+	}
+	var notes []*enexNote
+	for _, file := range files {
+		parsed, err := readEnexFile(file)
+		if err != nil {
+			return err
+		}
+		notes = append(notes, parsed...)
+	}
+
+	set := newImportSet()
+	set.absPath, set.folder = absPath, importFolder(&importEnexOptions)
+	if importEnexOptions.Format == "text" {
+		printScanHeader(fmt.Sprintf("Importing %d notes from %d notebooks", len(notes), len(files)))
+	}
+	convertEnexNotes(set, notes, settings.AttachmentFolderPath)
+	return runImport(cmd, absPath, "Evernote", set, &importEnexOptions)
+}
+
+// collectImportFiles returns src if it is a file, or the files with the
+// given extension in the folder src, sorted.
+func collectImportFiles(src string, exts ...string) ([]string, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{src}, nil
+	}
+	var files []string
+	err = filepath.WalkDir(src, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && p != src {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, ext := range exts {
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ext) {
+				files = append(files, p)
+			}
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// readEnexFile reads the notes of an ENEX file. The notebook is named
+// after the file.
+func readEnexFile(file string) ([]*enexNote, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	notebook := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	var notes []*enexNote
+	d := xml.NewDecoder(f)
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid ENEX file %s: %w", file, err)
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "note" {
+			note := &enexNote{notebook: notebook}
+			if err := d.DecodeElement(note, &start); err != nil {
+				return nil, fmt.Errorf("invalid ENEX file %s: %w", file, err)
+			}
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+// convertEnexNotes adds ENEX notes to an import set. Attachments go to
+// the attachment folder given by the vault's setting.
+func convertEnexNotes(set *importSet, notes []*enexNote, attachmentSetting string) {
+	// Same titles are numbered in order of creation
+	sort.SliceStable(notes, func(i, j int) bool {
+		a, b := notes[i], notes[j]
+		if a.notebook != b.notebook {
+			return a.notebook < b.notebook
+		}
+		if ta, tb := strings.ToLower(a.Title), strings.ToLower(b.Title); ta != tb {
+			return ta < tb
+		}
+		return a.Created < b.Created
+	})
+	paths := make([]string, len(notes))
+	byTitle := make(map[string]string) // Lowercase title -> path of its first note
+	for i, note := range notes {
+		paths[i] = set.reserve(sanitizeImportName(note.notebook)+"/"+sanitizeImportName(note.Title), true)
+		if title := strings.ToLower(strings.TrimSpace(note.Title)); byTitle[title] == "" {
+			byTitle[title] = paths[i]
+		}
+	}
+
+	for i, note := range notes {
+		p := paths[i]
+		resources := make(map[string]*enexResource) // MD5 -> resource
+		data := make(map[string][]byte)
+		var order []string
+		for j := range note.Resources {
+			r := &note.Resources[j]
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(r.Data))
+			if err != nil {
+				continue
+			}
+			sum := md5.Sum(decoded)
+			hash := hex.EncodeToString(sum[:])
+			if _, ok := resources[hash]; !ok {
+				order = append(order, hash)
+			}
+			resources[hash], data[hash] = r, decoded
+		}
+
+		used := make(map[string]bool)
+		attach := func(hash string) string {
+			r := resources[hash]
+			name := r.Attributes.FileName
+			if name == "" {
+				name = note.Title + enexExtension(r.Mime)
+			}
+			used[hash] = true
+			return importAttachmentLink(set.addAttachment(attachmentDir(attachmentSetting, p), sanitizeImportName(name), data[hash]))
+		}
+		conv := &htmlConverter{
+			media: func(n *html.Node) string {
+				if _, ok := resources[htmlAttr(n, "hash")]; !ok {
+					return ""
+				}
+				return attach(htmlAttr(n, "hash"))
+			},
+			link: func(href, text string) string {
+				if !strings.HasPrefix(href, "evernote:") {
+					return ""
+				}
+				// Links to notes carry an ID that isn't in the export; match the title
+				title := strings.TrimSpace(text)
+				target, ok := byTitle[strings.ToLower(title)]
+				if !ok {
+					target = sanitizeImportName(title)
+				}
+				l := wikilink{Target: target, Alias: title, HasAlias: pathBase(target) != title}
+				return l.String()
+			},
+		}
+
+		body := ""
+		if root, err := html.Parse(strings.NewReader(enmlSelfClosingRegex.ReplaceAllString(note.Content, "<$1$2></$1>"))); err == nil {
+			if enNote := findHTMLElement(root, "en-note"); enNote != nil {
+				root = enNote
+			}
+			body = conv.convert(root)
+		}
+		// Attachments not placed in the content go at the end
+		var extra []string
+		for _, hash := range order {
+			if !used[hash] {
+				extra = append(extra, attach(hash))
+			}
+		}
+		if len(extra) > 0 {
+			if body != "" {
+				body += "\n"
+			}
+			body += strings.Join(extra, "\n") + "\n"
+		}
+
+		fm := &frontmatter{}
+		var tags []string
+		for _, tag := range note.Tags {
+			if tag = strings.ReplaceAll(strings.TrimSpace(tag), " ", "-"); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) > 0 {
+			fm.SetList("tags", tags)
+		}
+		created, hasCreated := parseEnexTime(note.Created)
+		updated, hasUpdated := parseEnexTime(note.Updated)
+		if hasCreated {
+			fm.SetScalar("created", importTime(created))
+		}
+		if hasUpdated {
+			fm.SetScalar("updated", importTime(updated))
+		}
+		if note.Attributes.Author != "" {
+			fm.SetScalar("author", note.Attributes.Author)
+		}
+		if note.Attributes.SourceURL != "" {
+			fm.SetScalar("source", note.Attributes.SourceURL)
+		}
+		if len(fm.Fields) > 0 {
+			body = fm.Block() + body
+		}
+		set.notes[p+".md"] = body
+		switch {
+		case hasUpdated:
+			set.mtimes[p+".md"] = updated
+		case hasCreated:
+			set.mtimes[p+".md"] = created
+		}
+	}
+}
+
+// parseEnexTime parses an ENEX timestamp.
+func parseEnexTime(s string) (time.Time, bool) {
+	t, err := time.Parse(enexTimeLayout, strings.TrimSpace(s))
+	return t, err == nil
+}
+
+// importTime formats a date for frontmatter the way Obsidian writes date
+// and time properties, in local time.
+func importTime(t time.Time) string {
+	return t.Local().Format("2006-01-02T15:04:05")
+}
+
+// enexExtension returns a file extension for a MIME type.
+func enexExtension(mimeType string) string {
+	switch mimeType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "application/pdf":
+		return ".pdf"
+	case "audio/mpeg":
+		return ".mp3"
+	}
+	if exts, _ := mime.ExtensionsByType(mimeType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+// importAttachmentLink links an imported attachment, embedding the ones
+// Obsidian can display.
+func importAttachmentLink(p string) string {
+	ext := strings.ToLower(path.Ext(p))
+	kind := assetExtensions[ext]
+	l := wikilink{Target: p, Embed: kind == "image" || kind == "media" || ext == ".pdf"}
+	return l.String()
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var importHTMLOptions importOptions
+
+var importHTMLCmd = &cobra.Command{
+	Use:   "html <file.html|folder>...",
+	Short: "Import notes exported as HTML (Apple Notes, Bear)",
+	Long: `Imports notes exported as HTML files, one note per file, such as Apple
+Notes and Bear exports. A folder imports every .html file in it, keeping
+its subfolders.
+
+  - The note is named after the page <title>, else its first heading, else
+    the file name; a first heading repeating the title is dropped
+  - Formatting, lists, checklists, tables and code blocks are converted
+  - Images, relative to the file or inline data: URIs, are copied into the
+    vault's attachment folder and embedded
+  - Links between the exported files become wikilinks
+  - created/modified <meta> dates and keywords go into frontmatter; the
+    modification time is kept; Bear's #tags stay in the text
+
+Examples:
+  obsidian-cli import html ~/Bear-Export --vault ~/notes --dry-run
+  obsidian-cli import html ~/AppleNotes --vault ~/notes --into "Apple Notes"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runImportHTML,
+}
+
+func init() {
+	importCmd.AddCommand(importHTMLCmd)
+	addImportFlags(importHTMLCmd, &importHTMLOptions, "Notes")
+}
+
+// htmlNote is an HTML file to import.
+type htmlNote struct {
+	file    string // Path on disk
+	rel     string // Slash-separated path in the export
+	root    *html.Node
+	title   string
+	path    string // Import path
+	modTime time.Time
+}
+
+// Layouts tried for <meta> dates
+var htmlMetaTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+func runImportHTML(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	settings, err := loadObsidianAppSettings(absPath)
+	if err != nil {
+		return err
+	}
+
+	var notes []*htmlNote
+	for _, arg := range args {
+		files, err := collectImportFiles(arg, ".html", ".htm")
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			rel := filepath.Base(file)
+			if file != arg {
+				rel = mustRelPath(arg, file)
+			}
+			note, err := readHTMLNote(file, filepath.ToSlash(rel))
+			if err != nil {
+				return err
+			}
+			notes = append(notes, note)
+		}
+	}
+	if len(notes) == 0 {
+		return fmt.Errorf("no .html files found")
+	}
+
+	set := newImportSet()
+	set.absPath, set.folder = absPath, importFolder(&importHTMLOptions)
+	if importHTMLOptions.Format == "text" {
+		printScanHeader(fmt.Sprintf("Importing %d HTML notes", len(notes)))
+	}
+	convertHTMLNotes(set, notes, settings.AttachmentFolderPath)
+	return runImport(cmd, absPath, "HTML", set, &importHTMLOptions)
+}
+
+// readHTMLNote parses an HTML file and finds its title.
+func readHTMLNote(file, rel string) (*htmlNote, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	root, err := html.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	note := &htmlNote{file: file, rel: rel, root: root, modTime: info.ModTime()}
+	if title := findHTMLElement(root, "title"); title != nil {
+		note.title = strings.TrimSpace(htmlText(title))
+	}
+	if note.title == "" {
+		if h1 := findHTMLElement(root, "h1"); h1 != nil {
+			note.title = strings.Join(strings.Fields(htmlText(h1)), " ")
+		}
+	}
+	if note.title == "" {
+		note.title = strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+	}
+	return note, nil
+}
+
+// convertHTMLNotes adds HTML notes to an import set, keeping the folders
+// of the export.
+func convertHTMLNotes(set *importSet, notes []*htmlNote, attachmentSetting string) {
+	byFile := make(map[string]string) // Export path -> import path
+	for _, note := range notes {
+		dir := path.Dir(note.rel)
+		name := sanitizeImportName(note.title)
+		if dir != "." {
+			name = sanitizeImportPath(dir) + "/" + name
+		}
+		note.path = set.reserve(name, true)
+		byFile[note.rel] = note.path
+	}
+
+	for _, n := range notes {
+		conv := &htmlConverter{
+			media: func(img *html.Node) string {
+				src, alt := htmlAttr(img, "src"), htmlAttr(img, "alt")
+				data, name := readHTMLImage(n, src)
+				if data == nil {
+					return fmt.Sprintf("![%s](%s)", alt, src)
+				}
+				return importAttachmentLink(set.addAttachment(attachmentDir(attachmentSetting, n.path), sanitizeImportName(name), data))
+			},
+			link: func(href, text string) string {
+				u, err := url.Parse(href)
+				if err != nil || u.Scheme != "" || u.Path == "" {
+					return ""
+				}
+				target, ok := byFile[path.Clean(path.Join(path.Dir(n.rel), u.Path))]
+				if !ok {
+					return ""
+				}
+				l := wikilink{Target: target, Alias: strings.TrimSpace(text)}
+				l.HasAlias = l.Alias != pathBase(target)
+				return l.String()
+			},
+		}
+
+		body := findHTMLElement(n.root, "body")
+		if body == nil {
+			body = n.root
+		}
+		// The inline title shows the name; drop a heading repeating it
+		for ch := body.FirstChild; ch != nil; ch = ch.NextSibling {
+			if ch.Type == html.TextNode && strings.TrimSpace(ch.Data) == "" {
+				continue
+			}
+			if ch.DataAtom == atom.H1 && strings.Join(strings.Fields(htmlText(ch)), " ") == n.title {
+				body.RemoveChild(ch)
+			}
+			break
+		}
+		content := conv.convert(body)
+
+		var tags []string
+		var created, updated string
+		modified := n.modTime
+		for _, meta := range findHTMLElements(n.root, "meta") {
+			name, value := strings.ToLower(htmlAttr(meta, "name")), strings.TrimSpace(htmlAttr(meta, "content"))
+			switch name {
+			case "keywords", "tags":
+				for _, tag := range strings.Split(value, ",") {
+					if tag = strings.ReplaceAll(strings.TrimPrefix(strings.TrimSpace(tag), "#"), " ", "-"); tag != "" {
+						tags = append(tags, tag)
+					}
+				}
+			case "created", "dcterms.created", "modified", "last-modified", "dcterms.modified":
+				for _, layout := range htmlMetaTimeLayouts {
+					if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+						if strings.Contains(name, "created") {
+							created = importTime(t)
+						} else {
+							updated, modified = importTime(t), t
+						}
+						break
+					}
+				}
+			}
+		}
+		fm := &frontmatter{}
+		if len(tags) > 0 {
+			fm.SetList("tags", tags)
+		}
+		if created != "" {
+			fm.SetScalar("created", created)
+		}
+		if updated != "" {
+			fm.SetScalar("updated", updated)
+		}
+		if len(fm.Fields) > 0 {
+			content = fm.Block() + content
+		}
+		set.notes[n.path+".md"] = content
+		set.mtimes[n.path+".md"] = modified
+	}
+}
+
+// readHTMLImage reads the image at src, relative to the note's file or a
+// data: URI. Returns nil for remote or missing images.
+func readHTMLImage(note *htmlNote, src string) (data []byte, name string) {
+	if rest, ok := strings.CutPrefix(src, "data:"); ok {
+		meta, encoded, ok := strings.Cut(rest, ",")
+		if !ok || !strings.HasSuffix(meta, ";base64") {
+			return nil, ""
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, ""
+		}
+		return data, note.title + enexExtension(strings.TrimSuffix(meta, ";base64"))
+	}
+	u, err := url.Parse(src)
+	if err != nil || u.Scheme != "" || u.Path == "" {
+		return nil, ""
+	}
+	data, err = os.ReadFile(filepath.Join(filepath.Dir(note.file), filepath.FromSlash(u.Path)))
+	if err != nil {
+		return nil, ""
+	}
+	return data, path.Base(u.Path)
+}
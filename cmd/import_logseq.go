@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var importLogseqOptions importOptions
+
+var importLogseqCmd = &cobra.Command{
+	Use:   "logseq <graph folder>",
+	Short: "Import a Logseq graph folder",
+	Long: `Imports the pages, journals and assets of a Logseq graph folder.
+
+  - Page properties (key:: value) become frontmatter; tags and alias
+    become lists, alias is renamed aliases
+  - Namespaced pages (a___b.md or a%2Fb.md) go into folders, and
+    title:: properties name the note
+  - Journals (2026_10_18.md) become journals/2026-10-18.md, and links to
+    them ([[Oct 18th, 2026]]) are rewritten
+  - Block references ((uuid)) become links to the block, [[page#^uuid]],
+    using the block's id:: property; {{embed}} becomes an embed
+  - TODO/DOING/NOW/LATER and DONE markers become tasks
+  - Assets are copied to assets/ and embedded
+
+Examples:
+  obsidian-cli import logseq ~/logseq-graph --vault ~/notes --dry-run
+  obsidian-cli import logseq ~/logseq-graph --vault ~/notes --into Logseq`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportLogseq,
+}
+
+func init() {
+	importCmd.AddCommand(importLogseqCmd)
+	addImportFlags(importLogseqCmd, &importLogseqOptions, "Logseq")
+}
+
+var (
+	// Matches a property line: "key:: value", optionally indented
+	logseqPropertyRegex = regexp.MustCompile(`^(\s*)([\w-]+):: ?(.*)$`)
+	// Matches a bullet line and its task marker, if any
+	logseqBulletRegex = regexp.MustCompile(`^(\s*)- (?:(TODO|DOING|NOW|LATER|DONE|CANCELED|CANCELLED) )?`)
+	// Matches a journal file name: 2026_10_18
+	logseqJournalRegex = regexp.MustCompile(`^(\d{4})_(\d{2})_(\d{2})$`)
+)
+
+// logseqPage is a page or journal file of a Logseq graph.
+type logseqPage struct {
+	file    string // Path in the graph
+	title   string
+	path    string // Import path
+	props   [][2]string
+	body    string
+	journal bool
+}
+
+func runImportLogseq(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	files := make(map[string][]byte)
+	for _, dir := range []string{"pages", "journals", "assets"} {
+		root := filepath.Join(args[0], dir)
+		err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && p == root {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+				return nil
+			}
+			data, err := os.ReadFile(p)
+			if err == nil {
+				files[filepath.ToSlash(mustRelPath(args[0], p))] = data
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no pages, journals or assets folder found in %s", args[0])
+	}
+	if importLogseqOptions.Format == "text" {
+		printScanHeader("Importing " + filepath.Base(args[0]))
+	}
+	return runImport(cmd, absPath, "Logseq", convertLogseqGraph(files), &importLogseqOptions)
+}
+
+// splitLogseqProperties splits the page properties at the top of a file
+// from the rest. Properties may be written as the first bullet.
+func splitLogseqProperties(content string) (props [][2]string, body string) {
+	lines := strings.Split(content, "\n")
+	bulleted := strings.HasPrefix(lines[0], "- ")
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		if i == 0 {
+			line = strings.TrimPrefix(line, "- ")
+		}
+		// Further properties of a first bullet are indented under it
+		m := logseqPropertyRegex.FindStringSubmatch(line)
+		if m == nil || (i > 0 && (m[1] != "") != bulleted) {
+			break
+		}
+		props = append(props, [2]string{strings.ToLower(m[2]), strings.TrimSpace(m[3])})
+	}
+	if len(props) == 0 {
+		return nil, content
+	}
+	return props, strings.TrimLeft(strings.Join(lines[i:], "\n"), "\r\n")
+}
+
+// logseqList splits a property value into items: "a, [[b c]], #d".
+func logseqList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimPrefix(strings.TrimSpace(item), "#")
+		item = strings.TrimSuffix(strings.TrimPrefix(item, "[["), "]]")
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// logseqPageName returns the page name of a file: namespaces are encoded
+// as "___" or "%2F".
+func logseqPageName(file string) string {
+	name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+	name = strings.ReplaceAll(name, "___", "/")
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return name
+}
+
+// convertLogseqGraph converts the files of a Logseq graph.
+func convertLogseqGraph(files map[string][]byte) *importSet {
+	set := newImportSet()
+	oc := &outlinerConverter{pages: make(map[string]string), blocks: make(map[string]string)}
+
+	var pages []*logseqPage
+	assets := make(map[string]string) // Graph path -> import path
+	for _, file := range sortedKeys(files) {
+		if strings.HasPrefix(file, "assets/") {
+			assets[file] = set.reserve("assets/"+sanitizeImportName(path.Base(file)), false)
+			set.files[assets[file]] = files[file]
+			continue
+		}
+		if !strings.EqualFold(path.Ext(file), ".md") {
+			continue
+		}
+		page := &logseqPage{file: file, title: logseqPageName(file)}
+		page.props, page.body = splitLogseqProperties(string(files[file]))
+		for _, prop := range page.props {
+			if prop[0] == "title" && prop[1] != "" {
+				page.title = prop[1]
+			}
+		}
+		name := sanitizeImportPath(page.title)
+		if m := logseqJournalRegex.FindStringSubmatch(page.title); m != nil && strings.HasPrefix(file, "journals/") {
+			page.journal = true
+			name = "journals/" + m[1] + "-" + m[2] + "-" + m[3]
+		}
+		page.path = set.reserve(name, true)
+		oc.pages[strings.ToLower(page.title)] = page.path
+		for _, prop := range page.props {
+			if prop[0] == "alias" {
+				for _, alias := range logseqList(prop[1]) {
+					if _, taken := oc.pages[strings.ToLower(alias)]; !taken {
+						oc.pages[strings.ToLower(alias)] = page.path
+					}
+				}
+			}
+		}
+		for _, line := range strings.Split(page.body, "\n") {
+			if m := logseqPropertyRegex.FindStringSubmatch(line); m != nil && m[2] == "id" {
+				oc.blocks[strings.TrimSpace(m[3])] = page.path
+			}
+		}
+		pages = append(pages, page)
+	}
+
+	// Links to journals use their date: [[Oct 18th, 2026]]
+	for _, page := range pages {
+		if page.journal {
+			if date := pathBase(page.path); oc.pages[strings.ToLower(date)] == "" {
+				oc.pages[strings.ToLower(date)] = page.path
+			}
+		}
+	}
+
+	for _, page := range pages {
+		set.notes[page.path+".md"] = convertLogseqPage(page, oc, assets)
+	}
+	return set
+}
+
+// convertLogseqPage renders a page with its properties as frontmatter.
+func convertLogseqPage(page *logseqPage, oc *outlinerConverter, assets map[string]string) string {
+	fm := &frontmatter{}
+	for _, prop := range page.props {
+		switch prop[0] {
+		case "title":
+			// The note is named after it
+		case "tags":
+			tags := logseqList(prop[1])
+			for i, tag := range tags {
+				tags[i] = strings.ReplaceAll(tag, " ", "-")
+			}
+			fm.SetList("tags", tags)
+		case "alias":
+			fm.SetList("aliases", logseqList(prop[1]))
+		default:
+			fm.SetScalar(prop[0], prop[1])
+		}
+	}
+
+	lines := strings.Split(page.body, "\n")
+	var out []string
+	lastBullet := -1
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if m := logseqPropertyRegex.FindStringSubmatch(line); m != nil && lastBullet != -1 {
+			switch m[2] {
+			case "id":
+				// Obsidian block IDs go at the end of the block
+				out[lastBullet] += " ^" + blockID(strings.TrimSpace(m[3]))
+				continue
+			case "collapsed":
+				continue
+			}
+		}
+		if m := logseqBulletRegex.FindStringSubmatch(line); m != nil {
+			switch m[2] {
+			case "":
+			case "DONE":
+				line = m[1] + "- [x] " + line[len(m[0]):]
+			case "CANCELED", "CANCELLED":
+				line = m[1] + "- [-] " + line[len(m[0]):]
+			default:
+				line = m[1] + "- [ ] " + line[len(m[0]):]
+			}
+			lastBullet = len(out)
+		}
+		out = append(out, line)
+	}
+
+	body := mapOutsideFences(strings.Join(out, "\n"), 1, func(chunk string, _ int) string {
+		return mapOutsideInlineCode(chunk, 1, func(piece string, _ int) string {
+			piece = oc.convertRefs(piece)
+			// Assets are linked relative to the page: ../assets/image.png
+			return markdownLinkRegex.ReplaceAllStringFunc(piece, func(match string) string {
+				ml := parseMarkdownLink(match, markdownLinkRegex.FindStringSubmatchIndex(match))
+				asset, ok := assets[path.Clean(path.Join(path.Dir(page.file), ml.Target))]
+				if !ok {
+					return match
+				}
+				l := wikilink{Embed: ml.Embed, Target: asset, Alias: ml.Label, HasAlias: !ml.Embed && ml.Label != ""}
+				return l.String()
+			})
+		})
+	})
+
+	if len(fm.Fields) == 0 {
+		return body
+	}
+	return fm.Block() + body
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var importNotionOptions importOptions
+
+var importNotionCmd = &cobra.Command{
+	Use:   "notion <export.zip|folder>",
+	Short: "Import a Notion markdown & CSV export",
+	Long: `Imports a Notion workspace or page exported as "Markdown & CSV", either
+the zip file (including multi-part exports) or the unzipped folder.
+
+  - The 32-character IDs Notion appends to file and folder names are removed
+  - Links between pages, including notion.so URLs to exported pages,
+    become wikilinks
+  - Images and attachments are copied next to their page and embedded
+  - Databases (CSV) become a note with a table, linking each row to its page
+
+Examples:
+  obsidian-cli import notion Export-1234.zip --vault ~/notes --dry-run
+  obsidian-cli import notion Export-1234.zip --vault ~/notes --into Notion`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportNotion,
+}
+
+func init() {
+	importCmd.AddCommand(importNotionCmd)
+	addImportFlags(importNotionCmd, &importNotionOptions, "Notion")
+}
+
+var (
+	// Matches the ID Notion appends to names: "Page 0123456789abcdef0123456789abcdef"
+	notionIDRegex = regexp.MustCompile(`^(.*?)\s*([0-9a-f]{32})$`)
+	// Matches a page ID at the end of a notion.so URL path
+	notionURLRegex = regexp.MustCompile(`^https?://(?:www\.)?notion\.(?:so|site)/.*?([0-9a-f]{32})(?:[?#].*)?$`)
+)
+
+func runImportNotion(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	files, err := readNotionExport(args[0])
+	if err != nil {
+		return err
+	}
+	if importNotionOptions.Format == "text" {
+		printScanHeader("Importing " + filepath.Base(args[0]))
+	}
+	return runImport(cmd, absPath, "Notion", convertNotionExport(files), &importNotionOptions)
+}
+
+// readNotionExport reads the files of a Notion export zip or folder.
+// Zips inside a zip (multi-part exports) are read too.
+func readNotionExport(src string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		err := filepath.WalkDir(src, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+				return nil
+			}
+			data, err := os.ReadFile(p)
+			if err == nil {
+				files[filepath.ToSlash(mustRelPath(src, p))] = data
+			}
+			return err
+		})
+		return files, err
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	return files, readNotionZip(data, files, 0)
+}
+
+func readNotionZip(data []byte, files map[string][]byte, depth int) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("not a zip file or folder: %w", err)
+	}
+	for _, f := range zr.File {
+		name := path.Clean(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		if strings.EqualFold(path.Ext(name), ".zip") && depth == 0 {
+			if err := readNotionZip(content, files, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		files[name] = content
+	}
+	return nil
+}
+
+// stripNotionID removes the page ID from a file or folder name, returning
+// the ID too.
+func stripNotionID(name string) (string, string) {
+	if m := notionIDRegex.FindStringSubmatch(name); m != nil && m[1] != "" {
+		return m[1], m[2]
+	}
+	return name, ""
+}
+
+// notionPath returns the path of an export file with IDs removed from
+// every segment. Notes lose their extension; databases are notes too.
+func notionPath(p string) (newPath, id string, isNote bool) {
+	parts := strings.Split(p, "/")
+	for i, part := range parts[:len(parts)-1] {
+		parts[i], _ = stripNotionID(part)
+	}
+	file := parts[len(parts)-1]
+	ext := path.Ext(file)
+	isNote = strings.EqualFold(ext, ".md") || strings.EqualFold(ext, ".csv")
+	if !isNote {
+		parts[len(parts)-1] = sanitizeImportName(file)
+		return sanitizeImportPath(strings.Join(parts, "/")), "", false
+	}
+	stem := strings.TrimSuffix(file, ext)
+	if strings.EqualFold(ext, ".csv") {
+		stem = strings.TrimSuffix(stem, "_all")
+	}
+	parts[len(parts)-1], id = stripNotionID(stem)
+	return sanitizeImportPath(strings.Join(parts, "/")), id, true
+}
+
+// convertNotionExport converts the files of a Notion export.
+func convertNotionExport(files map[string][]byte) *importSet {
+	set := newImportSet()
+	paths := make(map[string]string) // Export path -> import path
+	byID := make(map[string]string)  // Page ID -> import path
+	superseded := make(map[string]string)
+	for _, p := range sortedKeys(files) {
+		ext := strings.ToLower(path.Ext(p))
+		// Newer exports have both "DB.csv" and "DB_all.csv"; the latter has
+		// every row. Pages may link to either.
+		if all := strings.TrimSuffix(p, ".csv") + "_all.csv"; ext == ".csv" && !strings.HasSuffix(p, "_all.csv") && files[all] != nil {
+			superseded[p] = all
+			continue
+		}
+		newPath, id, isNote := notionPath(p)
+		newPath = set.reserve(newPath, isNote)
+		paths[p] = newPath
+		if id != "" {
+			byID[id] = newPath
+		}
+	}
+	for p, all := range superseded {
+		paths[p] = paths[all]
+	}
+
+	for _, p := range sortedKeys(paths) {
+		if _, ok := superseded[p]; ok {
+			continue
+		}
+		newPath := paths[p]
+		switch strings.ToLower(path.Ext(p)) {
+		case ".md":
+			set.notes[newPath+".md"] = convertNotionPage(p, string(files[p]), paths, byID)
+		case ".csv":
+			set.notes[newPath+".md"] = convertNotionDatabase(p, files[p], paths)
+		default:
+			set.files[newPath] = files[p]
+		}
+	}
+	return set
+}
+
+// convertNotionPage rewrites the links of a page exported at p.
+func convertNotionPage(p, content string, paths, byID map[string]string) string {
+	return mapOutsideFences(content, 1, func(chunk string, _ int) string {
+		return mapOutsideInlineCode(chunk, 1, func(piece string, _ int) string {
+			return markdownLinkRegex.ReplaceAllStringFunc(piece, func(match string) string {
+				ml := parseMarkdownLink(match, markdownLinkRegex.FindStringSubmatchIndex(match))
+				target, isNote, ok := "", false, false
+				if m := notionURLRegex.FindStringSubmatch(ml.Dest); m != nil {
+					target, ok = byID[m[1]]
+					isNote = true
+				} else if !strings.Contains(ml.Dest, "://") && !strings.HasPrefix(ml.Dest, "mailto:") {
+					exported := path.Clean(path.Join(path.Dir(p), ml.Target))
+					target, ok = paths[exported]
+					ext := strings.ToLower(path.Ext(exported))
+					isNote = ext == ".md" || ext == ".csv"
+					if !ok && ext == ".md" {
+						// A page that wasn't exported: keep a wikilink to its name
+						target, _, _ = notionPath(path.Base(exported))
+						ok = true
+					}
+				}
+				if !ok {
+					return match
+				}
+
+				l := wikilink{Embed: ml.Embed && !isNote, Target: target, Alias: ml.Label}
+				l.HasAlias = l.Alias != "" && l.Alias != pathBase(target) && !l.Embed
+				return l.String()
+			})
+		})
+	})
+}
+
+// convertNotionDatabase converts a database exported as CSV at p into a
+// table. Row titles link to the row pages in the database's folder.
+func convertNotionDatabase(p string, data []byte, paths map[string]string) string {
+	name, _, _ := notionPath(p)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", pathBase(name))
+
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil || len(rows) == 0 {
+		b.WriteString("```csv\n" + string(data) + "\n```\n")
+		return b.String()
+	}
+
+	// Row pages live in a folder named like the database
+	rowPages := make(map[string]string)
+	folder := strings.TrimSuffix(strings.TrimSuffix(p, path.Ext(p)), "_all")
+	for exported, newPath := range paths {
+		if path.Dir(exported) == folder && strings.EqualFold(path.Ext(exported), ".md") {
+			title, _ := stripNotionID(strings.TrimSuffix(path.Base(exported), path.Ext(exported)))
+			rowPages[strings.ToLower(title)] = newPath
+		}
+	}
+
+	cell := func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(strings.TrimSpace(s))
+	}
+	width := len(rows[0])
+	for i, row := range rows {
+		cells := make([]string, width)
+		for j := range cells {
+			if j < len(row) {
+				cells[j] = cell(row[j])
+			}
+		}
+		if i > 0 && width > 0 {
+			if page, ok := rowPages[strings.ToLower(strings.TrimSpace(row[0]))]; ok {
+				cells[0] = "[[" + page + "]]"
+			}
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var importRoamOptions importOptions
+
+var importRoamCmd = &cobra.Command{
+	Use:   "roam <export.json>",
+	Short: "Import a Roam Research JSON export",
+	Long: `Imports a Roam Research graph exported as JSON.
+
+  - Each page becomes a note; namespaced pages (a/b) go into folders and
+    daily pages ("October 18th, 2026") are named 2026-10-18
+  - Blocks become nested list items, TODO/DONE become tasks
+  - Block references ((uid)) become links to the block, [[page#^uid]],
+    and referenced blocks get a ^uid block ID; {{embed}} becomes an embed
+  - #[[tags]] become links, __italic__ and ^^highlights^^ are converted
+
+Examples:
+  obsidian-cli import roam graph.json --vault ~/notes --dry-run
+  obsidian-cli import roam graph.json --vault ~/notes --into Roam`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportRoam,
+}
+
+func init() {
+	importCmd.AddCommand(importRoamCmd)
+	addImportFlags(importRoamCmd, &importRoamOptions, "Roam")
+}
+
+// roamPage is a page in a Roam JSON export.
+type roamPage struct {
+	Title    string      `json:"title"`
+	Children []roamBlock `json:"children"`
+}
+
+// roamBlock is a block in a Roam JSON export.
+type roamBlock struct {
+	String   string      `json:"string"`
+	UID      string      `json:"uid"`
+	Heading  int         `json:"heading"`
+	Children []roamBlock `json:"children"`
+}
+
+var (
+	// Matches a daily note title: "October 18th, 2026" or "Oct 18th, 2026"
+	ordinalDateRegex = regexp.MustCompile(`^([A-Z][a-z]{2,8}) (\d{1,2})(?:st|nd|rd|th), (\d{4})$`)
+	// Matches a block reference: ((uid))
+	blockRefRegex = regexp.MustCompile(`\(\(([\w-]+)\)\)`)
+	// Matches a block or page embed: {{embed: ((uid))}}, {{[[embed]]: [[page]]}}, {{embed ((uid))}}
+	outlinerEmbedRegex = regexp.MustCompile(`\{\{(?:\[\[)?embed(?:\]\])?:?\s*(\(\([\w-]+\)\)|\[\[[^\]]+\]\])\s*\}\}`)
+	// Matches an aliased page or block link: [text]([[page]]) or [text](((uid)))
+	outlinerAliasRegex = regexp.MustCompile(`\[([^\[\]]*)\]\((\[\[[^\]]+\]\]|\(\([\w-]+\)\))\)`)
+	// Matches a multi-word tag: #[[tag]]
+	bracketTagRegex = regexp.MustCompile(`#\[\[([^\]]+)\]\]`)
+	// Matches a task marker at the start of a Roam block
+	roamTaskRegex      = regexp.MustCompile(`^\{\{(?:\[\[)?(TODO|DONE)(?:\]\])?\}\}\s*`)
+	roamItalicRegex    = regexp.MustCompile(`__([^_\n]+)__`)
+	roamHighlightRegex = regexp.MustCompile(`\^\^([^^\n]+)\^\^`)
+)
+
+// parseOrdinalDate parses a Roam or Logseq daily note title into
+// YYYY-MM-DD.
+func parseOrdinalDate(title string) (string, bool) {
+	m := ordinalDateRegex.FindStringSubmatch(title)
+	if m == nil {
+		return "", false
+	}
+	for _, layout := range []string{"January 2 2006", "Jan 2 2006"} {
+		if t, err := time.Parse(layout, m[1]+" "+m[2]+" "+m[3]); err == nil {
+			return t.Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
+// blockID turns a Roam or Logseq block UID into an Obsidian block ID,
+// which only allows letters, digits and dashes.
+func blockID(uid string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '-'
+	}, uid)
+}
+
+// outlinerConverter converts Roam and Logseq markup: page links are
+// pointed at imported notes, and block references at block IDs.
+type outlinerConverter struct {
+	pages  map[string]string // Lowercase page title or alias -> import path
+	blocks map[string]string // Block UID -> import path of its page
+}
+
+// convertRefs turns multi-word tags into links, points page links at
+// imported notes, then converts embeds, aliased links and block references.
+func (oc *outlinerConverter) convertRefs(text string) string {
+	blockLink := func(ref string, embed bool, alias string) string {
+		if uid, ok := strings.CutPrefix(ref, "(("); ok {
+			uid = strings.TrimSuffix(uid, "))")
+			page, ok := oc.blocks[uid]
+			if !ok {
+				return ""
+			}
+			ref = "[[" + page + "#^" + blockID(uid) + "]]"
+		}
+		l := parseWikilinks(ref)[0]
+		l.Embed = embed
+		l.Alias, l.HasAlias = alias, alias != ""
+		return l.String()
+	}
+
+	text = bracketTagRegex.ReplaceAllString(text, "[[$1]]")
+
+	text, _ = rewriteWikilinks(text, func(l wikilink) (string, bool) {
+		p, ok := oc.pages[strings.ToLower(l.Target)]
+		if !ok {
+			date, isDate := parseOrdinalDate(l.Target)
+			if !isDate {
+				return "", false
+			}
+			if p, ok = oc.pages[date]; !ok {
+				p = date
+			}
+		}
+		if !l.HasAlias && !l.Embed && pathBase(p) != l.Target {
+			l.Alias, l.HasAlias = l.Target, true
+		}
+		l.Target = p
+		return l.String(), true
+	})
+	text = outlinerEmbedRegex.ReplaceAllStringFunc(text, func(m string) string {
+		if link := blockLink(outlinerEmbedRegex.FindStringSubmatch(m)[1], true, ""); link != "" {
+			return link
+		}
+		return m
+	})
+	text = outlinerAliasRegex.ReplaceAllStringFunc(text, func(m string) string {
+		sm := outlinerAliasRegex.FindStringSubmatch(m)
+		if link := blockLink(sm[2], false, sm[1]); link != "" {
+			return link
+		}
+		return m
+	})
+	text = blockRefRegex.ReplaceAllStringFunc(text, func(m string) string {
+		if link := blockLink(m, false, ""); link != "" {
+			return link
+		}
+		return m
+	})
+	return text
+}
+
+func runImportRoam(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	var pages []roamPage
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return fmt.Errorf("invalid Roam export: %w", err)
+	}
+	if importRoamOptions.Format == "text" {
+		printScanHeader("Importing " + filepath.Base(args[0]))
+	}
+	return runImport(cmd, absPath, "Roam", convertRoamExport(pages), &importRoamOptions)
+}
+
+// convertRoamExport converts the pages of a Roam export.
+func convertRoamExport(pages []roamPage) *importSet {
+	set := newImportSet()
+	oc := &outlinerConverter{pages: make(map[string]string), blocks: make(map[string]string)}
+
+	byTitle := make(map[string]roamPage, len(pages))
+	for _, page := range pages {
+		byTitle[page.Title] = page
+	}
+	titles := sortedKeys(byTitle)
+	for _, title := range titles {
+		name := title
+		if date, ok := parseOrdinalDate(title); ok {
+			name = date
+		}
+		oc.pages[strings.ToLower(title)] = set.reserve(sanitizeImportPath(name), true)
+	}
+
+	// Referenced blocks get a block ID
+	referenced := make(map[string]bool)
+	var index func(p string, blocks []roamBlock)
+	index = func(p string, blocks []roamBlock) {
+		for _, b := range blocks {
+			oc.blocks[b.UID] = p
+			for _, m := range blockRefRegex.FindAllStringSubmatch(b.String, -1) {
+				referenced[m[1]] = true
+			}
+			index(p, b.Children)
+		}
+	}
+	for _, title := range titles {
+		index(oc.pages[strings.ToLower(title)], byTitle[title].Children)
+	}
+
+	for _, title := range titles {
+		var b strings.Builder
+		writeRoamBlocks(&b, oc, byTitle[title].Children, 0, referenced)
+		set.notes[oc.pages[strings.ToLower(title)]+".md"] = b.String()
+	}
+	return set
+}
+
+// writeRoamBlocks writes blocks as list items indented by depth tabs.
+func writeRoamBlocks(b *strings.Builder, oc *outlinerConverter, blocks []roamBlock, depth int, referenced map[string]bool) {
+	indent := strings.Repeat("\t", depth)
+	for _, block := range blocks {
+		text := block.String
+		marker := "- "
+		if m := roamTaskRegex.FindStringSubmatch(text); m != nil {
+			marker = "- [ ] "
+			if m[1] == "DONE" {
+				marker = "- [x] "
+			}
+			text = text[len(m[0]):]
+		}
+		text = oc.convertRefs(text)
+		text = roamItalicRegex.ReplaceAllString(text, "*$1*")
+		text = roamHighlightRegex.ReplaceAllString(text, "==$1==")
+		if block.Heading > 0 {
+			text = strings.Repeat("#", block.Heading) + " " + text
+		}
+		if referenced[block.UID] {
+			text += " ^" + blockID(block.UID)
+		}
+
+		lines := strings.Split(text, "\n")
+		b.WriteString(indent + marker + lines[0] + "\n")
+		for _, line := range lines[1:] {
+			b.WriteString(indent + "  " + line + "\n")
+		}
+		writeRoamBlocks(b, oc, block.Children, depth+1, referenced)
+	}
+}
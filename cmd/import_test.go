@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// TestNotionPath tests ID stripping from export paths
+func TestNotionPath(t *testing.T) {
+	for _, tc := range []struct {
+		in, want, id string
+		isNote       bool
+	}{
+		{"Home 0123456789abcdef0123456789abcdef.md", "Home", "0123456789abcdef0123456789abcdef", true},
+		{"Home 0123456789abcdef0123456789abcdef/Tasks aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa_all.csv", "Home/Tasks", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", true},
+		{"Home 0123456789abcdef0123456789abcdef/Untitled.png", "Home/Untitled.png", "", false},
+		{"Q: what?.md", "Q- what-", "", true},
+	} {
+		got, id, isNote := notionPath(tc.in)
+		if got != tc.want || id != tc.id || isNote != tc.isNote {
+			t.Errorf("notionPath(%q) = %q, %q, %v; want %q, %q, %v", tc.in, got, id, isNote, tc.want, tc.id, tc.isNote)
+		}
+	}
+}
+
+// TestConvertNotionExport tests that links to a database's "DB.csv" point
+// at the note converted from "DB_all.csv"
+func TestConvertNotionExport(t *testing.T) {
+	const home, db = "Home 0123456789abcdef0123456789abcdef", "Tasks aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	set := convertNotionExport(map[string][]byte{
+		home + ".md":                 []byte("[Tasks](Home%200123456789abcdef0123456789abcdef/Tasks%20aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa.csv)\n"),
+		home + "/" + db + ".csv":     []byte("Name\nOne\n"),
+		home + "/" + db + "_all.csv": []byte("Name\nOne\nTwo\n"),
+	})
+
+	if got := sortedKeys(set.notes); strings.Join(got, ",") != "Home.md,Home/Tasks.md" {
+		t.Fatalf("notes = %v, want Home.md and Home/Tasks.md", got)
+	}
+	if got, want := set.notes["Home.md"], "[[Home/Tasks]]\n"; got != want {
+		t.Errorf("Home.md = %q, want %q", got, want)
+	}
+	if !strings.Contains(set.notes["Home/Tasks.md"], "| Two |") {
+		t.Errorf("Home/Tasks.md = %q, want the rows of Tasks_all.csv", set.notes["Home/Tasks.md"])
+	}
+}
+
+// TestRunImportUnresolved tests that wikilinks and markdown links that
+// don't resolve after the import are reported
+func TestRunImportUnresolved(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "old.md"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	set := newImportSet()
+	set.notes["a.md"] = "[[b]] [[gone]] [b](b.md) [o](/old.md) [x](x.csv) [w](https://example.com) [h](#top)\n"
+	set.notes["b.md"] = "b\n"
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	if err := runImport(cmd, dir, "Test", set, &importOptions{Into: "In", DryRun: true, Format: "json"}); err != nil {
+		t.Fatal(err)
+	}
+	var result ImportResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, l := range result.Unresolved {
+		got = append(got, l.Target)
+	}
+	if strings.Join(got, ",") != "gone,x.csv" {
+		t.Errorf("unresolved = %v, want gone and x.csv", got)
+	}
+}
+
+// TestConvertRoamExport tests nesting, block references and daily pages
+func TestConvertRoamExport(t *testing.T) {
+	set := convertRoamExport([]roamPage{
+		{Title: "A", Children: []roamBlock{
+			{String: "parent", UID: "p_1", Children: []roamBlock{{String: "{{[[TODO]]}} child", UID: "c1"}}},
+		}},
+		{Title: "B", Children: []roamBlock{{String: "see ((p_1)) on [[October 18th, 2026]]", UID: "b1"}}},
+	})
+
+	if got, want := set.notes["A.md"], "- parent ^p-1\n\t- [ ] child\n"; got != want {
+		t.Errorf("A.md = %q, want %q", got, want)
+	}
+	if got, want := set.notes["B.md"], "- see [[A#^p-1]] on [[2026-10-18|October 18th, 2026]]\n"; got != want {
+		t.Errorf("B.md = %q, want %q", got, want)
+	}
+}
+
+// TestConvertLogseqGraph tests properties, block IDs and namespaces
+func TestConvertLogseqGraph(t *testing.T) {
+	set := convertLogseqGraph(map[string][]byte{
+		"pages/a___b.md": []byte("tags:: x, [[y z]]\nalias:: Bee\n\n- DONE task\n  id:: 1-2\n  collapsed:: true\n"),
+		"pages/c.md":     []byte("- [[Bee]] ((1-2))\n"),
+	})
+
+	want := "---\ntags:\n  - x\n  - y-z\naliases:\n  - Bee\n---\n- [x] task ^1-2\n"
+	if got := set.notes["a/b.md"]; got != want {
+		t.Errorf("a/b.md = %q, want %q", got, want)
+	}
+	if got := set.notes["c.md"]; !strings.Contains(got, "[[a/b|Bee]] [[a/b#^1-2]]") {
+		t.Errorf("c.md = %q", got)
+	}
+}
+
+// TestConvertEnexNotes tests ENML conversion, attachments and duplicate titles
+func TestConvertEnexNotes(t *testing.T) {
+	enml := `<en-note><div><en-todo checked="true"/>Done</div><div>a <b>b</b></div><div><br/></div>` +
+		`<ul><li>x<ul><li>y</li></ul></li></ul><en-media hash="39e6065acec4db6057df35e124a2b48b" type="image/png"/><div>end</div></en-note>`
+	set := newImportSet()
+	convertEnexNotes(set, []*enexNote{
+		{Title: "Same", Content: "<en-note>second</en-note>", Created: "20220101T000000Z", notebook: "Book"},
+		{Title: "Same", Content: enml, Created: "20210101T000000Z", Tags: []string{"a b"}, notebook: "Book",
+			Resources: []enexResource{{Data: "ZmFrZXBuZw==", Mime: "image/png"}}},
+	}, "/")
+
+	want := "---\ntags:\n  - a-b\ncreated: " + importTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)) + "\n---\n" +
+		"- [x] Done\na **b**\n\n- x\n  - y\n\n![[/Same.png]]\n\nend\n"
+	if got := set.notes["Book/Same.md"]; got != want {
+		t.Errorf("Book/Same.md = %q, want %q", got, want)
+	}
+	if !strings.Contains(set.notes["Book/Same 2.md"], "second") {
+		t.Errorf("Book/Same 2.md = %q", set.notes["Book/Same 2.md"])
+	}
+	if string(set.files["/Same.png"]) != "fakepng" {
+		t.Errorf("files = %v", sortedKeys(set.files))
+	}
+}
+
+// TestConvertHTMLNotes tests titles, links between files, images and
+// <meta> frontmatter of HTML exports
+func TestConvertHTMLNotes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Note.html": `<html><head><title>My_Note</title><meta name="keywords" content="#work, a b">` +
+			`<meta name="created" content="2021-01-01"></head><body><h1>My_Note</h1>` +
+			`<div>See <a href="sub/Other.html">the other</a> and <a href="https://example.com">site</a></div>` +
+			`<div><img src="img/pic.png" alt="pic"> <img src="data:image/png;base64,ZmFrZXBuZw=="> <img src="missing.png" alt="m"></div></body></html>`,
+		"sub/Other.html": `<body><h1>Other</h1><p>Back to <a href="../Note.html">My_Note</a></p></body>`,
+		"img/pic.png":    "pic",
+	}
+	var notes []*htmlNote
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(name, ".html") {
+			note, err := readHTMLNote(file, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			notes = append(notes, note)
+		}
+	}
+	set := newImportSet()
+	convertHTMLNotes(set, notes, "")
+
+	want := "---\ntags:\n  - work\n  - a-b\ncreated: " + importTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.Local)) + "\n---\n" +
+		"See [[sub/Other|the other]] and [site](https://example.com)\n![[/pic.png]] ![[/My_Note.png]] ![m](missing.png)\n"
+	if got := set.notes["My_Note.md"]; got != want {
+		t.Errorf("My_Note.md = %q, want %q", got, want)
+	}
+	if got, want := set.notes["sub/Other.md"], "Back to [[My_Note]]\n"; got != want {
+		t.Errorf("sub/Other.md = %q, want %q", got, want)
+	}
+	if string(set.files["/pic.png"]) != "pic" || string(set.files["/My_Note.png"]) != "fakepng" {
+		t.Errorf("files = %v", sortedKeys(set.files))
+	}
+}
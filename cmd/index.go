@@ -0,0 +1,637 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kofifort/obsidian-cli/internal/vault"
+)
+
+// indexedLink is an outgoing wikilink or embed of an indexed note.
+type indexedLink struct {
+	Target   string `json:"target"` // Without fragment, as written
+	Fragment string `json:"fragment,omitempty"`
+	Line     int    `json:"line"`
+	Embed    bool   `json:"embed,omitempty"`
+}
+
+// indexedNote is a note held in the in-memory vault index.
+type indexedNote struct {
+	*noteFile
+	Links   []indexedLink
+	Tags    []string // Lowercase, unique, sorted
+	Aliases []string
+	ModTime time.Time
+	Hash    string
+}
+
+// vaultIndex is an in-memory index of a vault's notes, links and folders
+// that can be updated one file at a time. It answers the same questions as
+// vault.ScanVault (dead links, orphans) with the same rules, without
+// rescanning the vault. Safe for concurrent use.
+type vaultIndex struct {
+	mu       sync.RWMutex
+	absPath  string
+	notes    map[string]*indexedNote    // Keyed by vault-relative path
+	folders  map[string]bool            // Lowercase relative folder paths, with and without trailing /
+	existing map[string]map[string]bool // Lowercase link keys (path, path.md, basename) -> notes providing them
+	incoming map[string]map[string]bool // Lowercase link target -> notes linking to it
+}
+
+// newVaultIndex indexes every note in the vault at absPath.
+func newVaultIndex(absPath string) (*vaultIndex, error) {
+	ix := &vaultIndex{
+		absPath:  absPath,
+		notes:    make(map[string]*indexedNote),
+		folders:  make(map[string]bool),
+		existing: make(map[string]map[string]bool),
+		incoming: make(map[string]map[string]bool),
+	}
+	if _, err := ix.addTree(""); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// readIndexedNote loads and parses a note for the index.
+func readIndexedNote(absPath, relPath string) (*indexedNote, error) {
+	path := filepath.Join(absPath, relPath)
+	if !isPathWithinVault(path, absPath) {
+		return nil, os.ErrNotExist
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newIndexedNote(absPath, relPath, data, info.ModTime()), nil
+}
+
+// newIndexedNote parses note content for the index.
+func newIndexedNote(absPath, relPath string, data []byte, modTime time.Time) *indexedNote {
+	note := &indexedNote{
+		noteFile: parseNote(absPath, filepath.Join(absPath, relPath), string(data)),
+		ModTime:  modTime,
+		Hash:     contentHash(data),
+	}
+	for _, l := range parseWikilinks(note.Content) {
+		if l.Target == "" {
+			continue
+		}
+		note.Links = append(note.Links, indexedLink{
+			Target:   l.Target,
+			Fragment: l.Fragment,
+			Line:     lineNumberAt(note.Content, l.Start),
+			Embed:    l.Embed,
+		})
+	}
+
+	tags := make(map[string]bool)
+	for _, occ := range noteTagOccurrences(note.noteFile) {
+		tags[strings.ToLower(occ.Tag)] = true
+	}
+	note.Tags = sortedKeys(tags)
+
+	for _, key := range []string{"aliases", "alias"} {
+		note.Aliases = append(note.Aliases, note.Frontmatter.Values(key)...)
+	}
+	return note
+}
+
+// linkKeys returns the lowercase keys a note can be linked by.
+func linkKeys(relPath string) []string {
+	return []string{
+		strings.ToLower(strings.TrimSuffix(relPath, ".md")),
+		strings.ToLower(relPath),
+		strings.ToLower(strings.TrimSuffix(filepath.Base(relPath), ".md")),
+	}
+}
+
+// put adds or replaces a note. Callers hold the write lock.
+func (ix *vaultIndex) put(note *indexedNote) {
+	ix.drop(note.RelPath)
+	ix.notes[note.RelPath] = note
+	for _, key := range linkKeys(note.RelPath) {
+		addToSet(ix.existing, key, note.RelPath)
+	}
+	for _, l := range note.Links {
+		if !vault.IsExternalLink(l.Target) {
+			addToSet(ix.incoming, strings.ToLower(l.Target), note.RelPath)
+		}
+	}
+}
+
+// drop removes a note if present. Callers hold the write lock.
+func (ix *vaultIndex) drop(relPath string) *indexedNote {
+	note, ok := ix.notes[relPath]
+	if !ok {
+		return nil
+	}
+	delete(ix.notes, relPath)
+	for _, key := range linkKeys(relPath) {
+		removeFromSet(ix.existing, key, relPath)
+	}
+	for _, l := range note.Links {
+		removeFromSet(ix.incoming, strings.ToLower(l.Target), relPath)
+	}
+	return note
+}
+
+// addToSet adds relPath to the set of notes under key.
+func addToSet(m map[string]map[string]bool, key, relPath string) {
+	if m[key] == nil {
+		m[key] = make(map[string]bool)
+	}
+	m[key][relPath] = true
+}
+
+// removeFromSet removes relPath from the set of notes under key, dropping
+// the key once no note is left.
+func removeFromSet(m map[string]map[string]bool, key, relPath string) {
+	if delete(m[key], relPath); len(m[key]) == 0 {
+		delete(m, key)
+	}
+}
+
+// update re-reads one note. A note that can no longer be read is removed.
+// Returns the previous and current versions (either may be nil).
+func (ix *vaultIndex) update(relPath string) (before, after *indexedNote) {
+	note, err := readIndexedNote(ix.absPath, relPath)
+	if err != nil {
+		return ix.set(relPath, nil), nil
+	}
+	return ix.set(relPath, note), note
+}
+
+// set replaces the note at relPath with note, or removes it when note is
+// nil. Returns the previous version.
+func (ix *vaultIndex) set(relPath string, note *indexedNote) *indexedNote {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	before := ix.notes[relPath]
+	if note == nil {
+		ix.drop(relPath)
+		return before
+	}
+	ix.put(note)
+	return before
+}
+
+// updateContent indexes content for a note that may not be saved yet (an
+// editor buffer). Returns the previous and current versions.
+func (ix *vaultIndex) updateContent(relPath, content string) (before, after *indexedNote) {
+	note := newIndexedNote(ix.absPath, relPath, []byte(content), time.Now())
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	before = ix.notes[relPath]
+	ix.put(note)
+	return before, note
+}
+
+// remove drops a note, or every note and folder under relPath when it was a
+// directory. Returns the removed notes.
+func (ix *vaultIndex) remove(relPath string) []*indexedNote {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	var removed []*indexedNote
+	if note := ix.drop(relPath); note != nil {
+		removed = append(removed, note)
+	}
+	prefix := relPath + string(filepath.Separator)
+	for _, rel := range sortedKeys(ix.notes) {
+		if strings.HasPrefix(rel, prefix) {
+			removed = append(removed, ix.drop(rel))
+		}
+	}
+	lower := strings.ToLower(relPath)
+	for folder := range ix.folders {
+		if folder == lower || folder == lower+"/" || strings.HasPrefix(folder, strings.ToLower(prefix)) {
+			delete(ix.folders, folder)
+		}
+	}
+	return removed
+}
+
+// addTree indexes every note and folder under relDir ("" for the whole
+// vault), e.g. after a folder is moved into place. Returns the added notes.
+func (ix *vaultIndex) addTree(relDir string) ([]*indexedNote, error) {
+	notes, folders, err := ix.readTree(relDir)
+	if err != nil {
+		return nil, err
+	}
+	ix.putTree(notes, folders)
+	return notes, nil
+}
+
+// readTree reads the notes and folders under relDir without indexing them.
+func (ix *vaultIndex) readTree(relDir string) ([]*indexedNote, []string, error) {
+	root := filepath.Join(ix.absPath, relDir)
+	var notes []*indexedNote
+	var folders []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skip, skipDir := shouldSkipEntry(path, d, ix.absPath); skip {
+			if skipDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel := mustRelPath(ix.absPath, path)
+		if d.IsDir() {
+			if rel != "." {
+				folders = append(folders, rel)
+			}
+			return nil
+		}
+		if strings.HasSuffix(strings.ToLower(path), ".md") {
+			if note, err := readIndexedNote(ix.absPath, rel); err == nil {
+				notes = append(notes, note)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return notes, folders, nil
+}
+
+// putTree indexes notes and folders read by readTree.
+func (ix *vaultIndex) putTree(notes []*indexedNote, folders []string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for _, f := range folders {
+		ix.folders[strings.ToLower(f)] = true
+		ix.folders[strings.ToLower(f+"/")] = true
+	}
+	for _, note := range notes {
+		ix.put(note)
+	}
+}
+
+// notesUnder returns the paths of indexed notes inside the folder relPath.
+func (ix *vaultIndex) notesUnder(relPath string) map[string]bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	prefix := relPath + string(filepath.Separator)
+	paths := make(map[string]bool)
+	for rel := range ix.notes {
+		if strings.HasPrefix(rel, prefix) {
+			paths[rel] = true
+		}
+	}
+	return paths
+}
+
+// foldersUnder returns the lowercase folder keys of relPath and the folders
+// inside it.
+func (ix *vaultIndex) foldersUnder(relPath string) []string {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	lower := strings.ToLower(relPath)
+	prefix := strings.ToLower(relPath + string(filepath.Separator))
+	var folders []string
+	for folder := range ix.folders {
+		if folder == lower || folder == lower+"/" || strings.HasPrefix(folder, prefix) {
+			folders = append(folders, folder)
+		}
+	}
+	return folders
+}
+
+// linking returns the notes with a link to one of the lowercase targets.
+func (ix *vaultIndex) linking(targets []string) map[string]bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	paths := make(map[string]bool)
+	for _, target := range targets {
+		for rel := range ix.incoming[target] {
+			paths[rel] = true
+		}
+	}
+	return paths
+}
+
+// linkedBy returns the notes one of the lowercase link targets names.
+func (ix *vaultIndex) linkedBy(targets []string) map[string]bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	paths := make(map[string]bool)
+	for _, target := range targets {
+		for rel := range ix.existing[target] {
+			paths[rel] = true
+		}
+	}
+	return paths
+}
+
+// note returns the indexed note at relPath, or nil.
+func (ix *vaultIndex) note(relPath string) *indexedNote {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.notes[relPath]
+}
+
+// allNotes returns every indexed note sorted by path.
+func (ix *vaultIndex) allNotes() []*indexedNote {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	notes := make([]*indexedNote, 0, len(ix.notes))
+	for _, rel := range sortedKeys(ix.notes) {
+		notes = append(notes, ix.notes[rel])
+	}
+	return notes
+}
+
+// isDeadLocked applies vault.ScanVault's dead link rules to a target.
+// Callers hold the read lock.
+func (ix *vaultIndex) isDeadLocked(target string) bool {
+	target = vault.NormalizeLink(target)
+	if target == "" || vault.IsExternalLink(target) || vault.IsAssetFile(target) {
+		return false
+	}
+	lower := strings.ToLower(target)
+	if strings.HasSuffix(target, "/") {
+		return !ix.folders[lower]
+	}
+	return len(ix.existing[lower]) == 0 && len(ix.existing[lower+".md"]) == 0
+}
+
+// isDead reports whether a link target points at nothing in the vault.
+func (ix *vaultIndex) isDead(target string) bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.isDeadLocked(target)
+}
+
+// isOrphanLocked applies vault.ScanVault's orphan rules to a note.
+// Callers hold the read lock.
+func (ix *vaultIndex) isOrphanLocked(relPath string) bool {
+	base := filepath.Base(relPath)
+	if strings.HasPrefix(base, "_") || strings.HasPrefix(base, "index") {
+		return false
+	}
+	for _, key := range linkKeys(relPath) {
+		if len(ix.incoming[key]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// deadLinks returns all dead links, sorted by source and line.
+func (ix *vaultIndex) deadLinks() []vault.DeadLink {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.deadLinksLocked(sortedKeys(ix.notes))
+}
+
+// deadLinksIn returns the dead links of the indexed notes among paths,
+// sorted by source and line.
+func (ix *vaultIndex) deadLinksIn(paths map[string]bool) []vault.DeadLink {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.deadLinksLocked(sortedKeys(paths))
+}
+
+// deadLinksLocked returns the dead links of the notes at rels, skipping
+// paths not indexed. Callers hold the read lock.
+func (ix *vaultIndex) deadLinksLocked(rels []string) []vault.DeadLink {
+	var dead []vault.DeadLink
+	for _, rel := range rels {
+		note, ok := ix.notes[rel]
+		if !ok {
+			continue
+		}
+		for _, l := range note.Links {
+			if ix.isDeadLocked(l.Target) {
+				dead = append(dead, vault.DeadLink{SourceFile: rel, Target: l.Target, Line: l.Line})
+			}
+		}
+	}
+	return dead
+}
+
+// orphans returns the notes no other note links to, sorted.
+func (ix *vaultIndex) orphans() []string {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.orphansLocked(sortedKeys(ix.notes))
+}
+
+// orphansIn returns the orphans among the indexed notes at paths, sorted.
+func (ix *vaultIndex) orphansIn(paths map[string]bool) []string {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.orphansLocked(sortedKeys(paths))
+}
+
+// orphansLocked returns the orphans among the notes at rels, skipping paths
+// not indexed. Callers hold the read lock.
+func (ix *vaultIndex) orphansLocked(rels []string) []string {
+	var orphans []string
+	for _, rel := range rels {
+		if _, ok := ix.notes[rel]; ok && ix.isOrphanLocked(rel) {
+			orphans = append(orphans, rel)
+		}
+	}
+	return orphans
+}
+
+// backlinks returns the links pointing at the note at relPath, matched the
+// way rename and backlinks match them (by path or basename).
+func (ix *vaultIndex) backlinks(relPath string) []vault.DeadLink {
+	keys := make(map[string]bool)
+	for _, key := range linkKeys(relPath) {
+		keys[key] = true
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	var links []vault.DeadLink
+	for _, rel := range sortedKeys(ix.notes) {
+		if rel == relPath {
+			continue
+		}
+		for _, l := range ix.notes[rel].Links {
+			if keys[strings.ToLower(l.Target)] {
+				links = append(links, vault.DeadLink{SourceFile: rel, Target: l.Target, Line: l.Line})
+			}
+		}
+	}
+	return links
+}
+
+// tagCounts returns the number of notes using each tag.
+func (ix *vaultIndex) tagCounts() map[string]int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	counts := make(map[string]int)
+	for _, note := range ix.notes {
+		for _, tag := range note.Tags {
+			counts[tag]++
+		}
+	}
+	return counts
+}
+
+// counts returns the number of notes, dead links and orphans.
+func (ix *vaultIndex) counts() (notes, dead, orphans int) {
+	return len(ix.allNotes()), len(ix.deadLinks()), len(ix.orphans())
+}
+
+// find looks up a note the way findNoteFile does: an exact vault-relative
+// path (without .md) wins, otherwise the basename must be unique.
+func (ix *vaultIndex) find(name string) (*indexedNote, error) {
+	name = strings.TrimSuffix(filepath.FromSlash(name), ".md")
+	lower := strings.ToLower(name)
+	baseLower := strings.ToLower(filepath.Base(name))
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	var baseMatches []string
+	for _, rel := range sortedKeys(ix.notes) {
+		relName := strings.ToLower(strings.TrimSuffix(rel, ".md"))
+		if relName == lower {
+			return ix.notes[rel], nil
+		}
+		if filepath.Base(relName) == baseLower {
+			baseMatches = append(baseMatches, rel)
+		}
+	}
+	switch len(baseMatches) {
+	case 0:
+		return nil, fmt.Errorf("note not found: %s", name)
+	case 1:
+		return ix.notes[baseMatches[0]], nil
+	}
+	return nil, fmt.Errorf("ambiguous note name %q matches multiple files: %v (use full path to disambiguate)", name, baseMatches)
+}
+
+// resolver returns a link resolver over the current notes.
+func (ix *vaultIndex) resolver() *noteIndex {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return newNoteIndex(sortedKeys(ix.notes))
+}
+
+// search returns the lines matching pattern in notes under folder ("" for
+// all), with contextLines lines of context around each match.
+func (ix *vaultIndex) search(pattern *regexp.Regexp, folder string, contextLines int) []SearchMatch {
+	prefix := ""
+	if folder = strings.Trim(filepath.FromSlash(folder), string(filepath.Separator)); folder != "" {
+		prefix = folder + string(filepath.Separator)
+	}
+
+	var matches []SearchMatch
+	for _, note := range ix.allNotes() {
+		if !strings.HasPrefix(note.RelPath, prefix) {
+			continue
+		}
+		lines := strings.Split(note.Content, "\n")
+		for i, line := range lines {
+			if !pattern.MatchString(line) {
+				continue
+			}
+			match := SearchMatch{File: note.RelPath, Line: i + 1, Content: strings.TrimSpace(line)}
+			if contextLines > 0 {
+				match.Context = getContextLines(lines, i, contextLines)
+			}
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// links returns a note's outgoing wikilinks, split into valid and dead the
+// way the links command reports them.
+func (ix *vaultIndex) links(note *indexedNote) *LinksResult {
+	res := ix.resolver()
+	result := &LinksResult{SourceFile: note.RelPath, ValidLinks: []LinkInfo{}, DeadLinks: []LinkInfo{}}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	seen := make(map[string]bool)
+	for _, l := range note.Links {
+		target := vault.NormalizeLink(l.Target)
+		if target == "" || vault.IsExternalLink(target) || seen[strings.ToLower(target)] {
+			continue
+		}
+		seen[strings.ToLower(target)] = true
+
+		info := LinkInfo{Target: target, Line: l.Line, Valid: !ix.isDeadLocked(target)}
+		if !info.Valid {
+			result.DeadLinks = append(result.DeadLinks, info)
+			continue
+		}
+		if path, ok := res.resolve(target); ok {
+			info.FullPath = filepath.FromSlash(path) + ".md"
+		}
+		result.ValidLinks = append(result.ValidLinks, info)
+	}
+	sort.Slice(result.ValidLinks, func(i, j int) bool { return result.ValidLinks[i].Target < result.ValidLinks[j].Target })
+	sort.Slice(result.DeadLinks, func(i, j int) bool { return result.DeadLinks[i].Target < result.DeadLinks[j].Target })
+	result.TotalLinks = len(result.ValidLinks) + len(result.DeadLinks)
+	return result
+}
+
+// backlinkResults returns the backlinks to relPath with the linking line
+// as context, like the backlinks command.
+func (ix *vaultIndex) backlinkResults(relPath string) []BacklinkResult {
+	results := []BacklinkResult{}
+	for _, bl := range ix.backlinks(relPath) {
+		result := BacklinkResult{SourceFile: bl.SourceFile, Line: bl.Line}
+		if src := ix.note(bl.SourceFile); src != nil {
+			if lines := strings.Split(src.Content, "\n"); bl.Line <= len(lines) {
+				result.Context = strings.TrimSpace(lines[bl.Line-1])
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// tagList returns every tag with its note count, most used first.
+func (ix *vaultIndex) tagList() []TagInfo {
+	counts := ix.tagCounts()
+	tags := make([]TagInfo, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, TagInfo{Name: name, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	return tags
+}
+
+// notesWithTag returns the notes using tag (with or without #) or one of
+// its nested tags (#project matches #project/x), sorted by path.
+func (ix *vaultIndex) notesWithTag(tag string) []string {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	files := []string{}
+	for _, note := range ix.allNotes() {
+		for _, t := range note.Tags {
+			if t == tag || strings.HasPrefix(t, tag+"/") {
+				files = append(files, note.RelPath)
+				break
+			}
+		}
+	}
+	return files
+}
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// journalDir is where operation journals are stored, relative to the vault.
+// Hidden directories are skipped by every vault scan.
+var journalDir = filepath.Join(".obsidian-cli", "journal")
+
+// Journal step kinds
+const (
+	journalWrite  = "write"  // Existing file overwritten; Content holds the original
+	journalCreate = "create" // New file created
+	journalMove   = "move"   // File renamed From -> Path
+	journalDelete = "delete" // File removed; Content holds the original
+)
+
+// journalStep records one file-system change made by a vault operation.
+type journalStep struct {
+	Kind      string      `json:"kind"`
+	Path      string      `json:"path"`           // Vault-relative path affected
+	From      string      `json:"from,omitempty"` // Original path for moves
+	Content   []byte      `json:"content,omitempty"`
+	Mode      os.FileMode `json:"mode,omitempty"`
+	AfterHash string      `json:"after_hash,omitempty"` // SHA-256 of the content written
+}
+
+// journal records the changes made by a mutating command so it can be undone.
+type journal struct {
+	ID          string        `json:"id"`
+	Operation   string        `json:"operation"`
+	Description string        `json:"description"`
+	Timestamp   string        `json:"timestamp"`
+	Nanos       int64         `json:"nanos,omitempty"` // Creation time in Unix nanoseconds, for ordering
+	Steps       []journalStep `json:"steps"`
+	Undone      bool          `json:"undone,omitempty"`
+
+	absPath string
+}
+
+// newJournal starts a journal for an operation on the vault at absPath.
+func newJournal(absPath, operation, description string) *journal {
+	now := time.Now()
+	return &journal{
+		ID:          fmt.Sprintf("%s-%s-%s", now.Format("20060102-150405"), operation, randomHex(4)),
+		Operation:   operation,
+		Description: description,
+		Timestamp:   now.Format(time.RFC3339),
+		Nanos:       now.UnixNano(),
+		absPath:     absPath,
+	}
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeFile writes content to a vault-relative path, recording the original
+// content (or the creation) first. Existing file modes are preserved.
+func (j *journal) writeFile(relPath string, content []byte, mode os.FileMode) error {
+	fullPath := filepath.Join(j.absPath, relPath)
+	if !isPathWithinVault(fullPath, j.absPath) {
+		return fmt.Errorf("path escapes vault boundary: %s", relPath)
+	}
+
+	step := journalStep{Kind: journalCreate, Path: relPath, AfterHash: contentHash(content)}
+	if info, err := os.Stat(fullPath); err == nil {
+		original, err := os.ReadFile(fullPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+		step.Kind = journalWrite
+		step.Content = original
+		step.Mode = info.Mode()
+		mode = info.Mode()
+	} else if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := os.WriteFile(fullPath, content, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", relPath, err)
+	}
+	j.Steps = append(j.Steps, step)
+	return nil
+}
+
+// replaceFile atomically replaces an existing vault-relative file: the
+// content is written to a temporary file in the same folder, which is
+// renamed over it, so readers never see a partial note. The file's mode
+// is kept and the original recorded.
+func (j *journal) replaceFile(relPath string, content []byte) error {
+	fullPath := filepath.Join(j.absPath, relPath)
+	if !isPathWithinVault(fullPath, j.absPath) {
+		return fmt.Errorf("path escapes vault boundary: %s", relPath)
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return fmt.Errorf("cannot access %s: %w", relPath, err)
+	}
+	original, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), "."+filepath.Base(fullPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", relPath, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(content)
+	if err == nil {
+		err = tmp.Chmod(info.Mode())
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), fullPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", relPath, err)
+	}
+	j.Steps = append(j.Steps, journalStep{Kind: journalWrite, Path: relPath, Content: original, Mode: info.Mode(), AfterHash: contentHash(content)})
+	return nil
+}
+
+// move renames a vault-relative file, refusing to overwrite an existing one.
+func (j *journal) move(fromRel, toRel string) error {
+	from := filepath.Join(j.absPath, fromRel)
+	to := filepath.Join(j.absPath, toRel)
+	if !isPathWithinVault(from, j.absPath) || !isPathWithinVault(to, j.absPath) {
+		return fmt.Errorf("path escapes vault boundary: %s -> %s", fromRel, toRel)
+	}
+	if _, err := os.Lstat(to); err == nil {
+		return fmt.Errorf("destination already exists: %s", toRel)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("failed to move %s: %w", fromRel, err)
+	}
+	j.Steps = append(j.Steps, journalStep{Kind: journalMove, Path: toRel, From: fromRel})
+	return nil
+}
+
+// remove deletes a vault-relative file, keeping its content for undo.
+func (j *journal) remove(relPath string) error {
+	fullPath := filepath.Join(j.absPath, relPath)
+	if !isPathWithinVault(fullPath, j.absPath) {
+		return fmt.Errorf("path escapes vault boundary: %s", relPath)
+	}
+	info, err := os.Lstat(fullPath)
+	if err != nil {
+		return fmt.Errorf("cannot access %s: %w", relPath, err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("refusing to remove symlink: %s", relPath)
+	}
+	original, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+	if err := os.Remove(fullPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", relPath, err)
+	}
+	j.Steps = append(j.Steps, journalStep{Kind: journalDelete, Path: relPath, Content: original, Mode: info.Mode()})
+	return nil
+}
+
+// save writes the journal to <vault>/.obsidian-cli/journal/<id>.json.
+// Journals without steps are not saved.
+func (j *journal) save() error {
+	if len(j.Steps) == 0 {
+		return nil
+	}
+	dir := filepath.Join(j.absPath, journalDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, j.ID+".json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// loadJournals returns all journals for the vault, newest first.
+func loadJournals(absPath string) ([]*journal, error) {
+	dir := filepath.Join(absPath, journalDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read journal directory: %w", err)
+	}
+
+	var journals []*journal
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		j := &journal{}
+		if err := json.Unmarshal(data, j); err != nil {
+			continue // Skip malformed journals
+		}
+		j.absPath = absPath
+		if j.Nanos == 0 {
+			// Journals written before Nanos was recorded
+			if t, err := time.Parse(time.RFC3339, j.Timestamp); err == nil {
+				j.Nanos = t.UnixNano()
+			}
+		}
+		journals = append(journals, j)
+	}
+
+	// IDs only have second precision: operations run in the same second
+	// are ordered by creation time
+	sort.Slice(journals, func(a, b int) bool {
+		if journals[a].Nanos != journals[b].Nanos {
+			return journals[a].Nanos > journals[b].Nanos
+		}
+		return journals[a].ID > journals[b].ID
+	})
+	return journals, nil
+}
+
+// undo reverts the journal's steps in reverse order. Unless force is set,
+// files changed since the operation are left alone and reported as conflicts.
+func (j *journal) undo(force bool) error {
+	// Check for conflicts before touching anything
+	if !force {
+		for path, hash := range j.expectedHashes() {
+			current, err := os.ReadFile(filepath.Join(j.absPath, path))
+			if err != nil || contentHash(current) != hash {
+				return fmt.Errorf("%s was modified after %s (use --force to undo anyway)", path, j.ID)
+			}
+		}
+	}
+
+	for i := len(j.Steps) - 1; i >= 0; i-- {
+		step := j.Steps[i]
+		fullPath := filepath.Join(j.absPath, step.Path)
+		if !isPathWithinVault(fullPath, j.absPath) {
+			return fmt.Errorf("journal path escapes vault boundary: %s", step.Path)
+		}
+
+		switch step.Kind {
+		case journalWrite, journalDelete:
+			if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			if err := os.WriteFile(fullPath, step.Content, step.Mode); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", step.Path, err)
+			}
+		case journalCreate:
+			if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %w", step.Path, err)
+			}
+		case journalMove:
+			from := filepath.Join(j.absPath, step.From)
+			if !isPathWithinVault(from, j.absPath) {
+				return fmt.Errorf("journal path escapes vault boundary: %s", step.From)
+			}
+			if _, err := os.Lstat(from); err == nil {
+				return fmt.Errorf("cannot move %s back: %s already exists", step.Path, step.From)
+			}
+			if err := os.MkdirAll(filepath.Dir(from), 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			if err := os.Rename(fullPath, from); err != nil {
+				return fmt.Errorf("failed to move %s back: %w", step.Path, err)
+			}
+		}
+	}
+
+	j.Undone = true
+	return j.save()
+}
+
+// expectedHashes returns the content hash each written file should still
+// have, keyed by its path once the whole operation completed.
+func (j *journal) expectedHashes() map[string]string {
+	hashes := make(map[string]string)
+	for _, step := range j.Steps {
+		switch step.Kind {
+		case journalWrite, journalCreate:
+			hashes[step.Path] = step.AfterHash
+		case journalMove:
+			if h, ok := hashes[step.From]; ok {
+				delete(hashes, step.From)
+				hashes[step.Path] = h
+			}
+		case journalDelete:
+			delete(hashes, step.Path)
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import "testing"
+
+// TestLoadJournalsOrder tests that operations run within the same second
+// are listed in the order they ran, not by ID
+func TestLoadJournalsOrder(t *testing.T) {
+	dir := t.TempDir()
+	for i, op := range []string{"note-insert", "note-replace-section", "note-append"} {
+		j := newJournal(dir, op, op)
+		j.ID = "20250115-103000-" + op + "-abcd"
+		j.Nanos += int64(i) // Same second, increasing time
+		if err := j.writeFile(op+".md", []byte(op), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := j.save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	journals, err := loadJournals(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []string
+	for _, j := range journals {
+		ops = append(ops, j.Operation)
+	}
+	if len(ops) != 3 || ops[0] != "note-append" || ops[2] != "note-insert" {
+		t.Errorf("order = %v, want note-append first and note-insert last", ops)
+	}
+}
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kofifort/obsidian-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var (
+	linksConvertTo         string
+	linksConvertLinkFormat string
+	linksConvertFolder     string
+	linksConvertDryRun     bool
+	linksConvertFormat     string
+)
+
+var linksConvertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert links between wikilink and markdown syntax",
+	Long: `Rewrites internal links in place to one syntax:
+
+  --to markdown   [[note#Heading|alias]] -> [alias](note.md#Heading)
+                  ![[image.png|300]]     -> ![300](image.png)
+  --to wikilink   the reverse
+
+Aliases, heading and block fragments, and embed sizes are preserved.
+Link paths follow the vault's "New link format" setting (.obsidian/app.json):
+shortest (name only when unique), relative (to the note's folder) or
+absolute (from the vault root); override with --link-format.
+
+Links in code, frontmatter and external URLs are left alone, as are
+markdown links with a title or to files that don't exist (except .md).
+Changes are journaled (revert with "undo").
+
+Examples:
+  obsidian-cli links convert --to wikilink --vault ~/notes --dry-run
+  obsidian-cli links convert --to markdown --vault ~/notes --folder Docs
+  obsidian-cli links convert --to markdown --vault ~/notes --link-format relative`,
+	Args: cobra.NoArgs,
+	RunE: runLinksConvert,
+}
+
+func init() {
+	linksCmd.AddCommand(linksConvertCmd)
+	linksConvertCmd.Flags().StringVar(&linksConvertTo, "to", "", "Target syntax: markdown, wikilink (required)")
+	linksConvertCmd.Flags().StringVar(&linksConvertLinkFormat, "link-format", "", "Link paths: shortest, relative, absolute (default: vault setting)")
+	linksConvertCmd.Flags().StringVarP(&linksConvertFolder, "folder", "f", "", "Only convert notes in this folder")
+	linksConvertCmd.Flags().BoolVar(&linksConvertDryRun, "dry-run", false, "Preview changes without modifying files")
+	linksConvertCmd.Flags().StringVar(&linksConvertFormat, "format", "text", "Output format: text, json")
+	_ = linksConvertCmd.MarkFlagRequired("to")
+}
+
+// LinkConvertFile is the number of links converted in one note.
+type LinkConvertFile struct {
+	File  string `json:"file"`
+	Links int    `json:"links"`
+}
+
+// LinkConvertResult is the result of links convert.
+type LinkConvertResult struct {
+	To             string            `json:"to"`
+	LinkFormat     string            `json:"link_format"`
+	Files          []LinkConvertFile `json:"files"`
+	LinksConverted int               `json:"links_converted"`
+	FilesModified  int               `json:"files_modified"`
+	Executed       bool              `json:"executed"`
+	JournalID      string            `json:"journal_id,omitempty"`
+}
+
+// linkConverter rewrites links between wikilink and markdown syntax.
+type linkConverter struct {
+	notes  *noteIndex
+	assets *noteIndex // Paths keep their extension
+	format string     // shortest, relative or absolute
+}
+
+func runLinksConvert(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	if linksConvertTo != "markdown" && linksConvertTo != "wikilink" {
+		return fmt.Errorf("invalid --to %q (expected markdown or wikilink)", linksConvertTo)
+	}
+	start := time.Now()
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+
+	format := linksConvertLinkFormat
+	if format == "" {
+		settings, err := loadObsidianAppSettings(absPath)
+		if err != nil {
+			return err
+		}
+		format = settings.NewLinkFormat
+	}
+	if format != "shortest" && format != "relative" && format != "absolute" {
+		return fmt.Errorf("invalid link format %q (expected shortest, relative or absolute)", format)
+	}
+
+	notes, err := loadNotes(absPath)
+	if err != nil {
+		return err
+	}
+	assets, err := collectAssetFiles(absPath)
+	if err != nil {
+		return err
+	}
+	lc := &linkConverter{notes: newNoteIndexFromNotes(notes), assets: newNoteIndex(assets), format: format}
+
+	result := &LinkConvertResult{To: linksConvertTo, LinkFormat: format, Files: []LinkConvertFile{}, Executed: !linksConvertDryRun}
+	oldContent := make(map[string]string)
+	newContent := make(map[string]string)
+	for _, note := range notes {
+		if !folderMatches(note.RelPath, linksConvertFolder) {
+			continue
+		}
+		content, n := lc.convert(note, linksConvertTo == "markdown")
+		if n == 0 {
+			continue
+		}
+		oldContent[note.RelPath] = note.Content
+		newContent[note.RelPath] = content
+		result.Files = append(result.Files, LinkConvertFile{File: filepath.ToSlash(note.RelPath), Links: n})
+		result.LinksConverted += n
+	}
+	result.FilesModified = len(newContent)
+
+	if linksConvertFormat == "json" {
+		if !linksConvertDryRun {
+			if result.JournalID, err = writeConvertedLinks(absPath, newContent); err != nil {
+				return err
+			}
+		}
+		return encodeJSON(cmd, result)
+	}
+
+	printLinkConversion(result, oldContent, newContent, time.Since(start))
+	if len(newContent) == 0 {
+		return nil
+	}
+	if linksConvertDryRun {
+		fmt.Printf("  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
+		return nil
+	}
+	journalID, err := writeConvertedLinks(absPath, newContent)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s Converted %d links in %d files\n", colors.Green("✓"), result.LinksConverted, result.FilesModified)
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n\n", colors.Dim("i"), journalID)
+	return nil
+}
+
+// convert rewrites the links in a note's body outside code. Returns the new
+// content and the number of links converted.
+func (lc *linkConverter) convert(note *noteFile, toMarkdown bool) (string, int) {
+	src := filepath.ToSlash(note.RelPath)
+	count := 0
+	body := mapOutsideFences(note.Body, 1, func(chunk string, _ int) string {
+		return mapOutsideInlineCode(chunk, 1, func(piece string, _ int) string {
+			var out string
+			var n int
+			if toMarkdown {
+				out, n = rewriteWikilinks(piece, func(l wikilink) (string, bool) {
+					return lc.toMarkdown(src, l)
+				})
+			} else {
+				out, n = lc.toWikilinks(src, piece)
+			}
+			count += n
+			return out
+		})
+	})
+	return note.Content[:len(note.Content)-len(note.Body)] + body, count
+}
+
+// toMarkdown converts a wikilink in note src to a markdown link. Links to
+// missing notes keep their target as written; links to missing attachments
+// are left alone, as toWikilink does for them.
+func (lc *linkConverter) toMarkdown(src string, l wikilink) (string, bool) {
+	if l.Target == "" && l.Fragment == "" {
+		return "", false
+	}
+
+	dest := ""
+	if l.Target != "" {
+		if p, ok := lc.notes.resolve(l.Target); ok {
+			dest = lc.markdownPath(src, p+".md", lc.notes.ambiguous(pathBase(p)))
+		} else if a, ok := lc.assets.resolve(l.Target); ok {
+			dest = lc.markdownPath(src, a, lc.assets.ambiguous(pathBase(a)))
+		} else {
+			file := strings.TrimPrefix(l.Target, "/")
+			if vault.IsAssetFile(file) {
+				return "", false
+			}
+			if !strings.HasSuffix(strings.ToLower(file), ".md") {
+				file += ".md"
+			}
+			dest = encodeLinkPath(file)
+		}
+	}
+	if l.Fragment != "" {
+		dest += "#" + encodeLinkPath(strings.TrimPrefix(l.Fragment, "#"))
+	}
+
+	text := l.Alias
+	if !l.HasAlias && !l.Embed {
+		text = l.Target + l.Fragment
+	}
+	if l.Embed {
+		return "![" + text + "](" + dest + ")", true
+	}
+	return "[" + text + "](" + dest + ")", true
+}
+
+// markdownPath returns the destination of a markdown link from note src to
+// vault file, per the link format.
+func (lc *linkConverter) markdownPath(src, file string, ambiguous bool) string {
+	switch {
+	case lc.format == "relative":
+		return encodeLinkPath(relativeLinkPath(src, file))
+	case lc.format == "shortest" && !ambiguous:
+		return encodeLinkPath(pathBase(file))
+	}
+	return encodeLinkPath(file)
+}
+
+// toWikilinks converts the markdown links to vault files in text.
+func (lc *linkConverter) toWikilinks(src, text string) (string, int) {
+	var b strings.Builder
+	last, count := 0, 0
+	for _, m := range markdownLinkRegex.FindAllStringSubmatchIndex(text, -1) {
+		replacement, ok := lc.toWikilink(src, text, m)
+		if !ok {
+			continue
+		}
+		b.WriteString(text[last:m[0]])
+		b.WriteString(replacement)
+		last = m[1]
+		count++
+	}
+	if count == 0 {
+		return text, 0
+	}
+	b.WriteString(text[last:])
+	return b.String(), count
+}
+
+// toWikilink converts one markdown link match. ok is false for links that
+// are left alone: external, titled, or to missing non-note files.
+func (lc *linkConverter) toWikilink(src, text string, m []int) (string, bool) {
+	ml := parseMarkdownLink(text, m)
+	if ml.Title != "" || vault.IsExternalLink(ml.Dest) || strings.Contains(ml.Dest, ":") {
+		return "", false
+	}
+	embed, label, target, fragment := ml.Embed, ml.Label, ml.Target, ml.Fragment
+
+	name := ""
+	if target != "" {
+		var ok bool
+		if name, ok = lc.wikilinkTarget(src, target); !ok {
+			return "", false
+		}
+	}
+	body := name + fragment
+	if body == "" || strings.ContainsAny(name, "[]|#^") {
+		return "", false
+	}
+
+	l := wikilink{Embed: embed, Target: name, Fragment: fragment, Alias: label, HasAlias: label != "" && label != body}
+	return l.String(), true
+}
+
+// wikilinkTarget returns the wikilink target for a markdown link
+// destination in note src, per the link format. Destinations are tried
+// relative to the note, then from the vault root. Missing .md files keep
+// the destination as written.
+func (lc *linkConverter) wikilinkTarget(src, target string) (string, bool) {
+	candidates := []string{path.Join(path.Dir(src), target), strings.TrimPrefix(target, "/")}
+	ext := strings.ToLower(path.Ext(target))
+	if ext == ".md" || ext == "" {
+		for _, cand := range candidates {
+			if p, ok := lc.notes.resolve(cand); ok {
+				switch lc.format {
+				case "relative":
+					return strings.TrimSuffix(relativeLinkPath(src, p+".md"), ".md"), true
+				case "shortest":
+					return lc.notes.linkText(p), true
+				}
+				return p, true
+			}
+		}
+		if ext == ".md" {
+			return strings.TrimSuffix(target, path.Ext(target)), true
+		}
+		return "", false
+	}
+	for _, cand := range candidates {
+		if a, ok := lc.assets.resolve(cand); ok {
+			switch {
+			case lc.format == "relative":
+				return relativeLinkPath(src, a), true
+			case lc.format == "shortest" && !lc.assets.ambiguous(pathBase(a)):
+				return pathBase(a), true
+			}
+			return a, true
+		}
+	}
+	return "", false
+}
+
+// relativeLinkPath returns the path of vault file relative to the folder of
+// note src, both slash-separated vault paths.
+func relativeLinkPath(src, file string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(path.Dir(src)), filepath.FromSlash(file))
+	if err != nil {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+// encodeLinkPath escapes the characters that end or break a markdown link
+// destination, the way Obsidian writes them.
+func encodeLinkPath(p string) string {
+	return strings.NewReplacer("%", "%25", " ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(p)
+}
+
+// writeConvertedLinks writes converted notes through a journal. Returns the journal ID.
+func writeConvertedLinks(absPath string, newContent map[string]string) (journalID string, err error) {
+	if len(newContent) == 0 {
+		return "", nil
+	}
+
+	j := newJournal(absPath, "convert", fmt.Sprintf("convert links to %s in %d files", linksConvertTo, len(newContent)))
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	for _, file := range sortedKeys(newContent) {
+		if err := j.writeFile(file, []byte(newContent[file]), 0644); err != nil {
+			return j.ID, err
+		}
+	}
+	return j.ID, nil
+}
+
+func printLinkConversion(result *LinkConvertResult, oldContent, newContent map[string]string, elapsed time.Duration) {
+	title := "Convert Links to " + result.To
+	if linksConvertDryRun {
+		title += " (dry run)"
+	}
+	fmt.Printf("%s %s %s\n\n", colors.Green("→"), title, colors.Dim("("+result.LinkFormat+" paths)"))
+
+	if len(newContent) == 0 {
+		fmt.Printf("  No links to convert.\n\n")
+		fmt.Printf("  %s %s\n", colors.Cyan("Analyzed in:"), elapsed.Round(time.Millisecond))
+		return
+	}
+
+	for _, f := range result.Files {
+		fmt.Printf("    %s %s\n", colors.Cyan(f.File), colors.Dim(fmt.Sprintf("(%d links)", f.Links)))
+		if linksConvertDryRun {
+			file := filepath.FromSlash(f.File)
+			printDiff(diffLines(oldContent[file], newContent[file]), 0)
+		}
+	}
+	fmt.Println()
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import "testing"
+
+// TestLinkConvertRoundTrip tests wikilink -> markdown -> wikilink conversion
+func TestLinkConvertRoundTrip(t *testing.T) {
+	lc := &linkConverter{
+		notes:  newNoteIndex([]string{"a/src.md", "b/My Note.md", "a/dup.md", "b/dup.md"}),
+		assets: newNoteIndex([]string{"img/pic.png"}),
+		format: "shortest",
+	}
+	original := "[[My Note#Sec Two|alias]] [[a/dup]] [[b/dup#^blk]] ![[pic.png|300]] [[Missing]] [[gone.pdf]] `[[code]]`\n"
+	wantMarkdown := "[alias](My%20Note.md#Sec%20Two) [a/dup](a/dup.md) [b/dup#^blk](b/dup.md#^blk) ![300](pic.png) [Missing](Missing.md) [[gone.pdf]] `[[code]]`\n"
+
+	md, n := lc.convert(parseNote("/v", "/v/a/src.md", original), true)
+	if md != wantMarkdown || n != 5 {
+		t.Fatalf("to markdown = %q (%d links), want %q", md, n, wantMarkdown)
+	}
+	back, n := lc.convert(parseNote("/v", "/v/a/src.md", md), false)
+	if back != original || n != 5 {
+		t.Errorf("to wikilink = %q (%d links), want %q", back, n, original)
+	}
+
+	if _, n := lc.convert(parseNote("/v", "/v/a/src.md", "![g](gone.pdf)"), false); n != 0 {
+		t.Errorf("missing attachment converted to wikilink (%d links)", n)
+	}
+
+	lc.format = "relative"
+	if got, _ := lc.convert(parseNote("/v", "/v/a/src.md", "[[My Note]]"), true); got != "[My Note](../b/My%20Note.md)" {
+		t.Errorf("relative = %q", got)
+	}
+}
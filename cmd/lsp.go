@@ -0,0 +1,1026 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/kofifort/obsidian-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a Language Server Protocol server for the vault over stdio",
+	Long: `Implements the Language Server Protocol over stdin/stdout for editing
+vault notes in Neovim, VS Code, Helix and other LSP-capable editors.
+
+Features:
+  Completion    Note names and aliases after [[, headings after [[note#,
+                block IDs after [[note#^
+  Definition    Jump from a [[link]] to the note, heading or block
+  References    Every link to the note or heading under the cursor
+  Diagnostics   Dead links, missing headings and block IDs, invalid frontmatter
+  Rename        Rename a note (updating backlinks, like rename) or a heading
+                (updating [[note#Heading]] links)
+  Hover         Preview of the linked note, section or block
+
+The vault is the --vault path, or else the workspace root sent by the
+editor. Unsaved changes in open notes are taken into account.
+
+Example Neovim configuration:
+  vim.lsp.start({ name = "obsidian", cmd = { "obsidian-cli", "lsp" },
+                  root_dir = "/path/to/vault" })`,
+	RunE: runLSP,
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+	// Many clients pass --stdio; it's the only transport
+	lspCmd.Flags().Bool("stdio", true, "Use stdin/stdout (the only transport)")
+	_ = lspCmd.Flags().MarkHidden("stdio")
+}
+
+// LSP error code for requests sent before initialize
+const lspServerNotInitialized = -32002
+
+// Lines shown in hover previews
+const lspHoverLines = 20
+
+// LSP severities and completion item kinds used by the server
+const (
+	lspSeverityError   = 1
+	lspSeverityWarning = 2
+
+	lspKindFile      = 17
+	lspKindReference = 18
+	lspKindEnum      = 20
+	lspKindStruct    = 22
+)
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"` // UTF-16 code units
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code,omitempty"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspCompletionItem struct {
+	Label      string      `json:"label"`
+	Kind       int         `json:"kind"`
+	Detail     string      `json:"detail,omitempty"`
+	FilterText string      `json:"filterText,omitempty"`
+	SortText   string      `json:"sortText,omitempty"`
+	TextEdit   lspTextEdit `json:"textEdit"`
+}
+
+type lspDocumentPosition struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position lspPosition `json:"position"`
+}
+
+// lspServer serves one editor session. Requests are handled in order on
+// the read loop; the vault watcher republishes diagnostics from its own
+// goroutine, so open documents and output are guarded.
+type lspServer struct {
+	ix        *vaultIndex
+	canRename bool // Client supports file rename operations in workspace edits
+
+	mu   sync.Mutex
+	docs map[string]string // Open documents: vault-relative path -> text
+
+	outMu sync.Mutex
+	w     io.Writer
+
+	cancelWatch context.CancelFunc
+}
+
+func runLSP(cmd *cobra.Command, args []string) error {
+	s := &lspServer{docs: make(map[string]string), w: cmd.OutOrStdout()}
+	defer func() {
+		if s.cancelWatch != nil {
+			s.cancelWatch()
+		}
+	}()
+	return s.serve(cmd.InOrStdin())
+}
+
+// serve reads Content-Length framed messages until exit or EOF.
+func (s *lspServer) serve(r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for {
+		body, err := readLSPMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.send(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &rpcError{rpcParseError, "parse error: " + err.Error()}})
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		if len(req.ID) == 0 {
+			s.notify(&req)
+			continue
+		}
+
+		resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+		resp.Result, resp.Error = s.handle(&req)
+		s.send(resp)
+	}
+}
+
+// readLSPMessage reads one "Content-Length: N\r\n\r\n<body>" message.
+func readLSPMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length == -1 {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to read header: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			if length, err = strconv.Atoi(strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("invalid Content-Length: %q", value)
+			}
+		}
+	}
+	if length < 0 || length > maxServeBody {
+		return nil, fmt.Errorf("missing or invalid Content-Length")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+	return body, nil
+}
+
+// send writes one framed message.
+func (s *lspServer) send(v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n", len(body))
+	_, _ = s.w.Write(body)
+}
+
+func (s *lspServer) handle(req *rpcRequest) (any, *rpcError) {
+	if req.Method == "initialize" {
+		return s.initialize(req.Params)
+	}
+	if s.ix == nil {
+		return nil, &rpcError{lspServerNotInitialized, "server not initialized"}
+	}
+
+	var pos lspDocumentPosition
+	_ = json.Unmarshal(req.Params, &pos)
+
+	switch req.Method {
+	case "shutdown":
+		return nil, nil
+	case "textDocument/completion":
+		return s.completion(&pos), nil
+	case "textDocument/definition":
+		return s.definition(&pos), nil
+	case "textDocument/references":
+		return s.references(&pos), nil
+	case "textDocument/hover":
+		return s.hover(&pos), nil
+	case "textDocument/prepareRename":
+		return s.prepareRename(&pos), nil
+	case "textDocument/rename":
+		var params struct {
+			NewName string `json:"newName"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		edit, err := s.rename(&pos, params.NewName)
+		if err != nil {
+			return nil, &rpcError{rpcInvalidRequest, err.Error()}
+		}
+		return edit, nil
+	}
+	return nil, &rpcError{rpcMethodNotFound, "method not found: " + req.Method}
+}
+
+func (s *lspServer) initialize(params json.RawMessage) (any, *rpcError) {
+	var in struct {
+		RootURI          string `json:"rootUri"`
+		RootPath         string `json:"rootPath"`
+		WorkspaceFolders []struct {
+			URI string `json:"uri"`
+		} `json:"workspaceFolders"`
+		Capabilities struct {
+			Workspace struct {
+				WorkspaceEdit struct {
+					DocumentChanges    bool     `json:"documentChanges"`
+					ResourceOperations []string `json:"resourceOperations"`
+				} `json:"workspaceEdit"`
+			} `json:"workspace"`
+		} `json:"capabilities"`
+	}
+	if err := json.Unmarshal(params, &in); err != nil {
+		return nil, &rpcError{rpcInvalidParams, "invalid params: " + err.Error()}
+	}
+
+	root := vaultPath
+	if root == "" {
+		switch {
+		case in.RootURI != "":
+			root = uriToPath(in.RootURI)
+		case len(in.WorkspaceFolders) > 0:
+			root = uriToPath(in.WorkspaceFolders[0].URI)
+		default:
+			root = in.RootPath
+		}
+	}
+	if root == "" {
+		return nil, &rpcError{rpcInvalidParams, "no vault: pass --vault or open a workspace folder"}
+	}
+	absPath, err := filepath.Abs(root)
+	if err != nil {
+		return nil, &rpcError{rpcInvalidParams, "invalid vault path: " + err.Error()}
+	}
+	if s.ix, err = newVaultIndex(absPath); err != nil {
+		return nil, &rpcError{rpcInternalError, err.Error()}
+	}
+
+	we := in.Capabilities.Workspace.WorkspaceEdit
+	for _, op := range we.ResourceOperations {
+		if op == "rename" && we.DocumentChanges {
+			s.canRename = true
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancelWatch = cancel
+	go func() {
+		err := watchVault(ctx, s.ix, nil, func(events []WatchEvent) {
+			if len(events) > 0 {
+				s.refreshOpenDocs()
+			}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		}
+	}()
+
+	return map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync": map[string]any{"openClose": true, "change": 1, "save": true}, // Full sync
+			"completionProvider": map[string]any{
+				"triggerCharacters": []string{"[", "#", "^"},
+			},
+			"definitionProvider": true,
+			"referencesProvider": true,
+			"hoverProvider":      true,
+			"renameProvider":     map[string]any{"prepareProvider": true},
+		},
+		"serverInfo": map[string]any{"name": "obsidian-cli"},
+	}, nil
+}
+
+// notify handles a client notification.
+func (s *lspServer) notify(req *rpcRequest) {
+	if s.ix == nil {
+		return
+	}
+	var params struct {
+		TextDocument struct {
+			URI  string `json:"uri"`
+			Text string `json:"text"`
+		} `json:"textDocument"`
+		ContentChanges []struct {
+			Text string `json:"text"`
+		} `json:"contentChanges"`
+	}
+	_ = json.Unmarshal(req.Params, &params)
+	rel, ok := s.relPath(params.TextDocument.URI)
+	if !ok {
+		return
+	}
+
+	switch req.Method {
+	case "textDocument/didOpen":
+		s.setDoc(rel, params.TextDocument.Text)
+	case "textDocument/didChange":
+		if n := len(params.ContentChanges); n > 0 {
+			s.setDoc(rel, params.ContentChanges[n-1].Text)
+		}
+	case "textDocument/didClose":
+		s.mu.Lock()
+		delete(s.docs, rel)
+		s.mu.Unlock()
+		s.ix.update(rel) // Back to the saved version
+		s.send(map[string]any{"jsonrpc": "2.0", "method": "textDocument/publishDiagnostics",
+			"params": map[string]any{"uri": params.TextDocument.URI, "diagnostics": []lspDiagnostic{}}})
+		s.publishAll()
+	}
+}
+
+// setDoc records an open document's text, indexes it and republishes
+// diagnostics (other open notes may link to it).
+func (s *lspServer) setDoc(rel, text string) {
+	s.mu.Lock()
+	s.docs[rel] = text
+	s.mu.Unlock()
+	s.ix.updateContent(rel, text)
+	s.publishAll()
+}
+
+// refreshOpenDocs re-applies open buffers after the watcher re-read files
+// from disk, then republishes diagnostics.
+func (s *lspServer) refreshOpenDocs() {
+	s.mu.Lock()
+	docs := make(map[string]string, len(s.docs))
+	for rel, text := range s.docs {
+		docs[rel] = text
+	}
+	s.mu.Unlock()
+	for rel, text := range docs {
+		s.ix.updateContent(rel, text)
+	}
+	s.publishAll()
+}
+
+func (s *lspServer) publishAll() {
+	s.mu.Lock()
+	rels := sortedKeys(s.docs)
+	s.mu.Unlock()
+	for _, rel := range rels {
+		s.send(map[string]any{"jsonrpc": "2.0", "method": "textDocument/publishDiagnostics",
+			"params": map[string]any{"uri": pathToURI(filepath.Join(s.ix.absPath, rel)), "diagnostics": s.diagnostics(rel)}})
+	}
+}
+
+// pathToURI converts an absolute path to a file:// URI.
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// uriToPath converts a file:// URI to a path; other schemes return "".
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+// relPath returns the vault-relative path of a markdown document URI.
+func (s *lspServer) relPath(uri string) (string, bool) {
+	path := uriToPath(uri)
+	if path == "" || !strings.HasSuffix(strings.ToLower(path), ".md") || !isPathWithinVault(path, s.ix.absPath) {
+		return "", false
+	}
+	rel, err := filepath.Rel(s.ix.absPath, path)
+	if err != nil || isHiddenPath(s.ix.absPath, path) {
+		return "", false
+	}
+	return rel, true
+}
+
+func (s *lspServer) uri(rel string) string {
+	return pathToURI(filepath.Join(s.ix.absPath, rel))
+}
+
+// text returns a note's current text: the open buffer, the indexed
+// content, or the file on disk.
+func (s *lspServer) text(rel string) (string, bool) {
+	s.mu.Lock()
+	text, ok := s.docs[rel]
+	s.mu.Unlock()
+	if ok {
+		return text, true
+	}
+	if note := s.ix.note(rel); note != nil {
+		return note.Content, true
+	}
+	data, err := os.ReadFile(filepath.Join(s.ix.absPath, rel))
+	return string(data), err == nil
+}
+
+// utf16Len returns the length of s in UTF-16 code units.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// positionAt converts a byte offset in text to an LSP position.
+func positionAt(text string, offset int) lspPosition {
+	offset = min(max(offset, 0), len(text))
+	lineStart := strings.LastIndex(text[:offset], "\n") + 1
+	return lspPosition{Line: strings.Count(text[:offset], "\n"), Character: utf16Len(text[lineStart:offset])}
+}
+
+// offsetAt converts an LSP position to a byte offset in text, clamped to
+// the end of the line.
+func offsetAt(text string, pos lspPosition) int {
+	offset := 0
+	for i := 0; i < pos.Line; i++ {
+		next := strings.IndexByte(text[offset:], '\n')
+		if next == -1 {
+			return len(text)
+		}
+		offset += next + 1
+	}
+	units := 0
+	for offset < len(text) && text[offset] != '\n' && units < pos.Character {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		if r >= 0x10000 {
+			units += 2
+		} else {
+			units++
+		}
+		offset += size
+	}
+	return offset
+}
+
+func rangeOf(text string, start, end int) lspRange {
+	return lspRange{Start: positionAt(text, start), End: positionAt(text, end)}
+}
+
+// linkAt returns the wikilink containing byte offset in text.
+func linkAt(text string, offset int) (wikilink, bool) {
+	for _, l := range parseWikilinks(text) {
+		if offset >= l.Start && offset < l.End {
+			return l, true
+		}
+	}
+	return wikilink{}, false
+}
+
+// linkTargetStart returns the byte offset of a link's target text.
+func linkTargetStart(l wikilink) int {
+	if l.Embed {
+		return l.Start + 3
+	}
+	return l.Start + 2
+}
+
+// headingAt returns the heading on the line containing offset.
+func headingAt(text string, offset int) (noteHeading, int, int, bool) {
+	line := positionAt(text, offset).Line + 1
+	for _, h := range parseHeadings(text) {
+		if h.Line == line {
+			lineStart := offsetAt(text, lspPosition{Line: line - 1})
+			lineText := text[lineStart:]
+			if end := strings.IndexByte(lineText, '\n'); end != -1 {
+				lineText = lineText[:end]
+			}
+			start := strings.Index(lineText, h.Text)
+			if start == -1 {
+				return noteHeading{}, 0, 0, false
+			}
+			return h, lineStart + start, lineStart + start + len(h.Text), true
+		}
+	}
+	return noteHeading{}, 0, 0, false
+}
+
+// resolveLink returns the note a link in note fromRel points at. Links
+// with an empty target ([[#Heading]]) point at fromRel itself.
+func (s *lspServer) resolveLink(res *noteIndex, l wikilink, fromRel string) (string, bool) {
+	if l.Target == "" {
+		return fromRel, true
+	}
+	if vault.IsExternalLink(l.Target) {
+		return "", false
+	}
+	path, ok := res.resolve(l.Target)
+	if !ok {
+		return "", false
+	}
+	return filepath.FromSlash(path) + ".md", true
+}
+
+// fragmentLine returns the 1-based line a link fragment points at in text,
+// or 0 when the fragment is empty or not found.
+func fragmentLine(text, fragment string) int {
+	if fragment == "" {
+		return 0
+	}
+	name, isBlock := fragmentTarget(fragment)
+	if isBlock {
+		if b, ok := findBlock(parseBlocks(text), name); ok {
+			return b.Line
+		}
+		return 0
+	}
+	if h, ok := findHeading(parseHeadings(text), name); ok {
+		return h.Line
+	}
+	return 0
+}
+
+func (s *lspServer) diagnostics(rel string) []lspDiagnostic {
+	text, ok := s.text(rel)
+	if !ok {
+		return []lspDiagnostic{}
+	}
+	diags := []lspDiagnostic{}
+	add := func(start, end, severity int, code, msg string) {
+		diags = append(diags, lspDiagnostic{Range: rangeOf(text, start, end), Severity: severity,
+			Code: code, Source: "obsidian-cli", Message: msg})
+	}
+
+	res := s.ix.resolver()
+	for _, l := range parseWikilinks(text) {
+		if l.Target != "" && vault.IsExternalLink(l.Target) {
+			continue
+		}
+		if l.Target != "" && s.ix.isDead(l.Target) {
+			add(l.Start, l.End, lspSeverityWarning, "dead-link", fmt.Sprintf("Dead link: no note matches %q", l.Target))
+			continue
+		}
+		if l.Fragment == "" {
+			continue
+		}
+		targetRel, ok := s.resolveLink(res, l, rel)
+		if !ok {
+			continue // Asset or folder link
+		}
+		targetText, ok := s.text(targetRel)
+		if !ok || fragmentLine(targetText, l.Fragment) > 0 {
+			continue
+		}
+		name, isBlock := fragmentTarget(l.Fragment)
+		if isBlock {
+			add(l.Start, l.End, lspSeverityWarning, "missing-block", fmt.Sprintf("Block ^%s not found in %s", name, targetRel))
+		} else {
+			add(l.Start, l.End, lspSeverityWarning, "missing-heading", fmt.Sprintf("Heading %q not found in %s", name, targetRel))
+		}
+	}
+
+	diags = append(diags, frontmatterDiagnostics(text)...)
+	return diags
+}
+
+// frontmatterDiagnostics reports unterminated frontmatter, lines that
+// aren't YAML keys or list items, and duplicate keys.
+func frontmatterDiagnostics(text string) []lspDiagnostic {
+	var diags []lspDiagnostic
+	add := func(line, severity int, code, msg string) {
+		lineText := strings.Split(text, "\n")[line]
+		diags = append(diags, lspDiagnostic{
+			Range:    lspRange{Start: lspPosition{Line: line}, End: lspPosition{Line: line, Character: utf16Len(lineText)}},
+			Severity: severity, Code: code, Source: "obsidian-cli", Message: msg,
+		})
+	}
+
+	if !strings.HasPrefix(text, "---\n") && !strings.HasPrefix(text, "---\r\n") {
+		return nil
+	}
+	fm, _, ok := splitFrontmatter(text)
+	if !ok {
+		add(0, lspSeverityError, "invalid-frontmatter", "Frontmatter is not closed with ---")
+		return diags
+	}
+
+	seen := make(map[string]bool)
+	for i, line := range strings.Split(fm, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case line[0] == ' ' || line[0] == '\t' || strings.HasPrefix(trimmed, "- "):
+			// Continuation or list item
+		case frontmatterKeyRegex.MatchString(line):
+			key := strings.TrimSpace(frontmatterKeyRegex.FindStringSubmatch(line)[1])
+			if seen[key] {
+				add(i+1, lspSeverityWarning, "invalid-frontmatter", fmt.Sprintf("Duplicate frontmatter key %q", key))
+			}
+			seen[key] = true
+		default:
+			add(i+1, lspSeverityWarning, "invalid-frontmatter", "Invalid frontmatter line (expected \"key: value\")")
+		}
+	}
+	return diags
+}
+
+func (s *lspServer) completion(pos *lspDocumentPosition) any {
+	rel, ok := s.relPath(pos.TextDocument.URI)
+	if !ok {
+		return nil
+	}
+	text, _ := s.text(rel)
+	offset := offsetAt(text, pos.Position)
+	lineStart := strings.LastIndex(text[:offset], "\n") + 1
+	prefix := text[lineStart:offset]
+
+	open := strings.LastIndex(prefix, "[[")
+	if open == -1 {
+		return nil
+	}
+	inner := prefix[open+2:]
+	if strings.Contains(inner, "]]") || strings.Contains(inner, "|") {
+		return nil
+	}
+
+	// Close the link unless the editor already did
+	rest := text[offset:]
+	if end := strings.IndexByte(rest, '\n'); end != -1 {
+		rest = rest[:end]
+	}
+	closing := "]]"
+	if strings.HasPrefix(rest, "]]") {
+		closing = ""
+	}
+
+	editRange := func(start int) lspRange { return rangeOf(text, lineStart+start, offset) }
+	items := []lspCompletionItem{}
+	res := s.ix.resolver()
+
+	hash := strings.Index(inner, "#")
+	if hash == -1 {
+		r := editRange(open + 2)
+		for _, note := range s.ix.allNotes() {
+			path := strings.TrimSuffix(filepath.ToSlash(note.RelPath), ".md")
+			link := res.linkText(path)
+			items = append(items, lspCompletionItem{
+				Label: link, Kind: lspKindFile, Detail: note.RelPath, SortText: "0" + link,
+				TextEdit: lspTextEdit{Range: r, NewText: link + closing},
+			})
+			for _, alias := range note.Aliases {
+				items = append(items, lspCompletionItem{
+					Label: alias, Kind: lspKindReference, Detail: "alias of " + note.RelPath, SortText: "1" + alias,
+					TextEdit: lspTextEdit{Range: r, NewText: link + "|" + alias + closing},
+				})
+			}
+		}
+		return map[string]any{"isIncomplete": false, "items": items}
+	}
+
+	targetRel, ok := s.resolveLink(res, wikilink{Target: inner[:hash]}, rel)
+	if !ok {
+		return nil
+	}
+	targetText, ok := s.text(targetRel)
+	if !ok {
+		return nil
+	}
+
+	if frag := inner[hash+1:]; strings.HasPrefix(frag, "^") {
+		r := editRange(open + 2 + hash + 2)
+		for _, b := range parseBlocks(targetText) {
+			items = append(items, lspCompletionItem{
+				Label: "^" + b.ID, Kind: lspKindEnum, Detail: truncateRunes(b.Text, 60), FilterText: b.ID,
+				TextEdit: lspTextEdit{Range: r, NewText: b.ID + closing},
+			})
+		}
+	} else {
+		r := editRange(open + 2 + hash + 1)
+		for i, h := range parseHeadings(targetText) {
+			items = append(items, lspCompletionItem{
+				Label: h.Text, Kind: lspKindStruct, Detail: strings.Repeat("#", h.Level), SortText: fmt.Sprintf("%05d", i),
+				TextEdit: lspTextEdit{Range: r, NewText: h.Text + closing},
+			})
+		}
+	}
+	return map[string]any{"isIncomplete": false, "items": items}
+}
+
+func (s *lspServer) definition(pos *lspDocumentPosition) any {
+	rel, ok := s.relPath(pos.TextDocument.URI)
+	if !ok {
+		return nil
+	}
+	text, _ := s.text(rel)
+	l, ok := linkAt(text, offsetAt(text, pos.Position))
+	if !ok {
+		return nil
+	}
+	targetRel, ok := s.resolveLink(s.ix.resolver(), l, rel)
+	if !ok {
+		return nil
+	}
+	targetText, _ := s.text(targetRel)
+	line := max(fragmentLine(targetText, l.Fragment)-1, 0)
+	return lspLocation{URI: s.uri(targetRel), Range: lspRange{Start: lspPosition{Line: line}, End: lspPosition{Line: line}}}
+}
+
+// referenceTarget returns the note (and heading, if any) the cursor refers
+// to: a link's target, a heading in the document, or the document itself.
+func (s *lspServer) referenceTarget(rel, text string, offset int) (targetRel, heading string, ok bool) {
+	if l, found := linkAt(text, offset); found {
+		targetRel, ok = s.resolveLink(s.ix.resolver(), l, rel)
+		if name, isBlock := fragmentTarget(l.Fragment); l.Fragment != "" && !isBlock {
+			heading = name
+		}
+		return targetRel, heading, ok
+	}
+	if h, _, _, found := headingAt(text, offset); found {
+		return rel, h.Text, true
+	}
+	return rel, "", true
+}
+
+// linkRef is a link found in a note, with the note's current text.
+type linkRef struct {
+	rel  string
+	text string
+	link wikilink // Offsets into text
+}
+
+// linkRefs returns every link to targetRel, restricted to links to heading
+// when set.
+func (s *lspServer) linkRefs(targetRel, heading string) []linkRef {
+	var refs []linkRef
+	seenLines := make(map[string]bool)
+	matches := func(l wikilink) bool {
+		if heading == "" {
+			return true
+		}
+		name, isBlock := fragmentTarget(l.Fragment)
+		return l.Fragment != "" && !isBlock && strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(heading))
+	}
+
+	// Links from other notes, found by the backlinks logic
+	for _, bl := range s.ix.backlinks(targetRel) {
+		key := bl.SourceFile + ":" + strconv.Itoa(bl.Line)
+		if seenLines[key] {
+			continue
+		}
+		seenLines[key] = true
+		text, ok := s.text(bl.SourceFile)
+		if !ok {
+			continue
+		}
+		lineStart := offsetAt(text, lspPosition{Line: bl.Line - 1})
+		lineEnd := len(text)
+		if end := strings.IndexByte(text[lineStart:], '\n'); end != -1 {
+			lineEnd = lineStart + end
+		}
+		for _, l := range parseWikilinks(text[lineStart:lineEnd]) {
+			if strings.EqualFold(l.Target, bl.Target) && matches(l) {
+				l.Start += lineStart
+				l.End += lineStart
+				refs = append(refs, linkRef{rel: bl.SourceFile, text: text, link: l})
+			}
+		}
+	}
+
+	// Heading links within the note itself ([[#Heading]])
+	if heading != "" {
+		if text, ok := s.text(targetRel); ok {
+			for _, l := range parseWikilinks(text) {
+				if l.Target == "" && matches(l) {
+					refs = append(refs, linkRef{rel: targetRel, text: text, link: l})
+				}
+			}
+		}
+	}
+	return refs
+}
+
+func (s *lspServer) references(pos *lspDocumentPosition) any {
+	rel, ok := s.relPath(pos.TextDocument.URI)
+	if !ok {
+		return nil
+	}
+	text, _ := s.text(rel)
+	targetRel, heading, ok := s.referenceTarget(rel, text, offsetAt(text, pos.Position))
+	if !ok {
+		return []lspLocation{}
+	}
+	locations := []lspLocation{}
+	for _, ref := range s.linkRefs(targetRel, heading) {
+		locations = append(locations, lspLocation{URI: s.uri(ref.rel), Range: rangeOf(ref.text, ref.link.Start, ref.link.End)})
+	}
+	return locations
+}
+
+func (s *lspServer) hover(pos *lspDocumentPosition) any {
+	rel, ok := s.relPath(pos.TextDocument.URI)
+	if !ok {
+		return nil
+	}
+	text, _ := s.text(rel)
+	l, ok := linkAt(text, offsetAt(text, pos.Position))
+	if !ok {
+		return nil
+	}
+	r := rangeOf(text, l.Start, l.End)
+	hover := func(md string) any {
+		return map[string]any{"contents": map[string]string{"kind": "markdown", "value": md}, "range": r}
+	}
+
+	if l.Target != "" && s.ix.isDead(l.Target) {
+		return hover(fmt.Sprintf("**Dead link**: no note matches `%s`", l.Target))
+	}
+	targetRel, ok := s.resolveLink(s.ix.resolver(), l, rel)
+	if !ok {
+		return nil
+	}
+	targetText, ok := s.text(targetRel)
+	if !ok {
+		return nil
+	}
+	return hover(fmt.Sprintf("**%s**\n\n%s", targetRel, previewText(targetText, l.Fragment, lspHoverLines)))
+}
+
+// previewText returns up to maxLines of a note for a preview: the block or
+// heading section named by fragment, or else the body after frontmatter.
+func previewText(text, fragment string, maxLines int) string {
+	section, _, ok := noteSection(text, fragment)
+	if !ok {
+		section, _, _ = noteSection(text, "")
+	}
+	lines := strings.Split(section, "\n")
+	truncated := len(lines) > maxLines
+	lines = applyLimit(lines, maxLines)
+	preview := strings.TrimSpace(strings.Join(lines, "\n"))
+	if truncated {
+		preview += "\n\n…"
+	}
+	return preview
+}
+
+// renameTarget works out what is being renamed at offset: a note (from a
+// link's target) or a heading (from a link's fragment or a heading line).
+// Returns the byte range of the text being renamed in the document.
+func (s *lspServer) renameTarget(rel, text string, offset int) (targetRel, heading string, start, end int, ok bool) {
+	if l, found := linkAt(text, offset); found {
+		targetRel, ok = s.resolveLink(s.ix.resolver(), l, rel)
+		if !ok {
+			return "", "", 0, 0, false
+		}
+		targetStart := linkTargetStart(l)
+		fragStart := targetStart + len(l.Target)
+		name, isBlock := fragmentTarget(l.Fragment)
+		if l.Fragment != "" && !isBlock && (offset >= fragStart || l.Target == "") {
+			nameStart := fragStart + len(l.Fragment) - len(name)
+			return targetRel, name, nameStart, nameStart + len(name), true
+		}
+		if l.Target == "" {
+			return "", "", 0, 0, false
+		}
+		return targetRel, "", targetStart, fragStart, true
+	}
+	if h, hStart, hEnd, found := headingAt(text, offset); found {
+		return rel, h.Text, hStart, hEnd, true
+	}
+	return "", "", 0, 0, false
+}
+
+func (s *lspServer) prepareRename(pos *lspDocumentPosition) any {
+	rel, ok := s.relPath(pos.TextDocument.URI)
+	if !ok {
+		return nil
+	}
+	text, _ := s.text(rel)
+	_, _, start, end, ok := s.renameTarget(rel, text, offsetAt(text, pos.Position))
+	if !ok {
+		return nil
+	}
+	return map[string]any{"range": rangeOf(text, start, end), "placeholder": text[start:end]}
+}
+
+func (s *lspServer) rename(pos *lspDocumentPosition, newName string) (any, error) {
+	rel, ok := s.relPath(pos.TextDocument.URI)
+	if !ok {
+		return nil, fmt.Errorf("not a vault note: %s", pos.TextDocument.URI)
+	}
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return nil, fmt.Errorf("new name cannot be empty")
+	}
+	text, _ := s.text(rel)
+	targetRel, heading, _, _, ok := s.renameTarget(rel, text, offsetAt(text, pos.Position))
+	if !ok {
+		return nil, fmt.Errorf("place the cursor on a [[link]] or a heading to rename")
+	}
+	if heading != "" {
+		return s.renameHeading(targetRel, heading, newName)
+	}
+	return s.renameNote(targetRel, newName)
+}
+
+// renameNote builds a workspace edit that renames a note and rewrites its
+// backlinks the way the rename command does.
+func (s *lspServer) renameNote(targetRel, newName string) (any, error) {
+	if !s.canRename {
+		return nil, fmt.Errorf("the editor doesn't support renaming files; use: obsidian-cli rename")
+	}
+	oldName := strings.TrimSuffix(filepath.ToSlash(targetRel), ".md")
+	newName = strings.TrimSuffix(newName, ".md")
+	result, sourceFile, destFile, err := planRename(s.ix.absPath, oldName, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []any
+	for _, note := range s.ix.allNotes() {
+		if note.RelPath == result.SourceFile {
+			continue
+		}
+		text, ok := s.text(note.RelPath)
+		if !ok {
+			continue
+		}
+		var edits []lspTextEdit
+		for i, line := range strings.Split(text, "\n") {
+			if updated := computeNewLinkContent(line, oldName, newName); updated != line {
+				edits = append(edits, lspTextEdit{
+					Range:   lspRange{Start: lspPosition{Line: i}, End: lspPosition{Line: i, Character: utf16Len(line)}},
+					NewText: updated,
+				})
+			}
+		}
+		if len(edits) > 0 {
+			changes = append(changes, map[string]any{
+				"textDocument": map[string]any{"uri": s.uri(note.RelPath), "version": nil},
+				"edits":        edits,
+			})
+		}
+	}
+	changes = append(changes, map[string]any{"kind": "rename", "oldUri": pathToURI(sourceFile), "newUri": pathToURI(destFile)})
+	return map[string]any{"documentChanges": changes}, nil
+}
+
+// renameHeading builds a workspace edit that renames a heading and every
+// [[note#Heading]] link to it.
+func (s *lspServer) renameHeading(targetRel, heading, newName string) (any, error) {
+	if strings.ContainsAny(newName, "#^[]|\n") {
+		return nil, fmt.Errorf("heading can't contain # ^ [ ] | or line breaks")
+	}
+	text, ok := s.text(targetRel)
+	if !ok {
+		return nil, fmt.Errorf("cannot read %s", targetRel)
+	}
+	h, ok := findHeading(parseHeadings(text), heading)
+	if !ok {
+		return nil, fmt.Errorf("heading %q not found in %s", heading, targetRel)
+	}
+
+	changes := make(map[string][]lspTextEdit)
+	lineStart := offsetAt(text, lspPosition{Line: h.Line - 1})
+	if idx := strings.Index(text[lineStart:], h.Text); idx != -1 {
+		start := lineStart + idx
+		uri := s.uri(targetRel)
+		changes[uri] = append(changes[uri], lspTextEdit{Range: rangeOf(text, start, start+len(h.Text)), NewText: newName})
+	}
+
+	for _, ref := range s.linkRefs(targetRel, h.Text) {
+		name, _ := fragmentTarget(ref.link.Fragment)
+		fragEnd := linkTargetStart(ref.link) + len(ref.link.Target) + len(ref.link.Fragment)
+		uri := s.uri(ref.rel)
+		changes[uri] = append(changes[uri], lspTextEdit{Range: rangeOf(ref.text, fragEnd-len(name), fragEnd), NewText: newName})
+	}
+	return map[string]any{"changes": changes}, nil
+}
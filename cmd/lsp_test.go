@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLSPPositions tests UTF-16 position conversion round trips
+func TestLSPPositions(t *testing.T) {
+	text := "héllo\n😀 [[note]]\nend"
+
+	tests := []struct {
+		offset int
+		want   lspPosition
+	}{
+		{0, lspPosition{0, 0}},
+		{3, lspPosition{0, 2}},  // After "hé" (é is 2 bytes, 1 unit)
+		{7, lspPosition{1, 0}},  // Start of second line
+		{11, lspPosition{1, 2}}, // After 😀 (4 bytes, 2 units)
+		{len(text), lspPosition{2, 3}},
+	}
+	for _, tt := range tests {
+		if got := positionAt(text, tt.offset); got != tt.want {
+			t.Errorf("positionAt(%d) = %+v, want %+v", tt.offset, got, tt.want)
+		}
+		if got := offsetAt(text, tt.want); got != tt.offset {
+			t.Errorf("offsetAt(%+v) = %d, want %d", tt.want, got, tt.offset)
+		}
+	}
+
+	// Positions past the end of a line clamp to it
+	if got := offsetAt(text, lspPosition{0, 99}); got != 6 {
+		t.Errorf("offsetAt past end of line = %d, want 6", got)
+	}
+}
+
+// TestFrontmatterDiagnostics tests unclosed frontmatter, invalid lines and duplicate keys
+func TestFrontmatterDiagnostics(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		lines []int
+	}{
+		{"valid", "---\ntitle: a\ntags:\n  - x\n- y\n---\nbody", nil},
+		{"no frontmatter", "body\nbad line", nil},
+		{"unclosed", "---\ntitle: a\n", []int{0}},
+		{"invalid line", "---\ntitle: a\nbad line\n---\n", []int{2}},
+		{"duplicate key", "---\ntitle: a\ntitle: b\n---\n", []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := frontmatterDiagnostics(tt.text)
+			if len(diags) != len(tt.lines) {
+				t.Fatalf("got %d diagnostics (%+v), want %d", len(diags), diags, len(tt.lines))
+			}
+			for i, d := range diags {
+				if d.Range.Start.Line != tt.lines[i] {
+					t.Errorf("diagnostic %d on line %d, want %d", i, d.Range.Start.Line, tt.lines[i])
+				}
+			}
+		})
+	}
+}
+
+// TestLSPServer tests completion, definition, hover and both rename paths over
+// Content-Length framed messages
+func TestLSPServer(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"note.md": "# Note\n\n## Setup\n\ntext\n",
+		"a.md":    "See [[note#Setup]].\nThen [[note#\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldVault := vaultPath
+	vaultPath = ""
+	defer func() { vaultPath = oldVault }()
+
+	aURI := pathToURI(filepath.Join(dir, "a.md"))
+	noteURI := pathToURI(filepath.Join(dir, "note.md"))
+	at := func(line, char int) map[string]any {
+		return map[string]any{"textDocument": map[string]any{"uri": aURI}, "position": map[string]any{"line": line, "character": char}}
+	}
+	withName := func(params map[string]any, name string) map[string]any {
+		params["newName"] = name
+		return params
+	}
+
+	run := func(requests ...map[string]any) map[string]map[string]any {
+		var in bytes.Buffer
+		for _, req := range requests {
+			req["jsonrpc"] = "2.0"
+			body, _ := json.Marshal(req)
+			fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(body), body)
+		}
+		var out bytes.Buffer
+		s := &lspServer{docs: make(map[string]string), w: &out}
+		defer func() {
+			if s.cancelWatch != nil {
+				s.cancelWatch()
+			}
+		}()
+		if err := s.serve(&in); err != nil {
+			t.Fatal(err)
+		}
+
+		byID := make(map[string]map[string]any)
+		r := bufio.NewReader(&out)
+		for {
+			body, err := readLSPMessage(r)
+			if err != nil {
+				break
+			}
+			var resp map[string]any
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatalf("invalid response %q: %v", body, err)
+			}
+			if id, ok := resp["id"]; ok {
+				byID[fmt.Sprint(id)] = resp
+			}
+		}
+		return byID
+	}
+	initialize := func(canRename bool) map[string]any {
+		params := map[string]any{"rootUri": pathToURI(dir)}
+		if canRename {
+			params["capabilities"] = map[string]any{"workspace": map[string]any{"workspaceEdit": map[string]any{
+				"documentChanges": true, "resourceOperations": []string{"create", "rename"}}}}
+		}
+		return map[string]any{"id": 1, "method": "initialize", "params": params}
+	}
+
+	resps := run(
+		initialize(true),
+		map[string]any{"id": 2, "method": "textDocument/completion", "params": at(1, 12)},
+		map[string]any{"id": 3, "method": "textDocument/definition", "params": at(0, 7)},
+		map[string]any{"id": 6, "method": "textDocument/hover", "params": at(0, 7)},
+		map[string]any{"id": 4, "method": "textDocument/rename", "params": withName(at(0, 12), "Install")},
+		map[string]any{"id": 5, "method": "textDocument/rename", "params": withName(at(0, 7), "renamed")},
+	)
+
+	var labels []string
+	for _, item := range resps["2"]["result"].(map[string]any)["items"].([]any) {
+		labels = append(labels, item.(map[string]any)["label"].(string))
+	}
+	if got := strings.Join(labels, ","); got != "Note,Setup" {
+		t.Errorf("completion after [[note# = %s, want Note,Setup", got)
+	}
+
+	def := resps["3"]["result"].(map[string]any)
+	if line := def["range"].(map[string]any)["start"].(map[string]any)["line"]; def["uri"] != noteURI || line != float64(2) {
+		t.Errorf("definition = %v line %v, want %s line 2", def["uri"], line, noteURI)
+	}
+
+	hover := resps["6"]["result"].(map[string]any)["contents"].(map[string]any)["value"].(string)
+	if !strings.Contains(hover, "note.md") || !strings.Contains(hover, "text") {
+		t.Errorf("hover = %q, want a preview of the Setup section of note.md", hover)
+	}
+
+	// Heading renames edit the heading and its links in place
+	changes := resps["4"]["result"].(map[string]any)["changes"].(map[string]any)
+	for _, uri := range []string{aURI, noteURI} {
+		edits, _ := changes[uri].([]any)
+		if len(edits) != 1 || edits[0].(map[string]any)["newText"] != "Install" {
+			t.Errorf("heading rename edits for %s = %v, want one edit to Install", uri, edits)
+		}
+	}
+
+	// Note renames edit backlinks and rename the file
+	docChanges := resps["5"]["result"].(map[string]any)["documentChanges"].([]any)
+	last := docChanges[len(docChanges)-1].(map[string]any)
+	if len(docChanges) != 2 || last["kind"] != "rename" || last["newUri"] != pathToURI(filepath.Join(dir, "renamed.md")) {
+		t.Errorf("note rename = %v, want a backlink edit then a rename to renamed.md", docChanges)
+	}
+
+	// Without file rename support, note renames are refused
+	resps = run(initialize(false), map[string]any{"id": 2, "method": "textDocument/rename", "params": withName(at(0, 7), "renamed")})
+	if resps["2"]["error"] == nil {
+		t.Errorf("note rename without rename support = %v, want an error", resps["2"]["result"])
+	}
+}
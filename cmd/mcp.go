@@ -0,0 +1,610 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mcpAllowWrite  bool
+	mcpPatternsDir string
+)
+
+// Protocol versions the MCP server speaks, newest first
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// Default number of results returned by list tools
+const mcpDefaultLimit = 50
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol server over stdio",
+	Long: `Speaks the Model Context Protocol (MCP) over stdin/stdout so an AI
+assistant can use the vault and pattern store directly.
+
+Read tools (always available):
+  search_notes           Full-text search across notes
+  read_note              Note content, frontmatter, tags and links
+  list_backlinks         Notes linking to a note
+  list_tags              Tag counts, or the notes with a tag
+  find_similar_patterns  Patterns similar to a description (logs a surfacing event)
+  log_user_action        Record accept/reject/ignore/partial/defer for a surfacing event
+  log_outcome            Record success/failure/partial/unknown for a surfacing event
+
+Write tools (only with --allow-write; journaled, revert with: obsidian-cli undo):
+  create_note            Create a new note
+  append_note            Append text to a note
+  rename_note            Rename a note and update backlinks
+
+Vault tools are only offered when --vault is set.
+
+Example client configuration:
+  {"command": "obsidian-cli", "args": ["mcp", "--vault", "/path/to/vault"]}`,
+	RunE: runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+	mcpCmd.Flags().BoolVar(&mcpAllowWrite, "allow-write", false, "Offer tools that create, append to and rename notes")
+
+	defaultPatternsDir := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		defaultPatternsDir = filepath.Join(home, ".claude", "patterns")
+	}
+	mcpCmd.Flags().StringVar(&mcpPatternsDir, "patterns-dir", defaultPatternsDir, "Path to patterns directory")
+}
+
+// mcpTool is a tool offered to the client.
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+
+	call func(args json.RawMessage) (any, error)
+}
+
+// mcpServer handles MCP requests for one client.
+type mcpServer struct {
+	ix          *vaultIndex // nil without --vault
+	patternsDir string
+	allowWrite  bool
+	tools       []*mcpTool
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	s := &mcpServer{patternsDir: mcpPatternsDir, allowWrite: mcpAllowWrite}
+
+	if vaultPath != "" {
+		if err := RequireVault(); err != nil {
+			return err
+		}
+		absPath, err := filepath.Abs(vaultPath)
+		if err != nil {
+			return fmt.Errorf("invalid vault path: %w", err)
+		}
+		if s.ix, err = newVaultIndex(absPath); err != nil {
+			return err
+		}
+
+		// Keep the index warm; stdout belongs to the protocol, so watch
+		// errors go to stderr
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			if err := watchVault(ctx, s.ix, nil, nil); err != nil {
+				fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+			}
+		}()
+	}
+	s.registerTools()
+
+	return s.serve(cmd.InOrStdin(), cmd.OutOrStdout())
+}
+
+// serve reads newline-delimited JSON-RPC messages from r until EOF and
+// writes responses to w.
+func (s *mcpServer) serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxServeBody)
+	enc := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			if err := enc.Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &rpcError{rpcParseError, "parse error: " + err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(req.ID) == 0 {
+			continue // Notifications (initialized, cancelled) need no response
+		}
+
+		resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+		resp.Result, resp.Error = s.handle(&req)
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *mcpServer) handle(req *rpcRequest) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := mcpProtocolVersions[0]
+		for _, v := range mcpProtocolVersions {
+			if v == params.ProtocolVersion {
+				version = v
+			}
+		}
+		serverVersion := rootCmd.Version
+		if serverVersion == "" {
+			serverVersion = "dev"
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "obsidian-cli", "version": serverVersion},
+		}, nil
+
+	case "ping":
+		return map[string]any{}, nil
+
+	case "tools/list":
+		return map[string]any{"tools": s.tools}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{rpcInvalidParams, "invalid params: " + err.Error()}
+		}
+		for _, tool := range s.tools {
+			if tool.Name == params.Name {
+				return s.callTool(tool, params.Arguments), nil
+			}
+		}
+		return nil, &rpcError{rpcInvalidParams, "unknown tool: " + params.Name}
+
+	case "":
+		return nil, &rpcError{rpcInvalidRequest, "missing method"}
+	}
+	return nil, &rpcError{rpcMethodNotFound, "method not found: " + req.Method}
+}
+
+// callTool runs a tool. Tool failures are reported in the result (isError)
+// so the assistant can see and react to them.
+func (s *mcpServer) callTool(tool *mcpTool, args json.RawMessage) map[string]any {
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	v, err := tool.call(args)
+	if err != nil {
+		return map[string]any{
+			"content": []map[string]string{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+	text, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		text = []byte(err.Error())
+	}
+	return map[string]any{
+		"content": []map[string]string{{"type": "text", "text": string(text)}},
+		"isError": false,
+	}
+}
+
+// mcpSchema builds an object input schema from property definitions.
+func mcpSchema(props map[string]any, required ...string) map[string]any {
+	schema := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func mcpString(desc string) map[string]any {
+	return map[string]any{"type": "string", "description": desc}
+}
+
+func mcpBool(desc string) map[string]any {
+	return map[string]any{"type": "boolean", "description": desc}
+}
+
+func mcpInteger(desc string) map[string]any {
+	return map[string]any{"type": "integer", "description": desc}
+}
+
+// decodeArgs unmarshals tool arguments.
+func decodeArgs(args json.RawMessage, v any) error {
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func (s *mcpServer) registerTools() {
+	if s.ix != nil {
+		s.tools = append(s.tools,
+			&mcpTool{
+				Name:        "search_notes",
+				Description: "Search the text of all notes in the vault. Returns matching lines with file and line number.",
+				InputSchema: mcpSchema(map[string]any{
+					"query":          mcpString("Text to search for (literal unless regex is true)"),
+					"regex":          mcpBool("Treat query as a regular expression"),
+					"case_sensitive": mcpBool("Match case"),
+					"folder":         mcpString("Only search notes under this folder"),
+					"limit":          mcpInteger(fmt.Sprintf("Maximum matches (default %d)", mcpDefaultLimit)),
+				}, "query"),
+				call: s.searchNotes,
+			},
+			&mcpTool{
+				Name:        "read_note",
+				Description: "Read a note by name or vault-relative path. Returns content, frontmatter, tags, aliases and links.",
+				InputSchema: mcpSchema(map[string]any{
+					"note": mcpString(`Note name ("my-note") or path ("folder/my-note")`),
+				}, "note"),
+				call: s.readNote,
+			},
+			&mcpTool{
+				Name:        "list_backlinks",
+				Description: "List the notes that link to a note, with the linking line.",
+				InputSchema: mcpSchema(map[string]any{
+					"note": mcpString(`Note name ("my-note") or path ("folder/my-note")`),
+				}, "note"),
+				call: s.listBacklinks,
+			},
+			&mcpTool{
+				Name:        "list_tags",
+				Description: "List tags with note counts, most used first. With tag set, list the notes using that tag (including nested tags).",
+				InputSchema: mcpSchema(map[string]any{
+					"tag":   mcpString("Tag to list notes for, with or without #"),
+					"limit": mcpInteger(fmt.Sprintf("Maximum results (default %d)", mcpDefaultLimit)),
+				}),
+				call: s.listTags,
+			},
+		)
+	}
+
+	s.tools = append(s.tools,
+		&mcpTool{
+			Name: "find_similar_patterns",
+			Description: "Find stored patterns similar to a description of the current task. Logs a surfacing event; " +
+				"report what happened with log_user_action and log_outcome using the returned event_id.",
+			InputSchema: mcpSchema(map[string]any{
+				"query":  mcpString("Description of the situation or task"),
+				"domain": mcpString("Only patterns in this domain (workflow, architecture, security, discovery, career)"),
+				"limit":  mcpInteger("Maximum patterns (default 5)"),
+			}, "query"),
+			call: s.findSimilarPatterns,
+		},
+		&mcpTool{
+			Name:        "log_user_action",
+			Description: "Record how the user responded to surfaced patterns.",
+			InputSchema: mcpSchema(map[string]any{
+				"action":   map[string]any{"type": "string", "enum": sortedKeys(validUserActions)},
+				"event_id": mcpString(`Surfacing event ID (default "latest")`),
+				"notes":    mcpString("Optional notes"),
+			}, "action"),
+			call: s.logUserAction,
+		},
+		&mcpTool{
+			Name:        "log_outcome",
+			Description: "Record the outcome of acting on surfaced patterns.",
+			InputSchema: mcpSchema(map[string]any{
+				"outcome":  map[string]any{"type": "string", "enum": sortedKeys(validOutcomes)},
+				"event_id": mcpString(`Surfacing event ID (default "latest")`),
+				"notes":    mcpString("Optional notes"),
+			}, "outcome"),
+			call: s.logOutcome,
+		},
+	)
+
+	if s.ix == nil || !s.allowWrite {
+		return
+	}
+	s.tools = append(s.tools,
+		&mcpTool{
+			Name:        "create_note",
+			Description: "Create a new note. Fails if a file already exists at the path.",
+			InputSchema: mcpSchema(map[string]any{
+				"path":    mcpString(`Vault-relative path, e.g. "folder/My Note" (.md is added)`),
+				"content": mcpString("Markdown content"),
+			}, "path", "content"),
+			call: s.createNote,
+		},
+		&mcpTool{
+			Name:        "append_note",
+			Description: "Append text to the end of an existing note.",
+			InputSchema: mcpSchema(map[string]any{
+				"note":    mcpString(`Note name ("my-note") or path ("folder/my-note")`),
+				"content": mcpString("Markdown to append"),
+			}, "note", "content"),
+			call: s.appendNote,
+		},
+		&mcpTool{
+			Name:        "rename_note",
+			Description: "Rename a note and update every wikilink pointing to it. Use dry_run to preview the changes.",
+			InputSchema: mcpSchema(map[string]any{
+				"from":    mcpString("Current note name or path"),
+				"to":      mcpString("New name, or a path from the vault root"),
+				"dry_run": mcpBool("Only report the changes"),
+			}, "from", "to"),
+			call: s.renameNote,
+		},
+	)
+}
+
+// mcpLimit returns limit, or def when unset.
+func mcpLimit(limit, def int) int {
+	if limit <= 0 {
+		return def
+	}
+	return limit
+}
+
+func (s *mcpServer) searchNotes(args json.RawMessage) (any, error) {
+	var in struct {
+		Query         string `json:"query"`
+		Regex         bool   `json:"regex"`
+		CaseSensitive bool   `json:"case_sensitive"`
+		Folder        string `json:"folder"`
+		Limit         int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	if in.Query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if in.Folder != "" && !isPathWithinVault(filepath.Join(s.ix.absPath, in.Folder), s.ix.absPath) {
+		return nil, fmt.Errorf("folder path escapes vault boundary: %s", in.Folder)
+	}
+
+	patternStr := in.Query
+	if !in.Regex {
+		patternStr = regexp.QuoteMeta(in.Query)
+	}
+	if !in.CaseSensitive {
+		patternStr = "(?i)" + patternStr
+	}
+	pattern, err := regexp.Compile(patternStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	matches := s.ix.search(pattern, in.Folder, 0)
+	total := len(matches)
+	matches = applyLimit(matches, mcpLimit(in.Limit, mcpDefaultLimit))
+	if matches == nil {
+		matches = []SearchMatch{}
+	}
+	return map[string]any{"query": in.Query, "total": total, "matches": matches}, nil
+}
+
+func (s *mcpServer) readNote(args json.RawMessage) (any, error) {
+	var in struct {
+		Note string `json:"note"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	note, err := s.ix.find(in.Note)
+	if err != nil {
+		return nil, err
+	}
+	return newNoteData(note), nil
+}
+
+func (s *mcpServer) listBacklinks(args json.RawMessage) (any, error) {
+	var in struct {
+		Note string `json:"note"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	note, err := s.ix.find(in.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"note": note.RelPath, "backlinks": s.ix.backlinkResults(note.RelPath)}, nil
+}
+
+func (s *mcpServer) listTags(args json.RawMessage) (any, error) {
+	var in struct {
+		Tag   string `json:"tag"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	limit := mcpLimit(in.Limit, mcpDefaultLimit)
+
+	if in.Tag != "" {
+		files := s.ix.notesWithTag(in.Tag)
+		return TagInfo{Name: strings.ToLower(strings.TrimPrefix(in.Tag, "#")), Count: len(files), Files: applyLimit(files, limit)}, nil
+	}
+	tags := s.ix.tagList()
+	return map[string]any{"total": len(tags), "tags": applyLimit(tags, limit)}, nil
+}
+
+func (s *mcpServer) findSimilarPatterns(args json.RawMessage) (any, error) {
+	var in struct {
+		Query  string `json:"query"`
+		Domain string `json:"domain"`
+		Limit  int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	if in.Query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if s.patternsDir == "" {
+		return nil, fmt.Errorf("patterns directory not specified (use --patterns-dir)")
+	}
+	if err := validatePatternsDir(s.patternsDir); err != nil {
+		return nil, err
+	}
+
+	patterns, err := loadAllPatterns(s.patternsDir)
+	if err != nil {
+		return nil, err
+	}
+	patterns = filterDeprecated(patterns, s.patternsDir)
+	patterns = applyStalenessDecay(patterns, true)
+	if in.Domain != "" {
+		patterns = filterByDomain(patterns, in.Domain)
+	}
+	limit := mcpLimit(in.Limit, 5)
+	patterns = findSimilar(patterns, in.Query, limit)
+	patterns = applyLimit(filterByEffectiveConfidence(patterns, 0.3), limit)
+	if patterns == nil {
+		patterns = []Pattern{}
+	}
+
+	result := map[string]any{"patterns": patterns}
+	if len(patterns) > 0 {
+		ids := make([]string, 0, len(patterns))
+		for _, p := range patterns {
+			if p.ID != "" {
+				ids = append(ids, p.ID)
+			}
+		}
+		eventID, err := logSurfacingEvent(s.patternsDir, ids, in.Query, "mcp")
+		if err != nil {
+			return nil, fmt.Errorf("failed to log surfacing event: %w", err)
+		}
+		result["event_id"] = eventID
+	}
+	return result, nil
+}
+
+func (s *mcpServer) logUserAction(args json.RawMessage) (any, error) {
+	var in struct {
+		Action  string `json:"action"`
+		EventID string `json:"event_id"`
+		Notes   string `json:"notes"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	eventID, err := s.patternEventID(in.EventID)
+	if err != nil {
+		return nil, err
+	}
+	eventID, err = logUserAction(s.patternsDir, eventID, in.Action, in.Notes)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"event_id": eventID, "user_action": in.Action}, nil
+}
+
+func (s *mcpServer) logOutcome(args json.RawMessage) (any, error) {
+	var in struct {
+		Outcome string `json:"outcome"`
+		EventID string `json:"event_id"`
+		Notes   string `json:"notes"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	eventID, err := s.patternEventID(in.EventID)
+	if err != nil {
+		return nil, err
+	}
+	eventID, err = logOutcome(s.patternsDir, eventID, in.Outcome, in.Notes)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"event_id": eventID, "outcome": in.Outcome}, nil
+}
+
+// patternEventID checks the patterns directory and returns the event to
+// log on, the latest one when id is empty. Like the patterns command, IDs
+// can't contain path separators.
+func (s *mcpServer) patternEventID(id string) (string, error) {
+	if s.patternsDir == "" {
+		return "", fmt.Errorf("patterns directory not specified (use --patterns-dir)")
+	}
+	if err := validatePatternsDir(s.patternsDir); err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(id, "/\\") {
+		return "", fmt.Errorf("invalid event ID: cannot contain path separators")
+	}
+	if id == "" {
+		return "latest", nil
+	}
+	return id, nil
+}
+
+func (s *mcpServer) createNote(args json.RawMessage) (any, error) {
+	var in struct {
+		Path    string `json:"path"`
+		Content string `json:"content"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	mustNotExist := ""
+	return writeVaultNote(s.ix, in.Path, in.Content, &mustNotExist)
+}
+
+func (s *mcpServer) appendNote(args json.RawMessage) (any, error) {
+	var in struct {
+		Note    string `json:"note"`
+		Content string `json:"content"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	note, err := s.ix.find(in.Note)
+	if err != nil {
+		return nil, err
+	}
+	content := note.Content
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += in.Content
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return writeVaultNote(s.ix, note.RelPath, content, &note.Hash)
+}
+
+func (s *mcpServer) renameNote(args json.RawMessage) (any, error) {
+	var in struct {
+		From   string `json:"from"`
+		To     string `json:"to"`
+		DryRun bool   `json:"dry_run"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	return renameVaultNote(s.ix, in.From, in.To, in.DryRun)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMCPServer tests the JSON-RPC handshake, tool gating and tool calls
+func TestMCPServer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("hello world\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ix, err := newVaultIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(s *mcpServer, lines ...string) map[string]map[string]any {
+		s.registerTools()
+		var out bytes.Buffer
+		if err := s.serve(strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+			t.Fatal(err)
+		}
+		byID := make(map[string]map[string]any)
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			var resp map[string]any
+			if err := json.Unmarshal([]byte(line), &resp); err != nil {
+				t.Fatalf("invalid response %q: %v", line, err)
+			}
+			id, _ := json.Marshal(resp["id"])
+			byID[string(id)] = resp
+		}
+		return byID
+	}
+
+	toolNames := func(resp map[string]any) []string {
+		var names []string
+		for _, tool := range resp["result"].(map[string]any)["tools"].([]any) {
+			names = append(names, tool.(map[string]any)["name"].(string))
+		}
+		return names
+	}
+
+	resps := run(&mcpServer{ix: ix},
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"search_notes","arguments":{"query":"WORLD"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"read_note","arguments":{"note":"missing"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"nope"}`,
+	)
+	if len(resps) != 5 {
+		t.Errorf("got %d responses, want 5 (notifications get none)", len(resps))
+	}
+	if v := resps["1"]["result"].(map[string]any)["protocolVersion"]; v != "2024-11-05" {
+		t.Errorf("protocolVersion = %v, want 2024-11-05", v)
+	}
+	if names := strings.Join(toolNames(resps["2"]), ","); strings.Contains(names, "create_note") {
+		t.Errorf("write tools offered without --allow-write: %s", names)
+	}
+	if text := resps["3"]["result"].(map[string]any)["content"].([]any)[0].(map[string]any)["text"].(string); !strings.Contains(text, `"a.md"`) {
+		t.Errorf("search_notes result missing a.md: %s", text)
+	}
+	if isErr := resps["4"]["result"].(map[string]any)["isError"]; isErr != true {
+		t.Errorf("read_note of missing note: isError = %v, want true", isErr)
+	}
+	if code := resps["5"]["error"].(map[string]any)["code"]; code != float64(rpcMethodNotFound) {
+		t.Errorf("unknown method code = %v, want %d", code, rpcMethodNotFound)
+	}
+
+	resps = run(&mcpServer{ix: ix, allowWrite: true}, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	if names := strings.Join(toolNames(resps["1"]), ","); !strings.Contains(names, "create_note") {
+		t.Errorf("write tools missing with --allow-write: %s", names)
+	}
+}
+
+// TestMCPPatternEventID tests that actions and outcomes aren't logged on
+// another event when the event ID is invalid
+func TestMCPPatternEventID(t *testing.T) {
+	s := &mcpServer{patternsDir: t.TempDir()}
+	for _, args := range []string{`{"action":"accepted","event_id":"../x"}`, `{"action":"accepted","event_id":"a\\b"}`} {
+		if _, err := s.logUserAction(json.RawMessage(args)); err == nil || !strings.Contains(err.Error(), "path separators") {
+			t.Errorf("logUserAction(%s) error = %v, want invalid event ID", args, err)
+		}
+	}
+	if _, err := s.logOutcome(json.RawMessage(`{"outcome":"success","event_id":"x/y"}`)); err == nil || !strings.Contains(err.Error(), "path separators") {
+		t.Errorf("logOutcome error = %v, want invalid event ID", err)
+	}
+	if _, err := (&mcpServer{}).logOutcome(json.RawMessage(`{"outcome":"success"}`)); err == nil {
+		t.Error("logOutcome without a patterns directory succeeded")
+	}
+}
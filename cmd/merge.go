@@ -0,0 +1,551 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeInto    string
+	mergeLevel   int
+	mergeArchive string
+	mergeDryRun  bool
+	mergeFormat  string
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <note>... --into <target>",
+	Short: "Merge notes into one, retargeting backlinks",
+	Long: `Merges notes into a target note, each under a heading named after it,
+and removes them. The reverse of split.
+
+  - The target is created when it doesn't exist: in the folder given in
+    its name, else next to the first note
+  - Headings of merged notes move below their section's heading, and a
+    first heading repeating the note's name is dropped
+  - Frontmatter is merged into the target's: lists (tags, aliases) are
+    combined, and a scalar with different values keeps the first one and
+    is reported as a conflict
+  - Links to a merged note anywhere in the vault are retargeted:
+    [[a]] -> [[target#a]], [[a#Heading]] -> [[target#Heading]], and
+    markdown links the same way: [x](a.md) -> [x](target.md#a)
+  - Merged notes are deleted, or moved to --archive
+
+Changes are journaled (revert with "undo").
+
+Examples:
+  obsidian-cli merge "Idea 1" "Idea 2" --into Ideas --vault ~/notes --dry-run
+  obsidian-cli merge Mon Tue Wed --into "Weekly/W42" --archive Archive --vault ~/notes`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMerge,
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+	mergeCmd.Flags().StringVar(&mergeInto, "into", "", "Note to merge into, created if missing (required)")
+	mergeCmd.Flags().IntVar(&mergeLevel, "level", 2, "Heading level of the merged notes' sections (1-5)")
+	mergeCmd.Flags().StringVar(&mergeArchive, "archive", "", "Move merged notes to this folder instead of deleting them")
+	mergeCmd.Flags().BoolVar(&mergeDryRun, "dry-run", false, "Preview changes without modifying files")
+	mergeCmd.Flags().StringVar(&mergeFormat, "format", "text", "Output format: text, json")
+	_ = mergeCmd.MarkFlagRequired("into")
+}
+
+// MergeConflict is a scalar frontmatter field with different values.
+type MergeConflict struct {
+	Key     string `json:"key"`
+	Kept    string `json:"kept"`
+	Dropped string `json:"dropped"`
+	Source  string `json:"source"` // Note the dropped value came from
+}
+
+// MergeResult is the result of the merge command.
+type MergeResult struct {
+	Target        string            `json:"target"`
+	Created       bool              `json:"created"`
+	Sources       []string          `json:"sources"`
+	Archived      map[string]string `json:"archived,omitempty"` // Source -> archive path
+	Conflicts     []MergeConflict   `json:"conflicts"`
+	Backlinks     []LinkConvertFile `json:"backlinks"`
+	LinksUpdated  int               `json:"links_updated"`
+	FilesModified int               `json:"files_modified"`
+	Executed      bool              `json:"executed"`
+	JournalID     string            `json:"journal_id,omitempty"`
+}
+
+// noteMerger plans the merge of notes into a target.
+type noteMerger struct {
+	target   string            // Slash-separated without .md
+	sections map[string]string // Source (slash-separated without .md) -> section heading
+	index    *noteIndex        // Vault before the merge
+	newIndex *noteIndex        // Vault after the merge
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	if mergeLevel < 1 || mergeLevel > 5 {
+		return fmt.Errorf("invalid --level %d (expected 1-5)", mergeLevel)
+	}
+	start := time.Now()
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	notes, err := loadNotes(absPath)
+	if err != nil {
+		return err
+	}
+	byPath := make(map[string]*noteFile, len(notes))
+	for _, n := range notes {
+		byPath[n.Path] = n
+	}
+
+	var sources []*noteFile
+	seen := make(map[string]bool)
+	for _, arg := range args {
+		p, err := findNoteFile(absPath, arg)
+		if err != nil {
+			return err
+		}
+		if !isPathWithinVault(p, absPath) || byPath[p] == nil {
+			return fmt.Errorf("note path escapes vault boundary: %s", arg)
+		}
+		if !seen[p] {
+			seen[p] = true
+			sources = append(sources, byPath[p])
+		}
+	}
+
+	target, created, err := mergeTarget(absPath, notes, sources[0], mergeInto)
+	if err != nil {
+		return err
+	}
+	if seen[filepath.Join(absPath, filepath.FromSlash(target)+".md")] {
+		return fmt.Errorf("cannot merge %s into itself", target+".md")
+	}
+	var targetNote *noteFile
+	if !created {
+		targetNote = byPath[filepath.Join(absPath, filepath.FromSlash(target)+".md")]
+	}
+
+	archived := make(map[string]string)
+	if mergeArchive != "" {
+		folder := strings.Trim(filepath.ToSlash(filepath.Clean(mergeArchive)), "/")
+		for _, src := range sources {
+			to := path.Join(folder, path.Base(filepath.ToSlash(src.RelPath)))
+			full := filepath.Join(absPath, filepath.FromSlash(to))
+			if !isPathWithinVault(full, absPath) {
+				return fmt.Errorf("archive path escapes vault boundary: %s", mergeArchive)
+			}
+			if _, err := os.Lstat(full); err == nil {
+				return fmt.Errorf("archived note already exists: %s", to)
+			}
+			archived[filepath.ToSlash(src.RelPath)] = to
+		}
+	}
+
+	m := newNoteMerger(notes, sources, target, created, archived)
+	content, conflicts := m.merge(targetNote, sources, mergeLevel)
+
+	result := &MergeResult{
+		Target:    target + ".md",
+		Created:   created,
+		Conflicts: conflicts,
+		Backlinks: []LinkConvertFile{},
+		Executed:  !mergeDryRun,
+	}
+	if len(archived) > 0 {
+		result.Archived = archived
+	}
+	oldContent := make(map[string]string)
+	changed := make(map[string]string)
+	for _, n := range notes {
+		if seen[n.Path] || n == targetNote {
+			continue
+		}
+		body, count := m.rewriteLinks(filepath.ToSlash(n.RelPath), n.Body, false)
+		if count == 0 {
+			continue
+		}
+		oldContent[n.RelPath] = n.Content
+		changed[n.RelPath] = n.Content[:len(n.Content)-len(n.Body)] + body
+		result.Backlinks = append(result.Backlinks, LinkConvertFile{File: filepath.ToSlash(n.RelPath), Links: count})
+		result.LinksUpdated += count
+	}
+	for _, src := range sources {
+		result.Sources = append(result.Sources, filepath.ToSlash(src.RelPath))
+	}
+	result.FilesModified = len(changed) + 1
+
+	if mergeFormat == "json" {
+		if !mergeDryRun {
+			if result.JournalID, err = writeMerge(absPath, result, content, changed); err != nil {
+				return err
+			}
+		}
+		return encodeJSON(cmd, result)
+	}
+
+	printMerge(result, oldContent, changed, time.Since(start))
+	if mergeDryRun {
+		fmt.Printf("  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
+		return nil
+	}
+	journalID, err := writeMerge(absPath, result, content, changed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s Merged %d notes into %s, updated %d links in %d files\n", colors.Green("✓"), len(sources), result.Target, result.LinksUpdated, len(changed))
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n\n", colors.Dim("i"), journalID)
+	return nil
+}
+
+// mergeTarget resolves the target note. A missing target goes to the
+// folder in its name, else the folder of the first source. Returns its
+// path (slash-separated without .md).
+func mergeTarget(absPath string, notes []*noteFile, first *noteFile, name string) (target string, created bool, err error) {
+	name = strings.TrimSuffix(filepath.ToSlash(name), ".md")
+	ix := newNoteIndexFromNotes(notes)
+	if !strings.Contains(name, "/") && ix.ambiguous(name) {
+		return "", false, fmt.Errorf("ambiguous note name %q (use full path to disambiguate)", name)
+	}
+	if p, ok := ix.resolve(name); ok {
+		return p, false, nil
+	}
+
+	target = strings.TrimPrefix(path.Clean(name), "/")
+	if !strings.Contains(name, "/") {
+		if dir := path.Dir(filepath.ToSlash(first.RelPath)); dir != "." {
+			target = dir + "/" + target
+		}
+	}
+	if pathBase(target) == "" || strings.ContainsAny(target, invalidStubChars) {
+		return "", false, fmt.Errorf("invalid note name: %s", name)
+	}
+	if !isPathWithinVault(filepath.Join(absPath, filepath.FromSlash(target)+".md"), absPath) {
+		return "", false, fmt.Errorf("target path escapes vault boundary: %s", name)
+	}
+	return target, true, nil
+}
+
+// newNoteMerger names the sections of the sources and indexes the vault
+// as it will be after the merge.
+func newNoteMerger(notes, sources []*noteFile, target string, created bool, archived map[string]string) *noteMerger {
+	m := &noteMerger{target: target, sections: make(map[string]string), index: newNoteIndexFromNotes(notes)}
+	names := make(map[string]int)
+	for _, src := range sources {
+		names[strings.ToLower(src.Name)]++
+	}
+	removed := make(map[string]bool)
+	for _, src := range sources {
+		p := strings.TrimSuffix(filepath.ToSlash(src.RelPath), ".md")
+		m.sections[p] = src.Name
+		if names[strings.ToLower(src.Name)] > 1 {
+			m.sections[p] = p
+		}
+		removed[src.RelPath] = true
+	}
+
+	var relPaths []string
+	for _, n := range notes {
+		if !removed[n.RelPath] {
+			relPaths = append(relPaths, n.RelPath)
+		}
+	}
+	for _, to := range archived {
+		relPaths = append(relPaths, to)
+	}
+	if created {
+		relPaths = append(relPaths, target+".md")
+	}
+	m.newIndex = newNoteIndex(relPaths)
+	return m
+}
+
+// retarget rewrites a link to a merged note. inTarget reports whether the
+// link ends up in the target, where it becomes a same-note link.
+func (m *noteMerger) retarget(l wikilink, inTarget bool) (string, bool) {
+	if l.Target == "" {
+		return "", false
+	}
+	p, ok := m.index.resolve(l.Target)
+	if !ok {
+		return "", false
+	}
+	fragment, ok := m.mergedFragment(p, l.Fragment, inTarget)
+	if !ok {
+		return "", false
+	}
+	l.Fragment = fragment
+	l.Target = m.newIndex.linkText(m.target)
+	if inTarget {
+		l.Target = ""
+	}
+	return l.String(), true
+}
+
+// mergedFragment returns the fragment a link to note p gets once the
+// merge is done: a merged note's section unless the link already names a
+// heading. Reports false when the link stays as it is.
+func (m *noteMerger) mergedFragment(p, fragment string, inTarget bool) (string, bool) {
+	section, merged := m.sections[p]
+	switch {
+	case merged && fragment == "":
+		return "#" + section, true
+	case merged, p == m.target && inTarget && fragment != "":
+		return fragment, true
+	}
+	return "", false
+}
+
+// retargetMarkdown rewrites a markdown link in note src to a merged note
+// the way retarget rewrites wikilinks. Relative destinations stay
+// relative to src.
+func (m *noteMerger) retargetMarkdown(src string, ml markdownLinkMatch, inTarget bool) (string, bool) {
+	if ml.Target == "" || strings.Contains(ml.Dest, "://") || strings.HasPrefix(ml.Dest, "mailto:") {
+		return "", false
+	}
+	if ext := strings.ToLower(path.Ext(ml.Target)); ext != ".md" && ext != "" {
+		return "", false
+	}
+	relative := !strings.HasPrefix(ml.Target, "/")
+	p, ok := m.index.resolve(path.Join(path.Dir(src), ml.Target))
+	if !ok || !relative {
+		relative = false
+		if p, ok = m.index.resolve(ml.Target); !ok {
+			return "", false
+		}
+	}
+	fragment, ok := m.mergedFragment(p, ml.Fragment, inTarget)
+	if !ok {
+		return "", false
+	}
+
+	dest := ""
+	if !inTarget {
+		dest = m.target + ".md"
+		if relative {
+			dest = relativeLinkPath(src, dest)
+		}
+		dest = encodeLinkPath(dest)
+	}
+	if fragment != "" {
+		dest += "#" + encodeLinkPath(strings.TrimPrefix(fragment, "#"))
+	}
+	prefix := ""
+	if ml.Embed {
+		prefix = "!"
+	}
+	return fmt.Sprintf("%s[%s](%s%s)", prefix, ml.Label, dest, ml.Title), true
+}
+
+// rewriteLinks applies retarget and retargetMarkdown to the links in the
+// body of note src.
+func (m *noteMerger) rewriteLinks(src, body string, inTarget bool) (string, int) {
+	count := 0
+	out := mapOutsideFences(body, 1, func(chunk string, _ int) string {
+		return mapOutsideInlineCode(chunk, 1, func(piece string, _ int) string {
+			out, n := rewriteWikilinks(piece, func(l wikilink) (string, bool) {
+				return m.retarget(l, inTarget)
+			})
+			count += n
+			return markdownLinkRegex.ReplaceAllStringFunc(out, func(match string) string {
+				ml := parseMarkdownLink(match, markdownLinkRegex.FindStringSubmatchIndex(match))
+				rewritten, ok := m.retargetMarkdown(src, ml, inTarget)
+				if !ok {
+					return match
+				}
+				count++
+				return rewritten
+			})
+		})
+	})
+	return out, count
+}
+
+// merge returns the content of the target with the sources appended under
+// headings at level, and the frontmatter conflicts. target is nil when it
+// is created.
+func (m *noteMerger) merge(target *noteFile, sources []*noteFile, level int) (string, []MergeConflict) {
+	fm := &frontmatter{}
+	var parts []string
+	if target != nil {
+		fm = target.Frontmatter
+		body, _ := m.rewriteLinks(m.target+".md", target.Body, true)
+		if body = strings.Trim(body, "\n"); body != "" {
+			parts = append(parts, body)
+		}
+	}
+
+	conflicts := []MergeConflict{}
+	for _, src := range sources {
+		for _, field := range src.Frontmatter.Fields {
+			if c := mergeFrontmatterField(fm, field); c != nil {
+				c.Source = filepath.ToSlash(src.RelPath)
+				conflicts = append(conflicts, *c)
+			}
+		}
+		body, _ := m.rewriteLinks(filepath.ToSlash(src.RelPath), src.Body, true)
+		section := m.sections[strings.TrimSuffix(filepath.ToSlash(src.RelPath), ".md")]
+		text := strings.Repeat("#", level) + " " + section
+		if body = nestHeadings(body, src.Name, level); body != "" {
+			text += "\n" + body
+		}
+		parts = append(parts, text)
+	}
+
+	content := strings.Join(parts, "\n\n") + "\n"
+	if len(fm.Fields) > 0 {
+		content = fm.Block() + content
+	}
+	return content, conflicts
+}
+
+// mergeFrontmatterField merges a field of a merged note into fm. Lists are
+// combined; a scalar with another value is kept and reported.
+func mergeFrontmatterField(fm *frontmatter, field *frontmatterField) *MergeConflict {
+	existing := fm.Get(field.Key)
+	if existing == nil {
+		copied := *field
+		fm.Fields = append(fm.Fields, &copied)
+		return nil
+	}
+	if existing.IsList || field.IsList {
+		items := fm.Values(existing.Key)
+		have := make(map[string]bool)
+		for _, item := range items {
+			have[strings.ToLower(item)] = true
+		}
+		added := false
+		for _, item := range (&frontmatter{Fields: []*frontmatterField{field}}).Values(field.Key) {
+			if !have[strings.ToLower(item)] {
+				have[strings.ToLower(item)] = true
+				items = append(items, item)
+				added = true
+			}
+		}
+		if added || !existing.IsList {
+			fm.SetList(existing.Key, items)
+		}
+		return nil
+	}
+	if field.Value == existing.Value || field.Value == "" && len(field.Raw) <= 1 {
+		return nil
+	}
+	if existing.Value == "" && len(existing.Raw) <= 1 {
+		fm.SetScalar(existing.Key, field.Value)
+		return nil
+	}
+	return &MergeConflict{Key: existing.Key, Kept: existing.Value, Dropped: field.Value}
+}
+
+// nestHeadings prepares the body of a merged note for its section at
+// level: a first heading repeating the note's name is dropped, and the
+// other headings are moved below level.
+func nestHeadings(body, name string, level int) string {
+	lines := strings.Split(strings.Trim(body, "\n"), "\n")
+	headings := parseHeadings(strings.Join(lines, "\n"))
+	if len(headings) > 0 && headings[0].Line == 1 && strings.EqualFold(headings[0].Text, name) {
+		lines = lines[1:]
+		headings = headings[1:]
+		for i := range headings {
+			headings[i].Line--
+		}
+	}
+
+	shift := 0
+	for _, h := range headings {
+		if s := level + 1 - h.Level; s > shift {
+			shift = s
+		}
+	}
+	for _, h := range headings {
+		line := strings.TrimLeft(lines[h.Line-1], "#")
+		lines[h.Line-1] = strings.Repeat("#", min(h.Level+shift, 6)) + line
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// writeMerge writes the target and the changed notes and removes or
+// archives the sources through a journal. Returns the journal ID.
+func writeMerge(absPath string, result *MergeResult, content string, changed map[string]string) (journalID string, err error) {
+	j := newJournal(absPath, "merge", fmt.Sprintf("merge %d notes into %s", len(result.Sources), result.Target))
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	target := filepath.FromSlash(result.Target)
+	if result.Created {
+		// Re-check right before writing; never overwrite
+		if _, err := os.Lstat(filepath.Join(absPath, target)); err == nil {
+			return j.ID, fmt.Errorf("note already exists: %s", result.Target)
+		}
+	}
+	if err := j.writeFile(target, []byte(content), 0644); err != nil {
+		return j.ID, err
+	}
+	for _, file := range sortedKeys(changed) {
+		if err := j.writeFile(file, []byte(changed[file]), 0644); err != nil {
+			return j.ID, err
+		}
+	}
+	for _, src := range result.Sources {
+		if to, ok := result.Archived[src]; ok {
+			err = j.move(filepath.FromSlash(src), filepath.FromSlash(to))
+		} else {
+			err = j.remove(filepath.FromSlash(src))
+		}
+		if err != nil {
+			return j.ID, err
+		}
+	}
+	return j.ID, nil
+}
+
+func printMerge(result *MergeResult, oldContent, newContent map[string]string, elapsed time.Duration) {
+	title := "Merge into " + result.Target
+	if mergeDryRun {
+		title += " (dry run)"
+	}
+	if result.Created {
+		title += " " + colors.Dim("(new note)")
+	}
+	fmt.Printf("%s %s\n\n", colors.Green("→"), title)
+
+	for _, src := range result.Sources {
+		if to, ok := result.Archived[src]; ok {
+			fmt.Printf("    %s %s %s\n", colors.Yellow("~"), src, colors.Dim("-> "+to))
+		} else {
+			fmt.Printf("    %s %s\n", colors.Red("-"), src)
+		}
+	}
+	fmt.Println()
+	if len(result.Conflicts) > 0 {
+		fmt.Printf("  %s\n", colors.Yellow("Frontmatter conflicts (first value kept):"))
+		for _, c := range result.Conflicts {
+			fmt.Printf("    %s: %s %s\n", c.Key, c.Kept, colors.Dim(fmt.Sprintf("(dropped %q from %s)", c.Dropped, c.Source)))
+		}
+		fmt.Println()
+	}
+	if len(result.Backlinks) > 0 {
+		fmt.Printf("  %s\n", colors.Cyan("Backlinks retargeted:"))
+		for _, f := range result.Backlinks {
+			fmt.Printf("    %s %s\n", colors.Cyan(f.File), colors.Dim(fmt.Sprintf("(%d links)", f.Links)))
+			if mergeDryRun {
+				file := filepath.FromSlash(f.File)
+				printDiff(diffLines(oldContent[file], newContent[file]), 0)
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Printf("  %s %s\n\n", colors.Cyan("Analyzed in:"), elapsed.Round(time.Millisecond))
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import "testing"
+
+// TestMergeFrontmatterField tests list unions and scalar conflicts
+func TestMergeFrontmatterField(t *testing.T) {
+	fm := parseFrontmatter("tags: [a]\nstatus: open")
+	other := parseFrontmatter("tags:\n  - A\n  - b\nstatus: done\nowner: me")
+	var conflicts []*MergeConflict
+	for _, field := range other.Fields {
+		if c := mergeFrontmatterField(fm, field); c != nil {
+			conflicts = append(conflicts, c)
+		}
+	}
+	if want := "tags:\n  - a\n  - b\nstatus: open\nowner: me"; fm.String() != want {
+		t.Errorf("frontmatter = %q, want %q", fm.String(), want)
+	}
+	if len(conflicts) != 1 || conflicts[0].Key != "status" || conflicts[0].Dropped != "done" {
+		t.Errorf("conflicts = %+v, want status with done dropped", conflicts)
+	}
+}
+
+// TestNestHeadings tests that headings move below the section level and a
+// title heading is dropped
+func TestNestHeadings(t *testing.T) {
+	got := nestHeadings("# Idea\ntext\n## Part\n# Other\n", "idea", 2)
+	if want := "text\n#### Part\n### Other"; got != want {
+		t.Errorf("nestHeadings = %q, want %q", got, want)
+	}
+}
+
+// TestMergeRewriteLinks tests that wikilinks and markdown links to merged
+// notes are retargeted, in other notes and inside the target
+func TestMergeRewriteLinks(t *testing.T) {
+	var notes []*noteFile
+	for _, rel := range []string{"A.md", "B.md", "T.md", "other.md", "sub/x.md"} {
+		notes = append(notes, parseNote("/v", "/v/"+rel, ""))
+	}
+	m := newNoteMerger(notes, notes[:2], "T", false, nil)
+
+	body := "[[B]] [m](../B.md) [h](/A.md#Part) [o](../other.md) ![i](B.png) [w](https://x.org/B.md)\n"
+	got, count := m.rewriteLinks("sub/x.md", body, false)
+	if want := "[[T#B]] [m](../T.md#B) [h](T.md#Part) [o](../other.md) ![i](B.png) [w](https://x.org/B.md)\n"; got != want || count != 3 {
+		t.Errorf("rewriteLinks = %q, %d; want %q, 3", got, count, want)
+	}
+	got, count = m.rewriteLinks("A.md", "[b](B.md#Sec%20Two) [t](T.md)\n", true)
+	if want := "[b](#Sec%20Two) [t](T.md)\n"; got != want || count != 1 {
+		t.Errorf("rewriteLinks in target = %q, %d; want %q, 1", got, count, want)
+	}
+}
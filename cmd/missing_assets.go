@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	missingAssetsFormat string
+	missingAssetsLimit  int
+	missingAssetsFolder string
+)
+
+// maxAssetSuggestions is the number of fixes suggested per missing asset.
+const maxAssetSuggestions = 3
+
+var missingAssetsCmd = &cobra.Command{
+	Use:   "missing-assets",
+	Short: "Find embeds and images pointing to files that don't exist",
+	Long: `Lists embeds (![[diagram.png]]) and markdown images (![](img/photo.jpg))
+whose file isn't in the vault.
+
+Targets are resolved the way Obsidian does: the path as written (relative
+to the note for markdown images), then the name in the attachment folder
+from .obsidian/app.json, then in the note's folder, then anywhere in the
+vault.
+
+Each missing file gets fix suggestions: files with the same name and a
+different extension, close names (typos), and copies moved to the trash
+by "unused-assets --trash".
+
+Examples:
+  obsidian-cli missing-assets --vault ~/Documents/Obsidian
+  obsidian-cli missing-assets --vault ~/Documents/Obsidian --folder Projects
+  obsidian-cli missing-assets --vault ~/Documents/Obsidian --format json`,
+	Args: cobra.NoArgs,
+	RunE: runMissingAssets,
+}
+
+func init() {
+	rootCmd.AddCommand(missingAssetsCmd)
+	missingAssetsCmd.Flags().StringVar(&missingAssetsFormat, "format", "text", "Output format: text, json")
+	missingAssetsCmd.Flags().IntVarP(&missingAssetsLimit, "limit", "n", 0, "Limit number of results (0 = no limit)")
+	missingAssetsCmd.Flags().StringVarP(&missingAssetsFolder, "folder", "f", "", "Only check notes in this folder")
+}
+
+// MissingAsset is an embed or markdown image whose file doesn't exist.
+type MissingAsset struct {
+	Source      string            `json:"source"`
+	Line        int               `json:"line"`
+	Link        string            `json:"link"` // As written
+	Target      string            `json:"target"`
+	Suggestions []AssetSuggestion `json:"suggestions"`
+}
+
+// AssetSuggestion is a possible fix for a missing asset.
+type AssetSuggestion struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+	Fix    string `json:"fix,omitempty"` // The link rewritten to Path; empty for trashed files
+}
+
+// MissingAssetsResult is the result of missing-assets.
+type MissingAssetsResult struct {
+	NotesChecked int            `json:"notes_checked"`
+	Missing      []MissingAsset `json:"missing"`
+}
+
+func runMissingAssets(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	start := time.Now()
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+	if missingAssetsFormat == "text" {
+		printScanHeader("Checking embeds")
+	}
+
+	notes, err := loadNotes(absPath)
+	if err != nil {
+		return err
+	}
+	r, err := newAssetResolver(absPath, newNoteIndexFromNotes(notes))
+	if err != nil {
+		return err
+	}
+	var checked []*noteFile
+	for _, n := range notes {
+		if folderMatches(filepath.ToSlash(n.RelPath), missingAssetsFolder) {
+			checked = append(checked, n)
+		}
+	}
+
+	result := &MissingAssetsResult{NotesChecked: len(checked), Missing: r.findMissing(checked)}
+	total := len(result.Missing)
+	result.Missing = applyLimit(result.Missing, missingAssetsLimit)
+	if missingAssetsFormat == "json" {
+		return encodeJSON(cmd, result)
+	}
+
+	printMissingAssets(result, total)
+	printLimitNote(total, missingAssetsLimit)
+	printScanFooter(time.Since(start))
+	return nil
+}
+
+// assetResolver resolves embeds to vault files the way Obsidian does and
+// suggests fixes for the ones that don't resolve.
+type assetResolver struct {
+	assets      *noteIndex
+	notes       *noteIndex
+	attachments string                // attachmentFolderPath from .obsidian/app.json
+	trashed     map[string]TrashEntry // Lowercase original path and basename -> pending trash entry
+	batchOf     map[string]string     // Trash path -> batch ID
+}
+
+func newAssetResolver(absPath string, notes *noteIndex) (*assetResolver, error) {
+	_, assets, err := loadAssetIndex(absPath)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := loadObsidianAppSettings(absPath)
+	if err != nil {
+		return nil, err
+	}
+	batches, err := loadTrashBatches(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &assetResolver{assets: assets, notes: notes, attachments: settings.AttachmentFolderPath,
+		trashed: make(map[string]TrashEntry), batchOf: make(map[string]string)}
+	// Batches are newest first; keep the latest copy of each file
+	for _, b := range batches {
+		for _, f := range b.pending() {
+			for _, key := range []string{strings.ToLower(f.Path), strings.ToLower(pathBase(f.Path))} {
+				if _, seen := r.trashed[key]; !seen {
+					r.trashed[key] = f
+				}
+			}
+			r.batchOf[f.TrashPath] = b.ID
+		}
+	}
+	return r, nil
+}
+
+// attachmentFolder returns the folder new attachments of a note in dir
+// go to: "/" is the vault root, "./" the note's folder and "./sub" a
+// subfolder of it.
+func (r *assetResolver) attachmentFolder(dir string) string {
+	switch {
+	case r.attachments == "" || r.attachments == "/":
+		return "."
+	case r.attachments == "." || strings.HasPrefix(r.attachments, "./"):
+		return path.Join(dir, strings.TrimPrefix(r.attachments, "."))
+	}
+	return strings.Trim(r.attachments, "/")
+}
+
+// resolve returns the vault file a link in note src points to: the path
+// as written, then its name in the attachment folder, in the note's
+// folder, then anywhere in the vault (shallowest first).
+func (r *assetResolver) resolve(src string, l assetLink) (string, bool) {
+	if a, ok := resolveAssetLink(r.assets, src, l); ok {
+		return a, true
+	}
+	name := strings.ToLower(pathBase(l.Target))
+	dir := path.Dir(src)
+	for _, folder := range []string{r.attachmentFolder(dir), dir} {
+		if a, ok := r.assets.byPath[strings.ToLower(path.Join(folder, name))]; ok {
+			return a, true
+		}
+	}
+	if matches := r.assets.byName[name]; len(matches) > 0 {
+		return matches[0], true
+	}
+	return "", false
+}
+
+// isAssetEmbed reports whether a link embeds a file other than a note.
+func (r *assetResolver) isAssetEmbed(l assetLink) bool {
+	ext := strings.ToLower(path.Ext(l.Target))
+	if !l.Embed || ext == "" || ext == ".md" {
+		return false
+	}
+	// Notes with dots in their name ("v1.2") look like files
+	_, isNote := r.notes.resolve(l.Target)
+	return !isNote
+}
+
+// findMissing returns the asset embeds in notes that don't resolve, by
+// note and line.
+func (r *assetResolver) findMissing(notes []*noteFile) []MissingAsset {
+	missing := []MissingAsset{}
+	for _, n := range notes {
+		src := filepath.ToSlash(n.RelPath)
+		rewriteAssetLinks(n, func(l assetLink) (string, bool) {
+			if !r.isAssetEmbed(l) {
+				return "", false
+			}
+			if _, ok := r.resolve(src, l); ok {
+				return "", false
+			}
+			missing = append(missing, MissingAsset{
+				Source:      n.RelPath,
+				Line:        l.Line,
+				Link:        l.Text,
+				Target:      l.Target,
+				Suggestions: r.suggest(src, l, maxAssetSuggestions),
+			})
+			return "", false
+		})
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		if missing[i].Source != missing[j].Source {
+			return missing[i].Source < missing[j].Source
+		}
+		return missing[i].Line < missing[j].Line
+	})
+	return missing
+}
+
+// suggest returns up to limit fixes for a missing asset: a trashed copy,
+// then files with the same name and another extension, then close names.
+func (r *assetResolver) suggest(src string, l assetLink, limit int) []AssetSuggestion {
+	suggestions := []AssetSuggestion{}
+	target := strings.ToLower(strings.TrimPrefix(l.Target, "/"))
+	name := pathBase(target)
+
+	trashed, ok := r.trashed[strings.ToLower(path.Join(path.Dir(src), target))]
+	if !ok {
+		trashed, ok = r.trashed[target]
+	}
+	if !ok {
+		trashed, ok = r.trashed[name]
+	}
+	if ok {
+		id := r.batchOf[trashed.TrashPath]
+		suggestions = append(suggestions, AssetSuggestion{
+			Path:   trashed.Path,
+			Reason: fmt.Sprintf("in the trash, restore with: obsidian-cli assets restore %s", id),
+		})
+	}
+
+	type candidate struct {
+		path string
+		rank int // 0 for a different extension, else 1 + edit distance
+	}
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	maxDist := maxTypoDistance(name)
+	var candidates []candidate
+	for _, a := range r.assets.paths {
+		base := strings.ToLower(pathBase(a))
+		aExt := path.Ext(base)
+		if strings.TrimSuffix(base, aExt) == stem && aExt != ext {
+			candidates = append(candidates, candidate{a, 0})
+		} else if d := levenshtein(name, base); d <= maxDist {
+			candidates = append(candidates, candidate{a, 1 + d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].rank < candidates[j].rank })
+
+	for _, c := range candidates {
+		reason := "same name, different extension"
+		if c.rank > 0 {
+			reason = fmt.Sprintf("edit distance %d", c.rank-1)
+		}
+		suggestions = append(suggestions, AssetSuggestion{
+			Path:   c.path,
+			Reason: reason,
+			Fix:    l.withTarget(assetLinkTarget(r.assets, r.assets, src, c.path, l)),
+		})
+	}
+	return applyLimit(suggestions, limit)
+}
+
+func printMissingAssets(result *MissingAssetsResult, total int) {
+	fmt.Printf("%s Missing Assets %s\n\n", colors.Red("!"), colors.Dim(fmt.Sprintf("(%d in %d notes checked)", total, result.NotesChecked)))
+	if len(result.Missing) == 0 {
+		fmt.Println("  No missing assets found.")
+		return
+	}
+
+	bySource := make(map[string][]MissingAsset)
+	for _, m := range result.Missing {
+		bySource[m.Source] = append(bySource[m.Source], m)
+	}
+	for _, source := range sortedKeys(bySource) {
+		missing := bySource[source]
+		fmt.Printf("  %s %s\n", colors.Cyan(source), colors.Dim(fmt.Sprintf("(%d)", len(missing))))
+		for _, m := range missing {
+			fmt.Printf("    :%d %s\n", m.Line, colors.Red(m.Link))
+			if len(m.Suggestions) == 0 {
+				fmt.Printf("         %s\n", colors.Dim("no suggestions; restore the file or remove the embed"))
+			}
+			for _, sg := range m.Suggestions {
+				fix := sg.Fix
+				if fix == "" {
+					fix = sg.Path
+				}
+				fmt.Printf("         %s %s %s\n", colors.Green("→"), fix, colors.Dim("("+sg.Reason+")"))
+			}
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Moment.js format tokens, longest first so "YYYY" wins over "YY"
+var momentTokens = []string{
+	"YYYY", "GGGG", "gggg", "MMMM", "dddd", "DDDD",
+	"MMM", "ddd", "DDD", "SSS",
+	"YY", "GG", "gg", "MM", "Do", "DD", "dd", "WW", "ww", "HH", "hh", "mm", "ss",
+	"Q", "M", "D", "d", "E", "e", "W", "w", "H", "h", "m", "s", "A", "a", "X", "x",
+}
+
+// formatMoment formats t with a moment.js format string, the way Obsidian
+// names daily and periodic notes ("YYYY-MM-DD", "gggg-[W]ww"). Text in
+// [brackets] is literal. Locale weeks (w, gggg) use moment's default
+// English locale: weeks start on Sunday and the week with January 1st is
+// week 1.
+func formatMoment(t time.Time, format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); {
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end != -1 {
+				b.WriteString(format[i+1 : i+end])
+				i += end + 1
+				continue
+			}
+		}
+		token := ""
+		for _, tok := range momentTokens {
+			if strings.HasPrefix(format[i:], tok) {
+				token = tok
+				break
+			}
+		}
+		if token == "" {
+			b.WriteByte(format[i])
+			i++
+			continue
+		}
+		b.WriteString(momentToken(t, token))
+		i += len(token)
+	}
+	return b.String()
+}
+
+// momentToken formats one moment.js token.
+func momentToken(t time.Time, token string) string {
+	isoYear, isoWeek := t.ISOWeek()
+	weekYear, week := localeWeek(t)
+	hour12 := t.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+	switch token {
+	case "YYYY":
+		return fmt.Sprintf("%04d", t.Year())
+	case "YY":
+		return fmt.Sprintf("%02d", t.Year()%100)
+	case "GGGG":
+		return fmt.Sprintf("%04d", isoYear)
+	case "GG":
+		return fmt.Sprintf("%02d", isoYear%100)
+	case "gggg":
+		return fmt.Sprintf("%04d", weekYear)
+	case "gg":
+		return fmt.Sprintf("%02d", weekYear%100)
+	case "Q":
+		return fmt.Sprint((int(t.Month())-1)/3 + 1)
+	case "MMMM":
+		return t.Month().String()
+	case "MMM":
+		return t.Month().String()[:3]
+	case "MM":
+		return fmt.Sprintf("%02d", int(t.Month()))
+	case "M":
+		return fmt.Sprint(int(t.Month()))
+	case "DDDD":
+		return fmt.Sprintf("%03d", t.YearDay())
+	case "DDD":
+		return fmt.Sprint(t.YearDay())
+	case "DD":
+		return fmt.Sprintf("%02d", t.Day())
+	case "D":
+		return fmt.Sprint(t.Day())
+	case "Do":
+		return ordinal(t.Day())
+	case "dddd":
+		return t.Weekday().String()
+	case "ddd":
+		return t.Weekday().String()[:3]
+	case "dd":
+		return t.Weekday().String()[:2]
+	case "d", "e":
+		return fmt.Sprint(int(t.Weekday()))
+	case "E":
+		return fmt.Sprint((int(t.Weekday())+6)%7 + 1)
+	case "WW":
+		return fmt.Sprintf("%02d", isoWeek)
+	case "W":
+		return fmt.Sprint(isoWeek)
+	case "ww":
+		return fmt.Sprintf("%02d", week)
+	case "w":
+		return fmt.Sprint(week)
+	case "HH":
+		return fmt.Sprintf("%02d", t.Hour())
+	case "H":
+		return fmt.Sprint(t.Hour())
+	case "hh":
+		return fmt.Sprintf("%02d", hour12)
+	case "h":
+		return fmt.Sprint(hour12)
+	case "mm":
+		return fmt.Sprintf("%02d", t.Minute())
+	case "m":
+		return fmt.Sprint(t.Minute())
+	case "ss":
+		return fmt.Sprintf("%02d", t.Second())
+	case "s":
+		return fmt.Sprint(t.Second())
+	case "SSS":
+		return fmt.Sprintf("%03d", t.Nanosecond()/int(time.Millisecond))
+	case "A":
+		return t.Format("PM")
+	case "a":
+		return t.Format("pm")
+	case "X":
+		return fmt.Sprint(t.Unix())
+	case "x":
+		return fmt.Sprint(t.UnixMilli())
+	}
+	return token
+}
+
+// localeWeek returns the week-year and week of t in moment's default
+// locale: the week belongs to the year its Saturday falls in.
+func localeWeek(t time.Time) (year, week int) {
+	saturday := t.AddDate(0, 0, 6-int(t.Weekday()))
+	return saturday.Year(), (saturday.YearDay()-1)/7 + 1
+}
+
+// ordinal returns n with its English ordinal suffix: 1st, 2nd, 11th.
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
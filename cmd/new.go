@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	newTemplate    string
+	newFolder      string
+	newVars        []string
+	newLinkFrom    string
+	newLinkSection string
+	newDryRun      bool
+	newFormat      string
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a note from a template",
+	Long: `Creates a note, rendering a template the way Obsidian's Templates and
+Templater plugins do. Existing notes are never overwritten.
+
+Templates are looked up as a vault path, then in the Templates and
+Templater folders. They can use:
+  - {{title}}, {{date}}, {{time}}, {{date:FORMAT}}, {{date+1d}} and
+    {{yesterday}}/{{tomorrow}} (formats from .obsidian/templates.json)
+  - Templater tags that don't run code: tp.file.title, tp.file.folder(),
+    tp.file.path(), tp.file.creation_date(), tp.date.now("FORMAT", offset),
+    tp.date.tomorrow(), tp.date.yesterday(), tp.date.weekday()
+  - Custom variables from --var key=value, as {{key}} or as the answer to
+    tp.system.prompt("key")
+Other Templater tags are kept as they are and reported. A --var naming a
+frontmatter field of the template sets that field.
+
+The note goes to the folder in its name ("Projects/Plan"), else --folder,
+else the folder .obsidian-cli.json sets for the template (new.folders),
+else new.folder, else Obsidian's default location for new notes. Without
+--template, the Templater folder template for that folder is used, else
+new.template.
+
+--link-from appends a link to the new note to a parent note, under
+--link-section when given.
+
+Examples:
+  obsidian-cli new "Weekly sync" --template Meeting --vault ~/notes
+  obsidian-cli new "Projects/Apollo" -t Project --var status=active --vault ~/notes
+  obsidian-cli new "Idea" --link-from Inbox --link-section "## Ideas" --vault ~/notes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNew,
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+	newCmd.Flags().StringVarP(&newTemplate, "template", "t", "", "Template note (vault path or name in the templates folder)")
+	newCmd.Flags().StringVar(&newFolder, "folder", "", "Vault folder for the note")
+	newCmd.Flags().StringArrayVar(&newVars, "var", nil, "Template variable as key=value (repeatable)")
+	newCmd.Flags().StringVar(&newLinkFrom, "link-from", "", "Parent note to append a link to the new note to")
+	newCmd.Flags().StringVar(&newLinkSection, "link-section", "", "Heading of the parent note to add the link under")
+	newCmd.Flags().BoolVar(&newDryRun, "dry-run", false, "Show the note without creating it")
+	newCmd.Flags().StringVar(&newFormat, "format", "text", "Output format: text, json, path")
+}
+
+// NewNoteResult is the result of the new command.
+type NewNoteResult struct {
+	Path         string `json:"path"`
+	AbsolutePath string `json:"absolute_path"`
+	Template     string `json:"template,omitempty"`
+	LinkedFrom   string `json:"linked_from,omitempty"`
+	Unsupported  int    `json:"unsupported_templater_tags,omitempty"`
+	Content      string `json:"content"`
+	Executed     bool   `json:"executed"`
+	JournalID    string `json:"journal_id,omitempty"`
+}
+
+func runNew(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadVaultConfig(absPath)
+	if err != nil {
+		return err
+	}
+	appSettings, err := loadObsidianAppSettings(absPath)
+	if err != nil {
+		return err
+	}
+	templateSettings, err := loadObsidianTemplateSettings(absPath)
+	if err != nil {
+		return err
+	}
+
+	name := strings.Trim(strings.TrimSuffix(filepath.ToSlash(args[0]), ".md"), "/")
+	if pathBase(name) == "" || strings.ContainsAny(name, invalidStubChars) {
+		return fmt.Errorf("invalid note name: %q", args[0])
+	}
+	vars := make(map[string]string, len(newVars))
+	for _, v := range newVars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --var %q: expected key=value", v)
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+
+	folder := newNoteFolder(cmd, cfg, appSettings, name)
+	relPath := path.Join(folder, name) + ".md"
+	fullPath := filepath.Join(absPath, filepath.FromSlash(relPath))
+	if !isPathWithinVault(fullPath, absPath) {
+		return fmt.Errorf("note path escapes vault boundary: %s", relPath)
+	}
+	if _, err := os.Lstat(fullPath); err == nil {
+		return fmt.Errorf("note already exists: %s", relPath)
+	}
+
+	templatePath := newTemplate
+	if templatePath == "" {
+		templatePath = folderTemplateFor(templateSettings.FolderTemplates, path.Dir(relPath))
+	}
+	if templatePath == "" {
+		templatePath = cfg.New.Template
+	}
+	content := ""
+	result := &NewNoteResult{Path: relPath, AbsolutePath: fullPath, Executed: !newDryRun}
+	if templatePath != "" {
+		var template string
+		if result.Template, template, err = findTemplate(absPath, templateSettings, templatePath); err != nil {
+			return err
+		}
+		now := time.Now()
+		content = renderTemplate(template, templateVars{
+			Title:      pathBase(name),
+			Date:       now,
+			Now:        now,
+			DateFormat: templateSettings.DateFormat,
+			TimeFormat: templateSettings.TimeFormat,
+			Path:       relPath,
+			Vars:       vars,
+		})
+		content, result.Unsupported = renderTemplater(content, templateVars{Title: pathBase(name), Now: now, Path: relPath, Vars: vars}, absPath)
+	}
+	content = setTemplateFields(content, vars)
+	result.Content = content
+
+	// Link from the parent with the shortest link that resolves
+	var parentRel, parentContent string
+	if newLinkFrom != "" {
+		parentPath, err := findNoteFile(absPath, newLinkFrom)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(parentPath)
+		if err != nil {
+			return err
+		}
+		mdFiles, err := collectMarkdownFiles(absPath)
+		if err != nil {
+			return err
+		}
+		relPaths := []string{relPath}
+		for _, f := range mdFiles {
+			relPaths = append(relPaths, mustRelPath(absPath, f))
+		}
+		link := "[[" + newNoteIndex(relPaths).linkText(strings.TrimSuffix(relPath, ".md")) + "]]"
+		parentRel, parentContent = mustRelPath(absPath, parentPath), string(data)
+		if newLinkSection != "" {
+			parentContent, _ = insertUnderHeading(parentContent, newLinkSection, link)
+		} else {
+			if parentContent != "" && !strings.HasSuffix(parentContent, "\n") {
+				parentContent += "\n"
+			}
+			parentContent += link + "\n"
+		}
+		result.LinkedFrom = filepath.ToSlash(parentRel)
+	}
+
+	if !newDryRun {
+		if result.JournalID, err = writeNewNote(absPath, relPath, content, parentRel, parentContent); err != nil {
+			return err
+		}
+	}
+
+	switch newFormat {
+	case "json":
+		return encodeJSON(cmd, result)
+	case "path":
+		fmt.Fprintln(cmd.OutOrStdout(), result.AbsolutePath)
+		return nil
+	}
+	printNewNoteResult(result, newDryRun)
+	return nil
+}
+
+// newNoteFolder returns the folder a new note goes to.
+func newNoteFolder(cmd *cobra.Command, cfg *vaultConfig, app *obsidianAppSettings, name string) string {
+	switch {
+	case strings.Contains(name, "/"):
+		// The name has its folder
+		return ""
+	case cmd.Flags().Changed("folder"):
+		return strings.Trim(filepath.ToSlash(newFolder), "/")
+	}
+	if newTemplate != "" {
+		for _, template := range sortedKeys(cfg.New.Folders) {
+			folder := cfg.New.Folders[template]
+			if strings.EqualFold(strings.TrimSuffix(template, ".md"), strings.TrimSuffix(newTemplate, ".md")) ||
+				strings.EqualFold(pathBase(strings.TrimSuffix(template, ".md")), pathBase(strings.TrimSuffix(newTemplate, ".md"))) {
+				return strings.Trim(filepath.ToSlash(folder), "/")
+			}
+		}
+	}
+	if cfg.New.Folder != "" {
+		return strings.Trim(filepath.ToSlash(cfg.New.Folder), "/")
+	}
+	if app.NewFileLocation == "folder" {
+		return strings.Trim(filepath.ToSlash(app.NewFileFolderPath), "/")
+	}
+	return ""
+}
+
+// folderTemplateFor returns the Templater folder template for notes in
+// folder: the one of the most specific folder containing it.
+func folderTemplateFor(templates []folderTemplate, folder string) string {
+	best, bestLen := "", -1
+	for _, ft := range templates {
+		f := strings.Trim(ft.Folder, "/")
+		if (f == "" || folderMatches(folder+"/", f)) && len(f) > bestLen {
+			best, bestLen = ft.Template, len(f)
+		}
+	}
+	return best
+}
+
+// findTemplate finds a template by vault path, or by name in the
+// Templates or Templater folder. Returns its vault-relative path and
+// content.
+func findTemplate(absPath string, settings *obsidianTemplateSettings, name string) (string, string, error) {
+	name = strings.TrimSuffix(filepath.ToSlash(name), ".md")
+	candidates := []string{name}
+	for _, folder := range []string{settings.Folder, settings.TemplaterFolder} {
+		if folder = strings.Trim(filepath.ToSlash(folder), "/"); folder != "" {
+			candidates = append(candidates, path.Join(folder, name))
+		}
+	}
+	for _, candidate := range candidates {
+		full := filepath.Join(absPath, filepath.FromSlash(candidate)+".md")
+		if !isPathWithinVault(full, absPath) {
+			return "", "", fmt.Errorf("template path escapes vault boundary: %s", name)
+		}
+		if _, err := os.Stat(full); err == nil {
+			content, err := loadTemplate(absPath, filepath.FromSlash(candidate))
+			return candidate + ".md", content, err
+		}
+	}
+	return "", "", fmt.Errorf("template not found: %s (looked in %s)", name, strings.Join(candidates, ", "))
+}
+
+// setTemplateFields sets the frontmatter fields of a rendered template
+// that are named by variables, keeping the template's value for the
+// others. Lists are given comma-separated.
+func setTemplateFields(content string, vars map[string]string) string {
+	fmText, body, ok := splitFrontmatter(content)
+	if !ok || len(vars) == 0 {
+		return content
+	}
+	fm := parseFrontmatter(fmText)
+	changed := false
+	for _, key := range sortedKeys(vars) {
+		field := fm.Get(key)
+		if field == nil {
+			continue
+		}
+		if field.IsList {
+			var items []string
+			for _, item := range strings.Split(vars[key], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			fm.SetList(field.Key, items)
+		} else {
+			fm.SetScalar(field.Key, vars[key])
+		}
+		changed = true
+	}
+	if !changed {
+		return content
+	}
+	return fm.Block() + body
+}
+
+// writeNewNote creates the note and links it from the parent through a
+// journal. Returns the journal ID.
+func writeNewNote(absPath, relPath, content, parentRel, parentContent string) (journalID string, err error) {
+	j := newJournal(absPath, "new", "create "+relPath)
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	// Re-check right before writing; never overwrite
+	if _, err := os.Lstat(filepath.Join(absPath, filepath.FromSlash(relPath))); err == nil {
+		return j.ID, fmt.Errorf("note already exists: %s", relPath)
+	}
+	if err := j.writeFile(filepath.FromSlash(relPath), []byte(content), 0644); err != nil {
+		return j.ID, err
+	}
+	if parentRel != "" {
+		if err := j.writeFile(parentRel, []byte(parentContent), 0644); err != nil {
+			return j.ID, err
+		}
+	}
+	return j.ID, nil
+}
+
+// printNewNoteResult prints the created note, or the preview of a dry run.
+func printNewNoteResult(result *NewNoteResult, dryRun bool) {
+	if dryRun {
+		fmt.Printf("%s New note (dry run) %s\n\n", colors.Green("→"), colors.Dim(result.Path))
+		for _, line := range strings.Split(strings.TrimRight(result.Content, "\n"), "\n") {
+			fmt.Printf("  %s %s\n", colors.Dim("│"), line)
+		}
+		fmt.Println()
+	} else {
+		fmt.Printf("%s Created %s\n", colors.Green("✓"), result.Path)
+	}
+	if result.Template != "" {
+		fmt.Printf("  %s Template: %s\n", colors.Dim("i"), result.Template)
+	}
+	if result.LinkedFrom != "" {
+		fmt.Printf("  %s Linked from %s\n", colors.Green("+"), result.LinkedFrom)
+	}
+	if result.Unsupported > 0 {
+		fmt.Printf("  %s %d Templater tags need Obsidian to run and were left as is\n", colors.Yellow("!"), result.Unsupported)
+	}
+	if dryRun {
+		fmt.Printf("  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
+		return
+	}
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n", colors.Dim("i"), result.JournalID)
+}
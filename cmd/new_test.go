@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRenderTemplater tests supported tags, whitespace control and prompts
+func TestRenderTemplater(t *testing.T) {
+	vars := templateVars{
+		Title: "Plan",
+		Now:   time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+		Path:  "Projects/Apollo/Plan.md",
+		Vars:  map[string]string{"Owner": "Sam"},
+	}
+	got, unsupported := renderTemplater("<% tp.file.title %> <% tp.file.folder() %> <% tp.file.folder(true) %>\n"+
+		"<%- tp.date.now(\"YYYY-MM-DD\", -1) %> <% tp.system.prompt('Owner') %> <% tp.system.prompt(\"Other\") %>\n<%* tR += 1 %>", vars, "/v")
+	want := "Plan Apollo Projects/Apollo2026-10-17 Sam <% tp.system.prompt(\"Other\") %>\n<%* tR += 1 %>"
+	if got != want || unsupported != 2 {
+		t.Errorf("renderTemplater = %q, %d; want %q, 2", got, unsupported, want)
+	}
+}
+
+// TestSetTemplateFields tests that variables override frontmatter defaults
+func TestSetTemplateFields(t *testing.T) {
+	content := "---\nstatus: draft\ntags: [a]\ntitle: x\n---\nbody\n"
+	got := setTemplateFields(content, map[string]string{"status": "active", "tags": "b, c", "other": "y"})
+	if want := "---\nstatus: active\ntags:\n  - b\n  - c\ntitle: x\n---\nbody\n"; got != want {
+		t.Errorf("setTemplateFields = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Read and edit a note",
+	Long: `Commands that work on one note, found by name or vault path the way
+the other commands resolve notes.`,
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+}
+
+// lockDir holds the lock files of notes being edited, relative to the vault.
+var lockDir = filepath.Join(".obsidian-cli", "locks")
+
+// lockNote takes an exclusive lock on a vault-relative note, waiting for
+// other obsidian-cli processes editing it. Notes are replaced by renaming,
+// so the lock is held on a separate file. The returned function unlocks.
+func lockNote(absPath, relPath string) (func(), error) {
+	dir := filepath.Join(absPath, lockDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	name := contentHash([]byte(filepath.ToSlash(strings.ToLower(relPath))))[:16] + ".lock"
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", relPath, err)
+	}
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
+
+// editNote applies edit to a note under a lock and replaces it atomically,
+// keeping its mode. edit sees "\n" line endings; lines it leaves alone
+// keep their own and new lines get the note's usual one (see
+// restoreLineEndings). With dryRun nothing is written. Returns the new
+// content and the journal ID.
+func editNote(absPath, relPath, operation, description string, dryRun bool, edit func(string) (string, error)) (content, journalID string, err error) {
+	unlock, err := lockNote(absPath, relPath)
+	if err != nil {
+		return "", "", err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(filepath.Join(absPath, relPath))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+	original := string(data)
+	content, err = edit(strings.ReplaceAll(original, "\r\n", "\n"))
+	if err != nil {
+		return "", "", err
+	}
+	content = restoreLineEndings(original, content)
+	if dryRun || content == original {
+		return content, "", nil
+	}
+
+	j := newJournal(absPath, operation, description)
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+	if err := j.replaceFile(relPath, []byte(content)); err != nil {
+		return "", j.ID, err
+	}
+	return content, j.ID, nil
+}
+
+// restoreLineEndings maps edited, an edit of original with "\r\n" turned
+// into "\n", back onto original's line endings: the unchanged lines before
+// and after the edit keep theirs, and the lines in between get "\r\n" when
+// most of original's lines end with it.
+func restoreLineEndings(original, edited string) string {
+	if !strings.Contains(original, "\r\n") {
+		return edited
+	}
+	crlf := 2*strings.Count(original, "\r\n") >= strings.Count(original, "\n")
+	normalize := func(line string) string {
+		if strings.HasSuffix(line, "\r\n") {
+			return line[:len(line)-2] + "\n"
+		}
+		return line
+	}
+
+	oldLines := strings.SplitAfter(original, "\n")
+	newLines := strings.SplitAfter(edited, "\n")
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && normalize(oldLines[prefix]) == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		normalize(oldLines[len(oldLines)-1-suffix]) == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	var b strings.Builder
+	for _, line := range oldLines[:prefix] {
+		b.WriteString(line)
+	}
+	for _, line := range newLines[prefix : len(newLines)-suffix] {
+		if crlf && strings.HasSuffix(line, "\n") {
+			line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r") + "\r\n"
+		}
+		b.WriteString(line)
+	}
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		b.WriteString(line)
+	}
+	return b.String()
+}
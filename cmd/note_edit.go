@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// noteEditOptions are the flags shared by the note editing commands.
+type noteEditOptions struct {
+	Heading string
+	DryRun  bool
+	Format  string
+}
+
+var (
+	noteAppendOptions  noteEditOptions
+	notePrependOptions noteEditOptions
+	noteInsertOptions  noteEditOptions
+	noteReplaceOptions noteEditOptions
+)
+
+var noteAppendCmd = &cobra.Command{
+	Use:   "append <note> [text]",
+	Short: "Append text to the end of a note",
+	Long: `Appends text to the end of a note. Without text, or with "-", the text is
+read from stdin.
+
+Edits take a lock on the note, so concurrent obsidian-cli edits don't
+lose each other's changes, and replace it atomically, keeping its file
+mode and line endings. They are journaled (revert with "undo").
+
+Examples:
+  obsidian-cli note append Inbox "- [ ] Call Ana" --vault ~/notes
+  pbpaste | obsidian-cli note append "Projects/Apollo" --vault ~/notes`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNoteEdit(cmd, args, &noteAppendOptions, "append", func(content, text string) (string, error) {
+			if content != "" && !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+			return content + text + "\n", nil
+		})
+	},
+}
+
+var notePrependCmd = &cobra.Command{
+	Use:   "prepend <note> [text]",
+	Short: "Insert text at the start of a note, after its frontmatter",
+	Long: `Inserts text at the start of a note, after its frontmatter. Without text,
+or with "-", the text is read from stdin.
+
+Examples:
+  obsidian-cli note prepend "Projects/Apollo" "> [!warning] On hold" --vault ~/notes`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNoteEdit(cmd, args, &notePrependOptions, "prepend", func(content, text string) (string, error) {
+			_, body, _ := splitFrontmatter(content)
+			head := content[:len(content)-len(body)]
+			if head != "" && !strings.HasSuffix(head, "\n") {
+				head += "\n"
+			}
+			return head + text + "\n" + body, nil
+		})
+	},
+}
+
+var noteInsertCmd = &cobra.Command{
+	Use:   "insert <note> [text] --under <heading>",
+	Short: "Insert text at the end of a section",
+	Long: `Inserts text at the end of the section under a heading, before the blank
+lines that separate it from the next heading. The heading is given as
+"## Log" (that level only) or "Log"; a missing heading is added at the
+end of the note. Without text, or with "-", the text is read from stdin.
+
+Examples:
+  obsidian-cli note insert "Projects/Apollo" "- Shipped v2" --under "## Log" --vault ~/notes`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNoteEdit(cmd, args, &noteInsertOptions, "insert", func(content, text string) (string, error) {
+			content, _ = insertUnderHeading(content, noteInsertOptions.Heading, text)
+			return content, nil
+		})
+	},
+}
+
+var noteReplaceSectionCmd = &cobra.Command{
+	Use:   "replace-section <note> [text] --section <heading>",
+	Short: "Replace the content of a section",
+	Long: `Replaces the content of the section under a heading, keeping the heading
+itself. Subsections are part of the section. The heading is given as
+"## Status" (that level only) or "Status" and must exist. Without text,
+or with "-", the text is read from stdin.
+
+Examples:
+  obsidian-cli note replace-section "Projects/Apollo" "Done." --section "## Status" --vault ~/notes
+  ./summary.sh | obsidian-cli note replace-section Weekly --section Summary --vault ~/notes`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNoteEdit(cmd, args, &noteReplaceOptions, "replace-section", func(content, text string) (string, error) {
+			out, found := replaceSection(content, noteReplaceOptions.Heading, text)
+			if !found {
+				return "", fmt.Errorf("heading not found: %s", noteReplaceOptions.Heading)
+			}
+			return out, nil
+		})
+	},
+}
+
+func init() {
+	for _, c := range []struct {
+		cmd  *cobra.Command
+		opts *noteEditOptions
+	}{
+		{noteAppendCmd, &noteAppendOptions},
+		{notePrependCmd, &notePrependOptions},
+		{noteInsertCmd, &noteInsertOptions},
+		{noteReplaceSectionCmd, &noteReplaceOptions},
+	} {
+		noteCmd.AddCommand(c.cmd)
+		c.cmd.Flags().BoolVar(&c.opts.DryRun, "dry-run", false, "Show the edited note without writing it")
+		c.cmd.Flags().StringVar(&c.opts.Format, "format", "text", "Output format: text, json")
+	}
+	noteInsertCmd.Flags().StringVar(&noteInsertOptions.Heading, "under", "", "Heading to insert under, as \"## Heading\" or \"Heading\"")
+	noteInsertCmd.MarkFlagRequired("under")
+	noteReplaceSectionCmd.Flags().StringVar(&noteReplaceOptions.Heading, "section", "", "Heading of the section, as \"## Heading\" or \"Heading\"")
+	noteReplaceSectionCmd.MarkFlagRequired("section")
+}
+
+// NoteEditResult is the result of a note editing command.
+type NoteEditResult struct {
+	Path      string `json:"path"`
+	Operation string `json:"operation"`
+	Heading   string `json:"heading,omitempty"`
+	Changed   bool   `json:"changed"`
+	Content   string `json:"content,omitempty"` // Edited note, for dry runs
+	Executed  bool   `json:"executed"`
+	JournalID string `json:"journal_id,omitempty"`
+}
+
+// runNoteEdit resolves the note, reads the text from the arguments or
+// stdin, and applies edit to the note's content.
+func runNoteEdit(cmd *cobra.Command, args []string, opts *noteEditOptions, operation string, edit func(content, text string) (string, error)) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	notePath, err := findNoteFile(absPath, args[0])
+	if err != nil {
+		return err
+	}
+	if !isPathWithinVault(notePath, absPath) {
+		return fmt.Errorf("note path escapes vault boundary: %s", args[0])
+	}
+	relPath := mustRelPath(absPath, notePath)
+
+	text := strings.Join(args[1:], " ")
+	if text == "" || text == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		text = string(data)
+	}
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" && operation != "replace-section" {
+		return fmt.Errorf("no text to %s", operation)
+	}
+
+	var original string
+	content, journalID, err := editNote(absPath, relPath, "note-"+operation, operation+" "+filepath.ToSlash(relPath), opts.DryRun, func(content string) (string, error) {
+		original = content
+		return edit(content, text)
+	})
+	if err != nil {
+		return err
+	}
+
+	result := NoteEditResult{
+		Path:      filepath.ToSlash(relPath),
+		Operation: operation,
+		Heading:   opts.Heading,
+		Changed:   strings.ReplaceAll(content, "\r\n", "\n") != original,
+		Executed:  !opts.DryRun,
+		JournalID: journalID,
+	}
+	if opts.DryRun {
+		result.Content = content
+	}
+	if opts.Format == "json" {
+		return encodeJSON(cmd, result)
+	}
+
+	if opts.DryRun {
+		fmt.Printf("%s Note %s (dry run) %s\n\n", colors.Green("→"), operation, colors.Dim(result.Path))
+		for _, line := range strings.Split(strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n"), "\n") {
+			fmt.Printf("  %s %s\n", colors.Dim("│"), line)
+		}
+		fmt.Printf("\n  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
+		return nil
+	}
+	if !result.Changed {
+		fmt.Printf("%s %s unchanged\n", colors.Dim("="), result.Path)
+		return nil
+	}
+	where := ""
+	if opts.Heading != "" {
+		where = " under " + strings.TrimSpace(strings.TrimLeft(opts.Heading, "#"))
+	}
+	fmt.Printf("%s Edited %s%s (%s)\n", colors.Green("✓"), result.Path, where, operation)
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n", colors.Dim("i"), journalID)
+	return nil
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	noteShowNoFrontmatter bool
+	noteShowSection       string
+	noteShowDepth         int
+	noteShowRaw           bool
+	noteShowFormat        string
+)
+
+var noteShowCmd = &cobra.Command{
+	Use:   "show <note>",
+	Short: "Print a note with its embeds expanded",
+	Long: `Prints a note the way Obsidian shows it: embedded notes (![[note]]),
+sections (![[note#Heading]]) and blocks (![[note#^block]]) are replaced
+by their content, recursively. Content of several lines, or starting
+with a heading, is put on its own lines even when the embed is inside a
+line. Embeds of files other than notes are kept.
+
+Embeds are expanded up to --depth levels. An embed of a note that is
+already being expanded (a cycle), one past the depth limit, and one
+that doesn't resolve are kept as they are and reported on stderr.
+
+--section prints only one section, given as "Heading", "## Heading" or
+"^block-id". JSON output splits the frontmatter from the body and lists
+the embeds.
+
+Examples:
+  obsidian-cli note show "Projects/Apollo" --vault ~/notes
+  obsidian-cli note show Weekly --section "## Summary" --no-frontmatter --vault ~/notes
+  obsidian-cli note show Apollo --format json --vault ~/notes | jq -r .body`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNoteShow,
+}
+
+func init() {
+	noteCmd.AddCommand(noteShowCmd)
+	noteShowCmd.Flags().BoolVar(&noteShowNoFrontmatter, "no-frontmatter", false, "Leave out the frontmatter")
+	noteShowCmd.Flags().StringVar(&noteShowSection, "section", "", "Print only this section or block")
+	noteShowCmd.Flags().IntVar(&noteShowDepth, "depth", exportMaxEmbedDepth, "Maximum depth of nested embeds")
+	noteShowCmd.Flags().BoolVar(&noteShowRaw, "raw", false, "Don't expand embeds")
+	noteShowCmd.Flags().StringVar(&noteShowFormat, "format", "text", "Output format: text, json")
+}
+
+// Embed statuses
+const (
+	embedExpanded       = "expanded"
+	embedCycle          = "cycle"
+	embedTooDeep        = "too-deep"
+	embedUnresolved     = "unresolved"
+	embedMissingSection = "missing-section"
+)
+
+// NoteEmbed is an embed of a note met while showing a note.
+type NoteEmbed struct {
+	Source string `json:"source"` // Note containing the embed
+	Line   int    `json:"line"`
+	Embed  string `json:"embed"`
+	Path   string `json:"path,omitempty"` // Embedded note
+	Status string `json:"status"`
+}
+
+// NoteShowResult is the result of the note show command.
+type NoteShowResult struct {
+	Path        string         `json:"path"`
+	Frontmatter map[string]any `json:"frontmatter,omitempty"`
+	Body        string         `json:"body"`
+	Embeds      []NoteEmbed    `json:"embeds,omitempty"`
+}
+
+// embedRenderer expands note embeds.
+type embedRenderer struct {
+	absPath  string
+	index    *noteIndex
+	maxDepth int
+	contents map[string]string // Note path (no .md) -> content
+	embeds   []NoteEmbed
+}
+
+func newEmbedRenderer(absPath string, maxDepth int) (*embedRenderer, error) {
+	mdFiles, err := collectMarkdownFiles(absPath)
+	if err != nil {
+		return nil, err
+	}
+	relPaths := make([]string, len(mdFiles))
+	for i, f := range mdFiles {
+		relPaths[i] = mustRelPath(absPath, f)
+	}
+	return &embedRenderer{
+		absPath:  absPath,
+		index:    newNoteIndex(relPaths),
+		maxDepth: maxDepth,
+		contents: make(map[string]string),
+	}, nil
+}
+
+// content reads a note by its path without .md.
+func (r *embedRenderer) content(p string) (string, error) {
+	if content, ok := r.contents[p]; ok {
+		return content, nil
+	}
+	data, err := os.ReadFile(filepath.Join(r.absPath, filepath.FromSlash(p)+".md"))
+	if err != nil {
+		return "", err
+	}
+	r.contents[p] = string(data)
+	return string(data), nil
+}
+
+// expand replaces the note embeds in text, part of note p starting at
+// firstLine. stack holds the notes and sections being expanded, as the
+// note path followed by the lowercase fragment.
+func (r *embedRenderer) expand(p, text string, firstLine, depth int, stack map[string]bool) string {
+	return mapOutsideFences(text, firstLine, func(chunk string, line int) string {
+		// Chunks start at a line start; the pieces between inline code
+		// spans continue a line
+		codeSpans, pieceIndex := len(inlineCodeRegex.FindAllStringIndex(chunk, -1)), -1
+		return mapOutsideInlineCode(chunk, line, func(piece string, line int) string {
+			pieceIndex++
+			afterCode, beforeCode := pieceIndex > 0, pieceIndex < codeSpans
+			out, _ := rewriteWikilinks(piece, func(l wikilink) (string, bool) {
+				if !l.Embed {
+					return "", false
+				}
+				embed := NoteEmbed{Source: p + ".md", Line: line + lineNumberAt(piece, l.Start) - 1, Embed: l.String()}
+				target, ok := p, true
+				if l.Target != "" {
+					target, ok = r.index.resolve(l.Target)
+				}
+				if !ok {
+					if ext := strings.ToLower(path.Ext(l.Target)); ext != "" && ext != ".md" {
+						return "", false // An image or other file
+					}
+					embed.Status = embedUnresolved
+					r.embeds = append(r.embeds, embed)
+					return "", false
+				}
+				embed.Path = target + ".md"
+
+				// A note or section embedded in itself is a cycle
+				key := target + strings.ToLower(l.Fragment)
+				content, err := r.content(target)
+				section, sectionLine, found := noteSection(content, l.Fragment)
+				switch {
+				case stack[key]:
+					embed.Status = embedCycle
+				case depth >= r.maxDepth:
+					embed.Status = embedTooDeep
+				case err != nil:
+					embed.Status = embedUnresolved
+				case !found:
+					embed.Status = embedMissingSection
+				default:
+					embed.Status = embedExpanded
+				}
+				r.embeds = append(r.embeds, embed)
+				if embed.Status != embedExpanded {
+					return "", false
+				}
+
+				if _, isBlock := fragmentTarget(l.Fragment); isBlock {
+					section = blockIDRegex.ReplaceAllString(section, "")
+				}
+				stack[key] = true
+				defer delete(stack, key)
+				expanded := strings.Trim(r.expand(target, section, sectionLine, depth+1, stack), "\n")
+				if !strings.Contains(expanded, "\n") && !strings.HasPrefix(expanded, "#") {
+					return expanded, true
+				}
+				// Several lines or a heading can't stay inside a line: give
+				// them their own block
+				if l.Start > 0 && piece[l.Start-1] != '\n' || l.Start == 0 && afterCode {
+					expanded = "\n\n" + expanded
+				}
+				if l.End < len(piece) && piece[l.End] != '\n' || l.End == len(piece) && beforeCode {
+					expanded += "\n\n"
+				}
+				return expanded, true
+			})
+			return out
+		})
+	})
+}
+
+// showFragment converts a --section value ("## Heading", "Heading",
+// "^block") to a link fragment.
+func showFragment(section string) string {
+	section = strings.TrimSpace(section)
+	if strings.HasPrefix(section, "^") || strings.HasPrefix(section, "#^") {
+		return "#" + strings.TrimPrefix(section, "#")
+	}
+	return "#" + strings.TrimSpace(strings.TrimLeft(section, "#"))
+}
+
+func runNoteShow(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	notePath, err := findNoteFile(absPath, args[0])
+	if err != nil {
+		return err
+	}
+	if !isPathWithinVault(notePath, absPath) {
+		return fmt.Errorf("note path escapes vault boundary: %s", args[0])
+	}
+	note, err := loadNote(absPath, notePath)
+	if err != nil {
+		return err
+	}
+	p := strings.TrimSuffix(filepath.ToSlash(note.RelPath), ".md")
+
+	// The frontmatter is kept as is unless only the body is wanted
+	head := note.Content[:len(note.Content)-len(note.Body)]
+	text, firstLine := note.Body, note.BodyLine
+	if noteShowSection != "" {
+		section, line, ok := noteSection(note.Content, showFragment(noteShowSection))
+		if !ok {
+			return fmt.Errorf("section not found in %s: %s", note.RelPath, noteShowSection)
+		}
+		head, text, firstLine = "", section+"\n", line
+	}
+
+	r, err := newEmbedRenderer(absPath, noteShowDepth)
+	if err != nil {
+		return err
+	}
+	if !noteShowRaw {
+		key := p
+		if noteShowSection != "" {
+			key += strings.ToLower(showFragment(noteShowSection))
+		}
+		text = r.expand(p, text, firstLine, 0, map[string]bool{key: true})
+	}
+
+	if noteShowFormat == "json" {
+		result := NoteShowResult{Path: filepath.ToSlash(note.RelPath), Body: text, Embeds: r.embeds}
+		if note.HasFrontmatter && !noteShowNoFrontmatter {
+			result.Frontmatter = note.Frontmatter.Map()
+		}
+		return encodeJSON(cmd, result)
+	}
+
+	if noteShowNoFrontmatter {
+		head = ""
+	}
+	fmt.Fprint(cmd.OutOrStdout(), head+text)
+	for _, e := range r.embeds {
+		if e.Status != embedExpanded {
+			fmt.Fprintf(os.Stderr, "%s %s:%d: %s not expanded (%s)\n", colors.Yellow("!"), e.Source, e.Line, e.Embed, e.Status)
+		}
+	}
+	return nil
+}
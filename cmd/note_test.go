@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestReplaceSection tests that subsections are replaced and the blank line
+// before the next heading is kept
+func TestReplaceSection(t *testing.T) {
+	content := "# P\n\n## Status\nold\n### Sub\nx\n\n## Log\n- a\n"
+	got, found := replaceSection(content, "## Status", "new\n")
+	if want := "# P\n\n## Status\nnew\n\n## Log\n- a\n"; got != want || !found {
+		t.Errorf("replaceSection = %q, %v; want %q", got, found, want)
+	}
+	if _, found := replaceSection(content, "### Status", "new"); found {
+		t.Error("replaceSection matched a heading of another level")
+	}
+}
+
+// TestEditNoteKeepsLineEndingsAndMode tests that an edited note keeps its
+// CRLF line endings and file mode
+func TestEditNoteKeepsLineEndingsAndMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Note.md")
+	if err := os.WriteFile(path, []byte("# Note\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, journalID, err := editNote(dir, "Note.md", "note-append", "append", false, func(content string) (string, error) {
+		return content + "text\n", nil
+	})
+	if err != nil || journalID == "" {
+		t.Fatalf("editNote: %v (journal %q)", err, journalID)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "# Note\r\ntext\r\n"; string(data) != want {
+		t.Errorf("content = %q, want %q", data, want)
+	}
+
+	// Mixed endings: only the new lines take the usual one
+	mixed := "# A\r\nx\r\n\n# B\n"
+	if got, want := restoreLineEndings(mixed, "# A\nx\nnew\n\n# B\n"), "# A\r\nx\r\nnew\r\n\n# B\n"; got != want {
+		t.Errorf("restoreLineEndings = %q, want %q", got, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+// TestEmbedRendererExpand tests nested embeds, sections, blocks and cycles
+func TestEmbedRendererExpand(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"A.md": "---\nx: 1\n---\nA ![[B]]\n",
+		"B.md": "B ![[A]] ![[C#^id]]\n",
+		"C.md": "# C\n## Part\npart\n\nline ^id\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, err := newEmbedRenderer(dir, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := r.expand("A", "A ![[B]] ![[C#Part]]\n", 4, 0, map[string]bool{"A": true})
+	if want := "A B ![[A]] line \n\n## Part\npart\n\nline ^id\n"; got != want {
+		t.Errorf("expand = %q, want %q", got, want)
+	}
+	if len(r.embeds) != 4 || r.embeds[1].Status != embedCycle {
+		t.Errorf("embeds = %+v, want the embed of A reported as a cycle", r.embeds)
+	}
+
+	// Sections that span lines get their own block, single lines stay inline
+	for text, want := range map[string]string{
+		"![[C#Part]]\n":           "## Part\npart\n\nline ^id\n",
+		"x ![[C#Part]] y\n":       "x \n\n## Part\npart\n\nline ^id\n\n y\n",
+		"`a` ![[C#Part]]`b`\n":    "`a` \n\n## Part\npart\n\nline ^id\n\n`b`\n",
+		"x ![[C#^id]] y\n":        "x line y\n",
+		"```\n![[C#Part]]\n```\n": "```\n![[C#Part]]\n```\n",
+	} {
+		if got := r.expand("A", text, 1, 0, map[string]bool{}); got != want {
+			t.Errorf("expand(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// noteFile is a markdown note loaded into memory with its frontmatter parsed.
+type noteFile struct {
+	Path           string // Absolute path
+	RelPath        string // Path relative to vault root
+	Name           string // Basename without .md
+	Content        string
+	Body           string // Content after frontmatter
+	BodyLine       int    // 1-based line number where Body starts
+	Frontmatter    *frontmatter
+	HasFrontmatter bool
+	Size           int64
+}
+
+// loadNote reads and parses a single markdown note.
+func loadNote(absPath, path string) (*noteFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseNote(absPath, path, string(data)), nil
+}
+
+// parseNote builds a noteFile from content already in memory.
+func parseNote(absPath, path, content string) *noteFile {
+	relPath := mustRelPath(absPath, path)
+	note := &noteFile{
+		Path:     path,
+		RelPath:  relPath,
+		Name:     strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Content:  content,
+		Body:     content,
+		BodyLine: 1,
+		Size:     int64(len(content)),
+	}
+
+	if fm, body, ok := splitFrontmatter(content); ok {
+		note.HasFrontmatter = true
+		note.Frontmatter = parseFrontmatter(fm)
+		note.Body = body
+		note.BodyLine = strings.Count(content[:len(content)-len(body)], "\n") + 1
+	} else {
+		note.Frontmatter = &frontmatter{}
+	}
+	return note
+}
+
+// loadNotes reads every markdown note in the vault. Unreadable files are skipped.
+func loadNotes(absPath string) ([]*noteFile, error) {
+	mdFiles, err := collectMarkdownFiles(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]*noteFile, 0, len(mdFiles))
+	for _, path := range mdFiles {
+		// Security: Verify file is within vault before reading
+		if !isPathWithinVault(path, absPath) {
+			continue
+		}
+		note, err := loadNote(absPath, path)
+		if err != nil {
+			continue
+		}
+		notes = append(notes, note)
+	}
+	return notes, nil
+}
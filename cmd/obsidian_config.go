@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// obsidianAppSettings holds the settings from Obsidian's .obsidian/app.json
+// that affect how notes and links are written.
+type obsidianAppSettings struct {
+	NewLinkFormat        string `json:"newLinkFormat"`        // "shortest", "relative" or "absolute"
+	UseMarkdownLinks     bool   `json:"useMarkdownLinks"`     // Markdown links instead of wikilinks
+	AttachmentFolderPath string `json:"attachmentFolderPath"` // "/", "./", "./sub" or a vault folder
+	NewFileLocation      string `json:"newFileLocation"`      // "root", "current" or "folder"
+	NewFileFolderPath    string `json:"newFileFolderPath"`    // Folder for new notes with "folder"
+}
+
+// loadObsidianAppSettings reads .obsidian/app.json of the vault at absPath.
+// A missing file gives Obsidian's defaults.
+func loadObsidianAppSettings(absPath string) (*obsidianAppSettings, error) {
+	settings := &obsidianAppSettings{NewLinkFormat: "shortest", AttachmentFolderPath: "/"}
+	path := filepath.Join(absPath, ".obsidian", "app.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if settings.NewLinkFormat == "" {
+		settings.NewLinkFormat = "shortest"
+	}
+	return settings, nil
+}
+
+// obsidianTemplateSettings holds the settings of the core Templates plugin
+// (.obsidian/templates.json) and the Templater plugin.
+type obsidianTemplateSettings struct {
+	Folder     string `json:"folder"`
+	DateFormat string `json:"dateFormat"`
+	TimeFormat string `json:"timeFormat"`
+
+	TemplaterFolder string           // Templater's templates folder
+	FolderTemplates []folderTemplate // Templater's templates for new notes by folder
+}
+
+// folderTemplate is a Templater folder template: new notes in Folder are
+// created from Template.
+type folderTemplate struct {
+	Folder   string `json:"folder"`
+	Template string `json:"template"`
+}
+
+// loadObsidianTemplateSettings reads the Templates and Templater settings
+// of the vault at absPath. Missing files give empty settings.
+func loadObsidianTemplateSettings(absPath string) (*obsidianTemplateSettings, error) {
+	settings := &obsidianTemplateSettings{}
+	readJSON := func(rel string, v any) error {
+		path := filepath.Join(absPath, rel)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+		return nil
+	}
+	if err := readJSON(filepath.Join(".obsidian", "templates.json"), settings); err != nil {
+		return nil, err
+	}
+	var templater struct {
+		TemplatesFolder       string           `json:"templates_folder"`
+		EnableFolderTemplates *bool            `json:"enable_folder_templates"`
+		FolderTemplates       []folderTemplate `json:"folder_templates"`
+	}
+	if err := readJSON(filepath.Join(".obsidian", "plugins", "templater-obsidian", "data.json"), &templater); err != nil {
+		return nil, err
+	}
+	settings.TemplaterFolder = templater.TemplatesFolder
+	if templater.EnableFolderTemplates == nil || *templater.EnableFolderTemplates {
+		for _, ft := range templater.FolderTemplates {
+			if ft.Template != "" {
+				settings.FolderTemplates = append(settings.FolderTemplates, ft)
+			}
+		}
+	}
+	return settings, nil
+}
@@ -235,12 +235,22 @@ func runPatterns(cmd *cobra.Command, args []string) error {
 
 	// Handle user action logging
 	if patternLogAction != "" {
-		return logUserAction(patternEventID, patternLogAction, patternActionNotes)
+		eventID, err := logUserAction(patternsDir, patternEventID, patternLogAction, patternActionNotes)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Logged user_action for event %s\n", eventID)
+		return nil
 	}
 
 	// Handle outcome logging
 	if patternLogOutcome != "" {
-		return logOutcome(patternEventID, patternLogOutcome, patternOutcomeNotes)
+		eventID, err := logOutcome(patternsDir, patternEventID, patternLogOutcome, patternOutcomeNotes)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Logged outcome for event %s\n", eventID)
+		return nil
 	}
 
 	// Handle surfacing stats
@@ -1145,12 +1155,14 @@ func logSurfacingEvent(dir string, patternIDs []string, context, source string)
 	return eventID, nil
 }
 
-func logUserAction(eventID, action, notes string) error {
+// logUserAction records the user's response to a surfacing event in dir.
+// Returns the ID of the updated event.
+func logUserAction(dir, eventID, action, notes string) (string, error) {
 	if !validUserActions[action] {
-		return fmt.Errorf("invalid action '%s'. Valid: accept, reject, ignore, partial, defer", action)
+		return "", fmt.Errorf("invalid action '%s'. Valid: accept, reject, ignore, partial, defer", action)
 	}
 
-	eventsPath := getSurfacingEventsPath(patternsDir)
+	eventsPath := getSurfacingEventsPath(dir)
 	return updateSurfacingEvent(eventsPath, eventID, map[string]string{
 		"user_action":      action,
 		"action_timestamp": time.Now().Format(time.RFC3339),
@@ -1158,12 +1170,14 @@ func logUserAction(eventID, action, notes string) error {
 	}, "user_action")
 }
 
-func logOutcome(eventID, outcome, notes string) error {
+// logOutcome records the outcome of a surfacing event in dir. Returns the
+// ID of the updated event.
+func logOutcome(dir, eventID, outcome, notes string) (string, error) {
 	if !validOutcomes[outcome] {
-		return fmt.Errorf("invalid outcome '%s'. Valid: success, failure, partial, unknown", outcome)
+		return "", fmt.Errorf("invalid outcome '%s'. Valid: success, failure, partial, unknown", outcome)
 	}
 
-	eventsPath := getSurfacingEventsPath(patternsDir)
+	eventsPath := getSurfacingEventsPath(dir)
 	return updateSurfacingEvent(eventsPath, eventID, map[string]string{
 		"outcome":           outcome,
 		"outcome_timestamp": time.Now().Format(time.RFC3339),
@@ -1171,18 +1185,18 @@ func logOutcome(eventID, outcome, notes string) error {
 	}, "outcome")
 }
 
-func updateSurfacingEvent(eventsPath, eventID string, updates map[string]string, findLatestWithout string) error {
+func updateSurfacingEvent(eventsPath, eventID string, updates map[string]string, findLatestWithout string) (string, error) {
 	// Open file for read+write with exclusive lock for the entire operation
 	// O_CREATE prevents TOCTOU race if file is created between check and open
 	file, err := os.OpenFile(eventsPath, os.O_RDWR|os.O_CREATE, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to open events file: %w", err)
+		return "", fmt.Errorf("failed to open events file: %w", err)
 	}
 	defer file.Close()
 
 	// Exclusive lock for the entire read-modify-write operation
 	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
+		return "", fmt.Errorf("failed to acquire lock: %w", err)
 	}
 	defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
 
@@ -1205,11 +1219,11 @@ func updateSurfacingEvent(eventsPath, eventID string, updates map[string]string,
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to read events: %w", err)
+		return "", fmt.Errorf("failed to read events: %w", err)
 	}
 
 	if len(events) == 0 {
-		return fmt.Errorf("no surfacing events found")
+		return "", fmt.Errorf("no surfacing events found")
 	}
 
 	// Find target event
@@ -1236,7 +1250,7 @@ func updateSurfacingEvent(eventsPath, eventID string, updates map[string]string,
 	}
 
 	if targetIdx == -1 {
-		return fmt.Errorf("event '%s' not found", eventID)
+		return "", fmt.Errorf("event '%s' not found", eventID)
 	}
 
 	// Apply updates
@@ -1248,25 +1262,24 @@ func updateSurfacingEvent(eventsPath, eventID string, updates map[string]string,
 
 	// Truncate file and rewrite from beginning (same file descriptor, still locked)
 	if _, err := file.Seek(0, 0); err != nil {
-		return fmt.Errorf("failed to seek: %w", err)
+		return "", fmt.Errorf("failed to seek: %w", err)
 	}
 	if err := file.Truncate(0); err != nil {
-		return fmt.Errorf("failed to truncate: %w", err)
+		return "", fmt.Errorf("failed to truncate: %w", err)
 	}
 
 	for _, e := range events {
 		data, err := json.Marshal(e)
 		if err != nil {
-			return fmt.Errorf("failed to marshal event: %w", err)
+			return "", fmt.Errorf("failed to marshal event: %w", err)
 		}
 		if _, err := file.WriteString(string(data) + "\n"); err != nil {
-			return fmt.Errorf("failed to write event: %w", err)
+			return "", fmt.Errorf("failed to write event: %w", err)
 		}
 	}
 
-	foundEventID := events[targetIdx]["event_id"]
-	fmt.Printf("Logged %s for event %v\n", findLatestWithout, foundEventID)
-	return nil
+	foundEventID, _ := events[targetIdx]["event_id"].(string)
+	return foundEventID, nil
 }
 
 func showSurfacingStats(cmd *cobra.Command, days int) error {
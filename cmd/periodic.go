@@ -0,0 +1,507 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// notePeriod is a kind of periodic note: daily, weekly or monthly.
+type notePeriod struct {
+	Name          string // Command and config key: "daily"
+	Unit          string // "day"
+	DefaultFormat string // Moment format Obsidian uses when none is set
+	DefaultRange  int    // Periods listed by "list" without --range
+}
+
+var notePeriods = []notePeriod{
+	{Name: "daily", Unit: "day", DefaultFormat: "YYYY-MM-DD", DefaultRange: 30},
+	{Name: "weekly", Unit: "week", DefaultFormat: "gggg-[W]ww", DefaultRange: 12},
+	{Name: "monthly", Unit: "month", DefaultFormat: "YYYY-MM", DefaultRange: 12},
+}
+
+// periodicConfig is the folder, name format and template of a kind of
+// periodic note, from the Daily Notes or Periodic Notes plugin settings.
+type periodicConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Folder   string `json:"folder"`
+	Format   string `json:"format"`
+	Template string `json:"template"`
+	Source   string `json:"-"` // Settings file it came from, or "defaults"
+}
+
+// periodicOptions are the flags of a periodic note command.
+type periodicOptions struct {
+	Date    string
+	Offset  int
+	Format  string
+	Section string
+	Range   string
+}
+
+func init() {
+	for _, p := range notePeriods {
+		rootCmd.AddCommand(newPeriodicCmd(p))
+	}
+}
+
+// newPeriodicCmd builds the command for a kind of periodic note, with its
+// append and list subcommands.
+func newPeriodicCmd(p notePeriod) *cobra.Command {
+	opts := &periodicOptions{}
+	cmd := &cobra.Command{
+		Use:   p.Name,
+		Short: fmt.Sprintf("Create the %s note if missing and print its path", p.Name),
+		Long: fmt.Sprintf(`Creates the %[1]s note for today (or --date, moved by --offset %[2]ss) if
+it doesn't exist, and prints its path.
+
+The folder, file name format and template come from the Periodic Notes
+plugin (.obsidian/plugins/periodic-notes/data.json) when it is enabled
+for %[1]s notes, else from the core Daily Notes plugin
+(.obsidian/daily-notes.json) for daily notes, else Obsidian's default
+name format (%[3]s). Templates get {{title}}, {{date}}, {{time}},
+{{date:FORMAT}}, {{date+1d}}, {{yesterday}}, {{tomorrow}} and weekday
+names ({{monday:YYYY-MM-DD}}), with the date of the note.
+
+Examples:
+  obsidian-cli %[1]s --vault ~/notes
+  obsidian-cli %[1]s --offset -1 --format path --vault ~/notes
+  obsidian-cli %[1]s append "Shipped the release" --section "## Log" --vault ~/notes
+  obsidian-cli %[1]s list --range 2026-01-01..2026-03-31 --vault ~/notes`, p.Name, p.Unit, p.DefaultFormat),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPeriodic(cmd, p, opts)
+		},
+	}
+	cmd.PersistentFlags().StringVar(&opts.Date, "date", "", "Date of the note (YYYY-MM-DD); default today")
+	cmd.PersistentFlags().IntVar(&opts.Offset, "offset", 0, fmt.Sprintf("Move the date by this many %ss (-1 for the previous one)", p.Unit))
+	cmd.Flags().StringVar(&opts.Format, "format", "text", "Output format: text, json, path")
+
+	appendCmd := &cobra.Command{
+		Use:   "append <text>",
+		Short: fmt.Sprintf("Append text to the %s note", p.Name),
+		Long: fmt.Sprintf(`Appends text to the %[1]s note, creating the note first if needed. With
+--section the text goes at the end of that heading's section; a missing
+heading is added. Use "-" to read the text from stdin.
+
+Examples:
+  obsidian-cli %[1]s append "- [ ] Call Alex" --vault ~/notes
+  obsidian-cli %[1]s append "Deployed v2" --section "## Log" --vault ~/notes
+  git log -1 --format=%%s | obsidian-cli %[1]s append - --section Log --vault ~/notes`, p.Name),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPeriodicAppend(cmd, p, opts, args)
+		},
+	}
+	appendCmd.Flags().StringVar(&opts.Section, "section", "", `Heading to append under ("## Log" or "Log")`)
+	appendCmd.Flags().StringVar(&opts.Format, "format", "text", "Output format: text, json")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: fmt.Sprintf("List %s notes in a range, highlighting gaps", p.Name),
+		Long: fmt.Sprintf(`Lists the %[1]s notes of a range of %[2]ss and the %[2]ss without one.
+
+--range is START..END (YYYY-MM-DD; END defaults to the date of --date and
+--offset) or a number of %[2]ss ending there. The default is the last %[3]d.
+
+Examples:
+  obsidian-cli %[1]s list --vault ~/notes
+  obsidian-cli %[1]s list --range 2026-10-01..2026-10-31 --vault ~/notes
+  obsidian-cli %[1]s list --range 90 --format json --vault ~/notes`, p.Name, p.Unit, p.DefaultRange),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPeriodicList(cmd, p, opts)
+		},
+	}
+	listCmd.Flags().StringVar(&opts.Range, "range", "", fmt.Sprintf("START..END or a number of %ss", p.Unit))
+	listCmd.Flags().StringVar(&opts.Format, "format", "text", "Output format: text, json")
+
+	cmd.AddCommand(appendCmd, listCmd)
+	return cmd
+}
+
+// loadPeriodicConfig reads the settings of a kind of periodic note.
+func loadPeriodicConfig(absPath string, p notePeriod) (*periodicConfig, error) {
+	readJSON := func(rel string, v any) (bool, error) {
+		data, err := os.ReadFile(filepath.Join(absPath, rel))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return false, fmt.Errorf("invalid %s: %w", rel, err)
+		}
+		return true, nil
+	}
+
+	cfg := &periodicConfig{Source: "defaults"}
+	var plugins []string
+	if _, err := readJSON(filepath.Join(".obsidian", "community-plugins.json"), &plugins); err != nil {
+		return nil, err
+	}
+	pluginEnabled := false
+	for _, id := range plugins {
+		pluginEnabled = pluginEnabled || id == "periodic-notes"
+	}
+	settings := make(map[string]*periodicConfig)
+	dataPath := filepath.Join(".obsidian", "plugins", "periodic-notes", "data.json")
+	if ok, err := readJSON(dataPath, &settings); err != nil {
+		return nil, err
+	} else if ok && pluginEnabled && settings[p.Name] != nil && settings[p.Name].Enabled {
+		cfg = settings[p.Name]
+		cfg.Source = dataPath
+	} else if p.Name == "daily" {
+		dailyPath := filepath.Join(".obsidian", "daily-notes.json")
+		if ok, err := readJSON(dailyPath, cfg); err != nil {
+			return nil, err
+		} else if ok {
+			cfg.Source = dailyPath
+		}
+	}
+	if cfg.Format == "" {
+		cfg.Format = p.DefaultFormat
+	}
+	cfg.Folder = strings.Trim(filepath.ToSlash(cfg.Folder), "/")
+	return cfg, nil
+}
+
+// start returns the first day of the period containing t. Weeks start on
+// Sunday, or Monday when the name format uses ISO weeks (GGGG-[W]WW).
+func (p notePeriod) start(t time.Time, format string) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	switch p.Unit {
+	case "week":
+		weekStart := time.Sunday
+		if usesISOWeek(format) {
+			weekStart = time.Monday
+		}
+		return t.AddDate(0, 0, -((int(t.Weekday()) - int(weekStart) + 7) % 7))
+	case "month":
+		return t.AddDate(0, 0, 1-t.Day())
+	}
+	return t
+}
+
+// add moves t by n periods.
+func (p notePeriod) add(t time.Time, n int) time.Time {
+	switch p.Unit {
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	}
+	return t.AddDate(0, 0, n)
+}
+
+// Matches [literal] text in a moment format
+var momentLiteralRegex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// usesISOWeek reports whether a moment format has ISO week tokens
+// outside [literal] text.
+func usesISOWeek(format string) bool {
+	return strings.ContainsAny(momentLiteralRegex.ReplaceAllString(format, ""), "GW")
+}
+
+// periodicPath returns the vault-relative path of the note for date.
+func periodicPath(cfg *periodicConfig, date time.Time) string {
+	return path.Join(cfg.Folder, formatMoment(date, cfg.Format)) + ".md"
+}
+
+// periodicDate returns the start of the period selected by --date and
+// --offset.
+func periodicDate(p notePeriod, cfg *periodicConfig, opts *periodicOptions) (time.Time, error) {
+	date := time.Now()
+	if opts.Date != "" {
+		var err error
+		if date, err = time.ParseInLocation("2006-01-02", opts.Date, time.Local); err != nil {
+			return time.Time{}, fmt.Errorf("invalid --date %q: expected YYYY-MM-DD", opts.Date)
+		}
+	}
+	return p.add(p.start(date, cfg.Format), opts.Offset), nil
+}
+
+// PeriodicNote is a periodic note opened or created.
+type PeriodicNote struct {
+	Period       string `json:"period"`
+	Date         string `json:"date"`
+	Path         string `json:"path"`
+	AbsolutePath string `json:"absolute_path"`
+	Created      bool   `json:"created"`
+	Appended     bool   `json:"appended,omitempty"`
+	JournalID    string `json:"journal_id,omitempty"`
+}
+
+// openPeriodicNote returns the note for the period selected by opts and
+// its content, rendered from the template when the note doesn't exist.
+func openPeriodicNote(absPath string, p notePeriod, opts *periodicOptions) (*PeriodicNote, string, error) {
+	cfg, err := loadPeriodicConfig(absPath, p)
+	if err != nil {
+		return nil, "", err
+	}
+	date, err := periodicDate(p, cfg, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	rel := periodicPath(cfg, date)
+	full := filepath.Join(absPath, filepath.FromSlash(rel))
+	if !isPathWithinVault(full, absPath) {
+		return nil, "", fmt.Errorf("%s note path escapes vault boundary: %s", p.Name, rel)
+	}
+	note := &PeriodicNote{Period: p.Name, Date: date.Format("2006-01-02"), Path: rel, AbsolutePath: full}
+
+	data, err := os.ReadFile(full)
+	if err == nil {
+		return note, string(data), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, "", err
+	}
+	note.Created = true
+	content := ""
+	if cfg.Template != "" {
+		template, err := loadTemplate(absPath, cfg.Template)
+		if err != nil {
+			return nil, "", err
+		}
+		content = renderTemplate(template, templateVars{
+			Title:      pathBase(strings.TrimSuffix(rel, ".md")),
+			Date:       date,
+			Now:        time.Now(),
+			DateFormat: cfg.Format,
+		})
+	}
+	return note, content, nil
+}
+
+// writePeriodicNote writes a periodic note through a journal.
+func writePeriodicNote(absPath string, note *PeriodicNote, content, description string) (err error) {
+	j := newJournal(absPath, note.Period, description)
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+	note.JournalID = j.ID
+	return j.writeFile(filepath.FromSlash(note.Path), []byte(content), 0644)
+}
+
+func runPeriodic(cmd *cobra.Command, p notePeriod, opts *periodicOptions) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	note, content, err := openPeriodicNote(absPath, p, opts)
+	if err != nil {
+		return err
+	}
+	if note.Created {
+		if err := writePeriodicNote(absPath, note, content, "create "+note.Path); err != nil {
+			return err
+		}
+	}
+
+	switch opts.Format {
+	case "json":
+		return encodeJSON(cmd, note)
+	case "path":
+		fmt.Fprintln(cmd.OutOrStdout(), note.AbsolutePath)
+		return nil
+	}
+	if note.Created {
+		fmt.Printf("%s Created %s\n", colors.Green("✓"), note.Path)
+		fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n", colors.Dim("i"), note.JournalID)
+		return nil
+	}
+	fmt.Printf("%s %s %s\n", colors.Cyan("→"), note.Path, colors.Dim("(exists)"))
+	return nil
+}
+
+func runPeriodicAppend(cmd *cobra.Command, p notePeriod, opts *periodicOptions, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	text := strings.Join(args, " ")
+	if text == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		text = string(data)
+	}
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return fmt.Errorf("nothing to append")
+	}
+
+	note, content, err := openPeriodicNote(absPath, p, opts)
+	if err != nil {
+		return err
+	}
+	appendText := func(content string) (string, error) {
+		if opts.Section != "" {
+			content, _ = insertUnderHeading(content, opts.Section, text)
+			return content, nil
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + text + "\n", nil
+	}
+	note.Appended = true
+	if note.Created {
+		content, _ = appendText(content)
+		err = writePeriodicNote(absPath, note, content, "append to "+note.Path)
+	} else {
+		// Existing notes are edited like "note append": locked, replaced
+		// atomically and keeping their line endings
+		_, note.JournalID, err = editNote(absPath, filepath.FromSlash(note.Path), note.Period, "append to "+note.Path, false, appendText)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.Format == "json" {
+		return encodeJSON(cmd, note)
+	}
+	where := ""
+	if opts.Section != "" {
+		where = " under " + strings.TrimSpace(strings.TrimLeft(opts.Section, "#"))
+	}
+	verb := "Appended to"
+	if note.Created {
+		verb = "Created and appended to"
+	}
+	fmt.Printf("%s %s %s%s\n", colors.Green("✓"), verb, note.Path, where)
+	return nil
+}
+
+// PeriodicListEntry is a period of a list, with its note if one exists.
+type PeriodicListEntry struct {
+	Date   string `json:"date"`
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
+
+// PeriodicListResult is the result of a periodic note list.
+type PeriodicListResult struct {
+	Period  string              `json:"period"`
+	Folder  string              `json:"folder"`
+	Format  string              `json:"format"`
+	Start   string              `json:"start"`
+	End     string              `json:"end"`
+	Entries []PeriodicListEntry `json:"entries"`
+	Found   int                 `json:"found"`
+	Missing int                 `json:"missing"`
+}
+
+// parsePeriodicRange parses --range into its first and last period.
+func parsePeriodicRange(p notePeriod, cfg *periodicConfig, value string, end time.Time) (time.Time, time.Time, error) {
+	if value == "" {
+		value = strconv.Itoa(p.DefaultRange)
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 1 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --range %q: must be at least 1", value)
+		}
+		return p.add(end, 1-n), end, nil
+	}
+	from, to, ok := strings.Cut(value, "..")
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --range %q: expected START..END or a number", value)
+	}
+	start, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --range start %q: expected YYYY-MM-DD", from)
+	}
+	if to != "" {
+		if end, err = time.ParseInLocation("2006-01-02", to, time.Local); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --range end %q: expected YYYY-MM-DD", to)
+		}
+		end = p.start(end, cfg.Format)
+	}
+	start = p.start(start, cfg.Format)
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --range %q: start is after end", value)
+	}
+	return start, end, nil
+}
+
+func runPeriodicList(cmd *cobra.Command, p notePeriod, opts *periodicOptions) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadPeriodicConfig(absPath, p)
+	if err != nil {
+		return err
+	}
+	end, err := periodicDate(p, cfg, opts)
+	if err != nil {
+		return err
+	}
+	start, end, err := parsePeriodicRange(p, cfg, opts.Range, end)
+	if err != nil {
+		return err
+	}
+
+	result := &PeriodicListResult{Period: p.Name, Folder: cfg.Folder, Format: cfg.Format,
+		Start: start.Format("2006-01-02"), End: end.Format("2006-01-02"), Entries: []PeriodicListEntry{}}
+	for d := start; !d.After(end); d = p.add(d, 1) {
+		rel := periodicPath(cfg, d)
+		_, statErr := os.Stat(filepath.Join(absPath, filepath.FromSlash(rel)))
+		entry := PeriodicListEntry{Date: d.Format("2006-01-02"), Path: rel, Exists: statErr == nil}
+		if entry.Exists {
+			result.Found++
+		} else {
+			result.Missing++
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	if opts.Format == "json" {
+		return encodeJSON(cmd, result)
+	}
+	printScanHeader(fmt.Sprintf("%s notes %s to %s", strings.ToUpper(p.Name[:1])+p.Name[1:], result.Start, result.End))
+	// Runs of missing periods are collapsed into one line
+	for i := 0; i < len(result.Entries); i++ {
+		e := result.Entries[i]
+		if e.Exists {
+			fmt.Printf("  %s %s  %s\n", colors.Green("✓"), e.Date, e.Path)
+			continue
+		}
+		j := i
+		for j+1 < len(result.Entries) && !result.Entries[j+1].Exists {
+			j++
+		}
+		if j == i {
+			fmt.Printf("  %s %s  %s\n", colors.Red("✗"), e.Date, colors.Red("missing"))
+		} else {
+			fmt.Printf("  %s %s  %s\n", colors.Red("✗"), e.Date+" … "+result.Entries[j].Date,
+				colors.Red(fmt.Sprintf("%d %ss missing", j-i+1, p.Unit)))
+		}
+		i = j
+	}
+	fmt.Printf("\n  %d notes, %d missing %s\n\n", result.Found, result.Missing, colors.Dim("(config: "+cfg.Source+")"))
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFormatMoment tests moment.js tokens used in periodic note names
+func TestFormatMoment(t *testing.T) {
+	date := time.Date(2025, 12, 29, 9, 5, 0, 0, time.UTC) // Monday
+	for format, want := range map[string]string{
+		"YYYY-MM-DD":              "2025-12-29",
+		"gggg-[W]ww":              "2026-W01",
+		"GGGG-[W]WW":              "2026-W01",
+		"dddd, MMMM Do YYYY":      "Monday, December 29th 2025",
+		"YYYY/MM/[Daily] ddd h A": "2025/12/Daily Mon 9 AM",
+	} {
+		if got := formatMoment(date, format); got != want {
+			t.Errorf("formatMoment(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+// TestRenderTemplate tests date variables with formats and offsets
+func TestRenderTemplate(t *testing.T) {
+	date := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC) // Sunday
+	got := renderTemplate("{{title}} {{date}} {{date+1d:ddd}} {{yesterday}} {{monday:MM-DD}} {{other}}",
+		templateVars{Title: "T", Date: date, DateFormat: "YYYY-MM-DD"})
+	if want := "T 2026-10-18 Mon 2026-10-17 10-19 {{other}}"; got != want {
+		t.Errorf("renderTemplate = %q, want %q", got, want)
+	}
+}
+
+// TestInsertUnderHeading tests appending to a section and adding a missing one
+func TestInsertUnderHeading(t *testing.T) {
+	content := "# Day\n\n## Log\n- a\n\n## Notes\nn\n"
+	got, found := insertUnderHeading(content, "## Log", "- b")
+	if want := "# Day\n\n## Log\n- a\n- b\n\n## Notes\nn\n"; got != want || !found {
+		t.Errorf("insertUnderHeading = %q, %v; want %q", got, found, want)
+	}
+	got, found = insertUnderHeading(content, "Todo", "- c")
+	if want := content + "\n## Todo\n- c\n"; got != want || found {
+		t.Errorf("insertUnderHeading = %q, %v; want %q", got, found, want)
+	}
+}
@@ -62,6 +62,7 @@ type RenameResult struct {
 	FilesModified int            `json:"files_modified"`
 	LinksUpdated  int            `json:"links_updated"`
 	Executed      bool           `json:"executed"`
+	JournalID     string         `json:"journal_id,omitempty"`
 }
 
 func runRename(cmd *cobra.Command, args []string) error {
@@ -83,15 +84,50 @@ func runRename(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid vault path: %w", err)
 	}
 
+	result, sourceFile, destFile, err := planRename(absPath, oldName, newName)
+	if err != nil {
+		return err
+	}
+	result.Executed = !renameDryRun
+	elapsed := time.Since(start)
+
+	// JSON output mode
+	if renameFormat == "json" {
+		if !renameDryRun {
+			journalID, err := executeRename(absPath, sourceFile, destFile, result.Changes, oldName, newName, true)
+			result.JournalID = journalID
+			if err != nil {
+				return err
+			}
+		}
+		return encodeJSON(cmd, result)
+	}
+
+	// Text output mode
+	printRenamePreview(result, elapsed)
+
+	if renameDryRun {
+		fmt.Printf("  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
+		return nil
+	}
+
+	// Execute the rename
+	_, err = executeRename(absPath, sourceFile, destFile, result.Changes, oldName, newName, false)
+	return err
+}
+
+// planRename finds the note to rename and every backlink to update. Returns
+// the (unexecuted) result with the absolute source and destination paths.
+func planRename(absPath, oldName, newName string) (*RenameResult, string, string, error) {
 	// Validate input names
 	if strings.TrimSpace(oldName) == "" || strings.TrimSpace(newName) == "" {
-		return fmt.Errorf("note names cannot be empty")
+		return nil, "", "", fmt.Errorf("note names cannot be empty")
 	}
 
 	// Find the source file
 	sourceFile, err := findNoteFile(absPath, oldName)
 	if err != nil {
-		return err
+		return nil, "", "", err
 	}
 
 	// Determine destination path
@@ -99,31 +135,27 @@ func runRename(cmd *cobra.Command, args []string) error {
 
 	// Security: Validate destination is within vault boundary
 	if !isPathWithinVault(destFile, absPath) {
-		return fmt.Errorf("destination path escapes vault boundary: %s", newName)
+		return nil, "", "", fmt.Errorf("destination path escapes vault boundary: %s", newName)
 	}
 
 	// Check destination doesn't exist
 	if _, err := os.Stat(destFile); err == nil {
-		return fmt.Errorf("destination file already exists: %s", destFile)
+		return nil, "", "", fmt.Errorf("destination file already exists: %s", destFile)
 	}
 
 	// Find all backlinks
 	relSource, _ := filepath.Rel(absPath, sourceFile)
 	mdFiles, err := collectMarkdownFiles(absPath)
 	if err != nil {
-		return err
+		return nil, "", "", err
 	}
-
 	backlinks := findBacklinksForRename(absPath, mdFiles, oldName)
-	elapsed := time.Since(start)
 
-	// Prepare result
 	result := &RenameResult{
 		SourceFile:    relSource,
 		DestFile:      mustRelPath(absPath, destFile),
 		BacklinkCount: len(backlinks),
 		Changes:       []RenameChange{},
-		Executed:      !renameDryRun,
 	}
 
 	// Calculate all changes
@@ -140,27 +172,7 @@ func runRename(cmd *cobra.Command, args []string) error {
 	}
 	result.FilesModified = len(filesAffected)
 	result.LinksUpdated = len(result.Changes)
-
-	// JSON output mode
-	if renameFormat == "json" {
-		if !renameDryRun {
-			if err := executeRename(absPath, sourceFile, destFile, result.Changes, oldName, newName, true); err != nil {
-				return err
-			}
-		}
-		return encodeJSON(cmd, result)
-	}
-
-	// Text output mode
-	printRenamePreview(result, elapsed)
-
-	if renameDryRun {
-		fmt.Printf("  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
-		return nil
-	}
-
-	// Execute the rename
-	return executeRename(absPath, sourceFile, destFile, result.Changes, oldName, newName, false)
+	return result, sourceFile, destFile, nil
 }
 
 func computeDestPath(absPath, sourceFile, newName string) string {
@@ -321,14 +333,25 @@ func printRenamePreview(result *RenameResult, elapsed time.Duration) {
 
 // executeRename performs the actual file rename and backlink updates.
 // WARNING: This operation is not atomic. If a write fails mid-operation,
-// some files will have updated links while others won't. Always use --dry-run
-// first to preview changes, and ensure you have backups or version control.
+// some files will have updated links while others won't. Every change is
+// journaled (including partial runs), so it can be reverted with "undo".
 // When quiet is true, no console output is produced (for JSON mode).
-func executeRename(absPath, sourceFile, destFile string, changes []RenameChange, oldName, newName string, quiet bool) error {
+// Returns the journal ID.
+func executeRename(absPath, sourceFile, destFile string, changes []RenameChange, oldName, newName string, quiet bool) (journalID string, err error) {
 	if !quiet {
 		fmt.Printf("\n%s Executing rename...\n\n", colors.Cyan("=>"))
 	}
 
+	j := newJournal(absPath, "rename", fmt.Sprintf("rename %s -> %s", oldName, newName))
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+		if len(j.Steps) > 0 {
+			journalID = j.ID
+		}
+	}()
+
 	// Group changes by file to process each file only once
 	// This prevents data loss when a file has multiple backlinks to the renamed note
 	changesByFile := make(map[string]bool)
@@ -351,18 +374,18 @@ func executeRename(absPath, sourceFile, destFile string, changes []RenameChange,
 
 		content, err := os.ReadFile(fullPath)
 		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", file, err)
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
 		}
 
 		info, err := os.Stat(fullPath)
 		if err != nil {
-			return fmt.Errorf("failed to stat %s: %w", file, err)
+			return "", fmt.Errorf("failed to stat %s: %w", file, err)
 		}
 
 		newContent := computeNewLinkContent(string(content), oldName, newName)
 
-		if err := os.WriteFile(fullPath, []byte(newContent), info.Mode()); err != nil {
-			return fmt.Errorf("failed to write %s (NOTE: %d files already modified): %w", file, linksUpdated, err)
+		if err := j.writeFile(file, []byte(newContent), info.Mode()); err != nil {
+			return "", fmt.Errorf("%w (NOTE: %d files already modified, run undo to revert)", err, linksUpdated)
 		}
 		linksUpdated++
 	}
@@ -370,22 +393,19 @@ func executeRename(absPath, sourceFile, destFile string, changes []RenameChange,
 	// Security: Validate destination directory is within vault before creating
 	destDir := filepath.Dir(destFile)
 	if !isPathWithinVault(destDir, absPath) {
-		return fmt.Errorf("destination directory escapes vault boundary")
-	}
-
-	if err := os.MkdirAll(destDir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+		return "", fmt.Errorf("destination directory escapes vault boundary")
 	}
 
-	if err := os.Rename(sourceFile, destFile); err != nil {
-		return fmt.Errorf("failed to rename file: %w", err)
+	if err := j.move(mustRelPath(absPath, sourceFile), mustRelPath(absPath, destFile)); err != nil {
+		return "", fmt.Errorf("failed to rename file: %w", err)
 	}
 
 	if !quiet {
 		relDest, _ := filepath.Rel(absPath, destFile)
 		fmt.Printf("  %s Renamed: %s\n", colors.Green("✓"), relDest)
-		fmt.Printf("  %s Updated links in %d files\n\n", colors.Green("✓"), len(changesByFile))
+		fmt.Printf("  %s Updated links in %d files\n", colors.Green("✓"), len(changesByFile))
+		fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n\n", colors.Dim("i"), j.ID)
 	}
 
-	return nil
+	return j.ID, nil
 }
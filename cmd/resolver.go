@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// noteIndex resolves wikilink targets to notes the way Obsidian does:
+// a vault-relative path match first, then a basename match, both
+// case-insensitive. Paths are stored slash-separated without .md.
+type noteIndex struct {
+	paths  []string            // All note paths (slash-separated, no .md), sorted
+	byPath map[string]string   // Lowercase path -> path
+	byName map[string][]string // Lowercase basename -> paths
+}
+
+// newNoteIndex builds an index from vault-relative note paths.
+func newNoteIndex(relPaths []string) *noteIndex {
+	ix := &noteIndex{
+		byPath: make(map[string]string),
+		byName: make(map[string][]string),
+	}
+	for _, rel := range relPaths {
+		p := strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+		ix.paths = append(ix.paths, p)
+		ix.byPath[strings.ToLower(p)] = p
+		name := strings.ToLower(pathBase(p))
+		ix.byName[name] = append(ix.byName[name], p)
+	}
+	sort.Strings(ix.paths)
+	for _, paths := range ix.byName {
+		sortByDepth(paths)
+	}
+	return ix
+}
+
+// newNoteIndexFromNotes builds an index from loaded notes.
+func newNoteIndexFromNotes(notes []*noteFile) *noteIndex {
+	relPaths := make([]string, len(notes))
+	for i, n := range notes {
+		relPaths[i] = n.RelPath
+	}
+	return newNoteIndex(relPaths)
+}
+
+// resolve returns the note path (slash-separated, no .md) a link target points
+// to. When several notes share a basename, the shallowest path wins.
+func (ix *noteIndex) resolve(target string) (string, bool) {
+	t := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(target), "/"), ".md"))
+	if t == "" {
+		return "", false
+	}
+	if p, ok := ix.byPath[t]; ok {
+		return p, true
+	}
+	if matches := ix.byName[pathBase(t)]; len(matches) > 0 && !strings.Contains(t, "/") {
+		return matches[0], true
+	}
+	// Partial paths ("sub/note") match any note whose path ends with them
+	if strings.Contains(t, "/") {
+		for _, p := range ix.byName[pathBase(t)] {
+			if strings.HasSuffix(strings.ToLower(p), "/"+t) {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ambiguous reports whether a bare basename matches more than one note.
+func (ix *noteIndex) ambiguous(name string) bool {
+	return len(ix.byName[strings.ToLower(name)]) > 1
+}
+
+// linkText returns the shortest link text that resolves to path: the
+// basename when unique, otherwise the full vault-relative path.
+func (ix *noteIndex) linkText(path string) string {
+	base := pathBase(path)
+	if len(ix.byName[strings.ToLower(base)]) <= 1 {
+		return base
+	}
+	return path
+}
+
+// pathBase returns the last element of a slash-separated path.
+func pathBase(p string) string {
+	if idx := strings.LastIndex(p, "/"); idx != -1 {
+		return p[idx+1:]
+	}
+	return p
+}
+
+// sortByDepth orders paths by folder depth, then alphabetically.
+func sortByDepth(paths []string) {
+	sort.Slice(paths, func(i, j int) bool {
+		di, dj := strings.Count(paths[i], "/"), strings.Count(paths[j], "/")
+		if di != dj {
+			return di < dj
+		}
+		return paths[i] < paths[j]
+	})
+}
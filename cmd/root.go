@@ -7,7 +7,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var vaultPath string
+var (
+	vaultPath  string
+	configPath string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "obsidian-cli",
@@ -33,6 +36,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&vaultPath, "vault", "v", "", "Path to Obsidian vault (required for most commands)")
 	// Note: vault is not globally required because the 'patterns' command doesn't need it.
 	// Commands that need vault should validate it in their RunE function.
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to config file (default: <vault>/"+defaultConfigName+")")
 }
 
 // RequireVault validates that the vault flag was provided.
@@ -0,0 +1,553 @@
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr     string
+	serveToken    string
+	serveReadOnly bool
+)
+
+// serveTokenEnv is read when --token isn't given.
+const serveTokenEnv = "OBSIDIAN_CLI_TOKEN"
+
+// Largest request body accepted (note content)
+const maxServeBody = 10 << 20
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a local HTTP/JSON API for the vault",
+	Long: `Starts a localhost HTTP server with JSON endpoints for vault operations,
+backed by an in-memory index that is kept up to date as files change.
+
+Every request must send the token as "Authorization: Bearer <token>". The
+token comes from --token or $OBSIDIAN_CLI_TOKEN; if neither is set, a
+random token is generated and printed at startup.
+
+Endpoints:
+  GET  /api/status                     Note, dead link and orphan counts
+  GET  /api/search?q=...               Full-text search (regex, case_sensitive, folder, context, limit)
+  GET  /api/backlinks?note=...         Notes linking to a note
+  GET  /api/links?note=...             Outgoing links of a note (valid and dead)
+  GET  /api/tags[?tag=...]             Tag counts, or the notes with a tag
+  GET  /api/orphans                    Notes nothing links to (folder, limit)
+  GET  /api/deadlinks                  Broken links (limit)
+  GET  /api/note?note=...              Note content, frontmatter, tags and links
+  PUT  /api/note?path=...              Create or overwrite a note
+  POST /api/rename                     Rename a note and update backlinks
+
+Writes are journaled (revert with: obsidian-cli undo). Use --read-only to
+reject them.
+
+Examples:
+  obsidian-cli serve --vault ~/Documents/Obsidian
+  obsidian-cli serve --vault ~/Documents/Obsidian --addr 127.0.0.1:9000 --read-only
+  curl -H "Authorization: Bearer $TOKEN" "http://127.0.0.1:7777/api/backlinks?note=my-note"`,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:7777", "Address to listen on (loopback only)")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "API token (default: $"+serveTokenEnv+" or generated)")
+	serveCmd.Flags().BoolVar(&serveReadOnly, "read-only", false, "Reject note writes and renames")
+}
+
+// vaultServer serves the HTTP API over a shared vault index. Reads go
+// straight to the index; writes are serialized by writeMu so a rename
+// plans and executes against a consistent vault.
+type vaultServer struct {
+	ix       *vaultIndex
+	absPath  string
+	token    string
+	readOnly bool
+	writeMu  sync.Mutex
+}
+
+// NoteData is a note as returned by the API.
+type NoteData struct {
+	Path        string         `json:"path"`
+	Content     string         `json:"content"`
+	Hash        string         `json:"hash"` // SHA-256 of the content, for conditional writes
+	ModTime     string         `json:"mod_time"`
+	Frontmatter map[string]any `json:"frontmatter"`
+	Tags        []string       `json:"tags"`
+	Aliases     []string       `json:"aliases"`
+	Links       []indexedLink  `json:"links"`
+}
+
+// NoteWrite is the body of PUT /api/note.
+type NoteWrite struct {
+	Content string `json:"content"`
+	// Hash of the content the client last read. When set, the write fails
+	// with 409 if the note changed since; "" for a note that must not exist.
+	BaseHash *string `json:"base_hash,omitempty"`
+}
+
+// NoteWriteResult is the response of PUT /api/note.
+type NoteWriteResult struct {
+	Path      string `json:"path"`
+	Hash      string `json:"hash"`
+	Created   bool   `json:"created"`
+	JournalID string `json:"journal_id,omitempty"`
+}
+
+// RenameRequest is the body of POST /api/rename.
+type RenameRequest struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// apiError is an error with an HTTP status.
+type apiError struct {
+	status int
+	msg    string
+}
+
+func (e *apiError) Error() string { return e.msg }
+
+func badRequest(format string, args ...any) error {
+	return &apiError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+	if err := checkLoopbackAddr(serveAddr); err != nil {
+		return err
+	}
+
+	token, generated := serveToken, false
+	if token == "" {
+		token = os.Getenv(serveTokenEnv)
+	}
+	if token == "" {
+		token, generated = randomHex(16), true
+	}
+
+	printScanHeader("Indexing vault")
+	start := time.Now()
+	ix, err := newVaultIndex(absPath)
+	if err != nil {
+		return err
+	}
+	notes, dead, orphans := ix.counts()
+
+	s := &vaultServer{ix: ix, absPath: absPath, token: token, readOnly: serveReadOnly}
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", serveAddr, err)
+	}
+	srv := &http.Server{Handler: s.routes(), ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Keep the index warm while serving
+	watchErr := make(chan error, 1)
+	go func() { watchErr <- watchVault(ctx, ix, nil, nil) }()
+
+	fmt.Printf("%s Serving %s %s\n\n", colors.Green("→"), colors.Cyan("http://"+listener.Addr().String()),
+		colors.Dim(fmt.Sprintf("(%d notes, %d dead links, %d orphans, indexed in %s)", notes, dead, orphans, time.Since(start).Round(time.Millisecond))))
+	if generated {
+		fmt.Printf("  Token: %s\n", colors.Yellow(token))
+	}
+	if serveReadOnly {
+		fmt.Printf("  %s Read-only: writes and renames are rejected\n", colors.Dim("i"))
+	}
+	fmt.Printf("  %s Press Ctrl+C to stop\n\n", colors.Dim("i"))
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(listener) }()
+
+	select {
+	case <-ctx.Done():
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	case err := <-watchErr:
+		if err != nil {
+			_ = srv.Close()
+			return err
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// checkLoopbackAddr rejects listen addresses reachable from other machines.
+func checkLoopbackAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("refusing to listen on %s: only loopback addresses (127.0.0.1, ::1, localhost) are allowed", addr)
+}
+
+func (s *vaultServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/status", s.handle(s.status))
+	mux.HandleFunc("GET /api/search", s.handle(s.search))
+	mux.HandleFunc("GET /api/backlinks", s.handle(s.backlinks))
+	mux.HandleFunc("GET /api/links", s.handle(s.links))
+	mux.HandleFunc("GET /api/tags", s.handle(s.tags))
+	mux.HandleFunc("GET /api/orphans", s.handle(s.orphans))
+	mux.HandleFunc("GET /api/deadlinks", s.handle(s.deadLinks))
+	mux.HandleFunc("GET /api/note", s.handle(s.readNote))
+	mux.HandleFunc("PUT /api/note", s.handle(s.writable(s.writeNote)))
+	mux.HandleFunc("POST /api/rename", s.handle(s.rename))
+	return s.authenticate(mux)
+}
+
+// authenticate requires the bearer token on every request.
+func (s *vaultServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			writeAPIJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handle adapts an endpoint returning a value or error to an http.HandlerFunc.
+func (s *vaultServer) handle(fn func(r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxServeBody)
+		v, err := fn(r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var apiErr *apiError
+			if errors.As(err, &apiErr) {
+				status = apiErr.status
+			}
+			writeAPIJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+		writeAPIJSON(w, http.StatusOK, v)
+	}
+}
+
+// writable rejects an endpoint in read-only mode.
+func (s *vaultServer) writable(fn func(r *http.Request) (any, error)) func(r *http.Request) (any, error) {
+	return func(r *http.Request) (any, error) {
+		if s.readOnly {
+			return nil, &apiError{http.StatusForbidden, "server is read-only"}
+		}
+		return fn(r)
+	}
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// queryInt reads an optional non-negative integer query parameter.
+func queryInt(r *http.Request, name string) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, badRequest("invalid %s: %q", name, v)
+	}
+	return n, nil
+}
+
+func queryBool(r *http.Request, name string) bool {
+	b, _ := strconv.ParseBool(r.URL.Query().Get(name))
+	return b
+}
+
+// findNote resolves the "note" query parameter.
+func (s *vaultServer) findNote(r *http.Request) (*indexedNote, error) {
+	name := r.URL.Query().Get("note")
+	if name == "" {
+		return nil, badRequest("missing note parameter")
+	}
+	note, err := s.ix.find(name)
+	if err != nil {
+		status := http.StatusNotFound
+		if strings.HasPrefix(err.Error(), "ambiguous") {
+			status = http.StatusConflict
+		}
+		return nil, &apiError{status, err.Error()}
+	}
+	return note, nil
+}
+
+func (s *vaultServer) status(r *http.Request) (any, error) {
+	notes, dead, orphans := s.ix.counts()
+	return map[string]any{
+		"vault":      s.absPath,
+		"notes":      notes,
+		"dead_links": dead,
+		"orphans":    orphans,
+		"read_only":  s.readOnly,
+	}, nil
+}
+
+func (s *vaultServer) search(r *http.Request) (any, error) {
+	q := r.URL.Query()
+	query := q.Get("q")
+	if query == "" {
+		return nil, badRequest("missing q parameter")
+	}
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		return nil, err
+	}
+	contextLines, err := queryInt(r, "context")
+	if err != nil {
+		return nil, err
+	}
+
+	patternStr := query
+	if !queryBool(r, "regex") {
+		patternStr = regexp.QuoteMeta(query)
+	}
+	if !queryBool(r, "case_sensitive") {
+		patternStr = "(?i)" + patternStr
+	}
+	pattern, err := regexp.Compile(patternStr)
+	if err != nil {
+		return nil, badRequest("invalid regex pattern: %v", err)
+	}
+	if folder := q.Get("folder"); folder != "" && !isPathWithinVault(filepath.Join(s.absPath, folder), s.absPath) {
+		return nil, badRequest("folder path escapes vault boundary: %s", folder)
+	}
+
+	matches := s.ix.search(pattern, q.Get("folder"), contextLines)
+	if matches == nil {
+		matches = []SearchMatch{}
+	}
+	return &SearchResult{Query: query, Matches: applyLimit(matches, limit)}, nil
+}
+
+func (s *vaultServer) backlinks(r *http.Request) (any, error) {
+	note, err := s.findNote(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.ix.backlinkResults(note.RelPath), nil
+}
+
+func (s *vaultServer) links(r *http.Request) (any, error) {
+	note, err := s.findNote(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.ix.links(note), nil
+}
+
+func (s *vaultServer) tags(r *http.Request) (any, error) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		return nil, err
+	}
+
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		files := s.ix.notesWithTag(tag)
+		return TagInfo{Name: strings.ToLower(strings.TrimPrefix(tag, "#")), Count: len(files), Files: applyLimit(files, limit)}, nil
+	}
+	return applyLimit(s.ix.tagList(), limit), nil
+}
+
+func (s *vaultServer) orphans(r *http.Request) (any, error) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		return nil, err
+	}
+	orphans := s.ix.orphans()
+	if folder := r.URL.Query().Get("folder"); folder != "" {
+		orphans = filterByFolder(orphans, folder)
+	}
+	if orphans == nil {
+		orphans = []string{}
+	}
+	return applyLimit(orphans, limit), nil
+}
+
+func (s *vaultServer) deadLinks(r *http.Request) (any, error) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		return nil, err
+	}
+	dead := []jsonDeadLink{}
+	for _, dl := range s.ix.deadLinks() {
+		dead = append(dead, jsonDeadLink{Source: dl.SourceFile, Target: dl.Target, Line: dl.Line})
+	}
+	return applyLimit(dead, limit), nil
+}
+
+func (s *vaultServer) readNote(r *http.Request) (any, error) {
+	note, err := s.findNote(r)
+	if err != nil {
+		return nil, err
+	}
+	return newNoteData(note), nil
+}
+
+// newNoteData converts an indexed note for API output.
+func newNoteData(note *indexedNote) *NoteData {
+	data := &NoteData{
+		Path:        note.RelPath,
+		Content:     note.Content,
+		Hash:        note.Hash,
+		ModTime:     note.ModTime.Format(time.RFC3339),
+		Frontmatter: note.Frontmatter.Map(),
+		Tags:        note.Tags,
+		Aliases:     note.Aliases,
+		Links:       note.Links,
+	}
+	if data.Tags == nil {
+		data.Tags = []string{}
+	}
+	if data.Aliases == nil {
+		data.Aliases = []string{}
+	}
+	if data.Links == nil {
+		data.Links = []indexedLink{}
+	}
+	return data
+}
+
+func (s *vaultServer) writeNote(r *http.Request) (any, error) {
+	var req NoteWrite
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, badRequest("invalid request body: %v", err)
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return writeVaultNote(s.ix, r.URL.Query().Get("path"), req.Content, req.BaseHash)
+}
+
+// writeVaultNote creates or overwrites the note at a vault-relative path
+// through a journal. With baseHash set, the write is refused if the note's
+// current content doesn't match it ("" requires the note not to exist).
+func writeVaultNote(ix *vaultIndex, relPath, content string, baseHash *string) (*NoteWriteResult, error) {
+	relPath = filepath.Clean(filepath.FromSlash(strings.TrimPrefix(relPath, "/")))
+	if relPath == "." || relPath == "" {
+		return nil, badRequest("missing path parameter")
+	}
+	if !strings.HasSuffix(strings.ToLower(relPath), ".md") {
+		relPath += ".md"
+	}
+	fullPath := filepath.Join(ix.absPath, relPath)
+	if !isPathWithinVault(fullPath, ix.absPath) {
+		return nil, badRequest("path escapes vault boundary: %s", relPath)
+	}
+	if isHiddenPath(ix.absPath, fullPath) {
+		return nil, badRequest("path is inside a hidden folder: %s", relPath)
+	}
+
+	current, err := os.ReadFile(fullPath)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if baseHash != nil {
+		switch {
+		case *baseHash == "" && exists:
+			return nil, &apiError{http.StatusConflict, "note already exists: " + relPath}
+		case *baseHash != "" && (!exists || contentHash(current) != *baseHash):
+			return nil, &apiError{http.StatusConflict, "note changed since it was read: " + relPath}
+		}
+	}
+
+	result := &NoteWriteResult{Path: relPath, Hash: contentHash([]byte(content)), Created: !exists}
+	if exists && string(current) == content {
+		return result, nil
+	}
+
+	j := newJournal(ix.absPath, "write", "write "+relPath)
+	if err := j.writeFile(relPath, []byte(content), 0644); err != nil {
+		return nil, err
+	}
+	if err := j.save(); err != nil {
+		return nil, err
+	}
+	result.JournalID = j.ID
+	ix.update(relPath)
+	return result, nil
+}
+
+func (s *vaultServer) rename(r *http.Request) (any, error) {
+	var req RenameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, badRequest("invalid request body: %v", err)
+	}
+	if !req.DryRun && s.readOnly {
+		return nil, &apiError{http.StatusForbidden, "server is read-only (use dry_run)"}
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return renameVaultNote(s.ix, req.From, req.To, req.DryRun)
+}
+
+// renameVaultNote renames a note and updates its backlinks like the rename
+// command, then refreshes the affected notes in the index.
+func renameVaultNote(ix *vaultIndex, from, to string, dryRun bool) (*RenameResult, error) {
+	oldName := strings.TrimSuffix(from, ".md")
+	newName := strings.TrimSuffix(to, ".md")
+	result, sourceFile, destFile, err := planRename(ix.absPath, oldName, newName)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	result.Executed = true
+	result.JournalID, err = executeRename(ix.absPath, sourceFile, destFile, result.Changes, oldName, newName, true)
+	for _, c := range result.Changes {
+		ix.update(c.File)
+	}
+	ix.update(result.SourceFile)
+	ix.update(result.DestFile)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestServeAPI tests token auth, read-only mode and conditional note writes
+func TestServeAPI(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("[[b]]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ix, err := newVaultIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(s *vaultServer, method, url, token, body string) int {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		s.routes().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	s := &vaultServer{ix: ix, absPath: dir, token: "secret"}
+	ro := &vaultServer{ix: ix, absPath: dir, token: "secret", readOnly: true}
+
+	tests := []struct {
+		name   string
+		server *vaultServer
+		method string
+		url    string
+		token  string
+		body   string
+		want   int
+	}{
+		{"no token", s, "GET", "/api/status", "", "", http.StatusUnauthorized},
+		{"wrong token", s, "GET", "/api/status", "nope", "", http.StatusUnauthorized},
+		{"status", s, "GET", "/api/status", "secret", "", http.StatusOK},
+		{"unknown note", s, "GET", "/api/note?note=zzz", "secret", "", http.StatusNotFound},
+		{"read-only write", ro, "PUT", "/api/note?path=b", "secret", `{"content":"x"}`, http.StatusForbidden},
+		{"create", s, "PUT", "/api/note?path=b", "secret", `{"content":"x","base_hash":""}`, http.StatusOK},
+		{"create existing", s, "PUT", "/api/note?path=b", "secret", `{"content":"y","base_hash":""}`, http.StatusConflict},
+		{"stale hash", s, "PUT", "/api/note?path=b", "secret", `{"content":"y","base_hash":"abc"}`, http.StatusConflict},
+		{"escape", s, "PUT", "/api/note?path=../x", "secret", `{"content":"x"}`, http.StatusBadRequest},
+		{"read-only rename", ro, "POST", "/api/rename", "secret", `{"from":"a","to":"c"}`, http.StatusForbidden},
+		{"read-only dry run", ro, "POST", "/api/rename", "secret", `{"from":"a","to":"c","dry_run":true}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := do(tt.server, tt.method, tt.url, tt.token, tt.body); got != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.url, got, tt.want)
+			}
+		})
+	}
+
+	// The written note is indexed immediately and resolves the dead link
+	if ix.note("b.md") == nil {
+		t.Error("written note not in index")
+	}
+	if dead := ix.deadLinks(); len(dead) != 0 {
+		t.Errorf("dead links after write = %v, want none", dead)
+	}
+}
@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	splitLevel  int
+	splitFolder string
+	splitEmbed  bool
+	splitDryRun bool
+	splitFormat string
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split <note>",
+	Short: "Split a note into one note per heading",
+	Long: `Extracts every section of a note at a heading level into its own note,
+named after the heading, and replaces the section with a link to it
+(or an embed with --embed).
+
+  - A section runs from its heading to the next heading of the same or a
+    higher level; subheadings go with it. Text before the first section
+    stays in the note
+  - New notes are created next to the note, or in --folder, and get its
+    frontmatter except aliases, title, id and uid
+  - Links to a section ([[note#Heading]]) anywhere in the vault are
+    retargeted to the new note ([[Heading]]); links to subheadings and
+    blocks in a section keep their fragment ([[Heading#Sub]])
+
+Existing notes are never overwritten. Changes are journaled (revert with
+"undo").
+
+Examples:
+  obsidian-cli split "Projects/Apollo" --vault ~/notes --dry-run
+  obsidian-cli split Handbook --level 3 --folder Handbook --embed --vault ~/notes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSplit,
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+	splitCmd.Flags().IntVar(&splitLevel, "level", 2, "Heading level to split at (1-6)")
+	splitCmd.Flags().StringVar(&splitFolder, "folder", "", "Vault folder for the new notes (default: the note's folder)")
+	splitCmd.Flags().BoolVar(&splitEmbed, "embed", false, "Replace sections with embeds instead of links")
+	splitCmd.Flags().BoolVar(&splitDryRun, "dry-run", false, "Preview changes without modifying files")
+	splitCmd.Flags().StringVar(&splitFormat, "format", "text", "Output format: text, json")
+}
+
+// Frontmatter fields that identify a note and aren't copied to the notes
+// split from it
+var splitSkipFields = []string{"aliases", "alias", "title", "id", "uid"}
+
+// SplitNote is a note created by split.
+type SplitNote struct {
+	Path    string `json:"path"`
+	Heading string `json:"heading"`
+	Lines   int    `json:"lines"`
+}
+
+// SplitResult is the result of the split command.
+type SplitResult struct {
+	Source        string            `json:"source"`
+	Level         int               `json:"level"`
+	Notes         []SplitNote       `json:"notes"`
+	Backlinks     []LinkConvertFile `json:"backlinks"`
+	LinksUpdated  int               `json:"links_updated"`
+	FilesModified int               `json:"files_modified"`
+	Executed      bool              `json:"executed"`
+	JournalID     string            `json:"journal_id,omitempty"`
+}
+
+// splitSection is a section of the note being split.
+type splitSection struct {
+	heading    noteHeading
+	start, end int    // 0-based line range, without the blank lines that end it
+	path       string // New note, slash-separated without .md
+}
+
+// noteSplitter plans the split of one note.
+type noteSplitter struct {
+	source   string // Slash-separated without .md
+	headings []noteHeading
+	blocks   []noteBlock
+	sections []*splitSection
+	index    *noteIndex // Vault before the split
+	newIndex *noteIndex // Vault with the new notes
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	if splitLevel < 1 || splitLevel > 6 {
+		return fmt.Errorf("invalid --level %d (expected 1-6)", splitLevel)
+	}
+	start := time.Now()
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+	notePath, err := findNoteFile(absPath, args[0])
+	if err != nil {
+		return err
+	}
+	if !isPathWithinVault(notePath, absPath) {
+		return fmt.Errorf("note path escapes vault boundary: %s", args[0])
+	}
+	notes, err := loadNotes(absPath)
+	if err != nil {
+		return err
+	}
+	var source *noteFile
+	for _, n := range notes {
+		if n.Path == notePath {
+			source = n
+		}
+	}
+	if source == nil {
+		return fmt.Errorf("note not found: %s", args[0])
+	}
+
+	folder := path.Dir(filepath.ToSlash(source.RelPath))
+	if splitFolder != "" {
+		folder = strings.Trim(filepath.ToSlash(filepath.Clean(splitFolder)), "/")
+	}
+	s, err := newNoteSplitter(absPath, notes, source, splitLevel, folder)
+	if err != nil {
+		return err
+	}
+
+	result := &SplitResult{Source: filepath.ToSlash(source.RelPath), Level: splitLevel, Backlinks: []LinkConvertFile{}, Executed: !splitDryRun}
+	newNotes, sourceContent := s.split(source, splitEmbed)
+	for _, sec := range s.sections {
+		result.Notes = append(result.Notes, SplitNote{Path: sec.path + ".md", Heading: sec.heading.Text, Lines: sec.end - sec.heading.Line})
+	}
+
+	oldContent := map[string]string{source.RelPath: source.Content}
+	changed := map[string]string{source.RelPath: sourceContent}
+	for _, n := range notes {
+		if n == source {
+			continue
+		}
+		content, count := s.retargetNote(n)
+		if count == 0 {
+			continue
+		}
+		oldContent[n.RelPath] = n.Content
+		changed[n.RelPath] = content
+		result.Backlinks = append(result.Backlinks, LinkConvertFile{File: filepath.ToSlash(n.RelPath), Links: count})
+		result.LinksUpdated += count
+	}
+	result.FilesModified = len(changed)
+
+	if splitFormat == "json" {
+		if !splitDryRun {
+			if result.JournalID, err = writeSplit(absPath, result.Source, newNotes, changed); err != nil {
+				return err
+			}
+		}
+		return encodeJSON(cmd, result)
+	}
+
+	printSplit(result, oldContent, changed, time.Since(start))
+	if splitDryRun {
+		fmt.Printf("  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
+		return nil
+	}
+	journalID, err := writeSplit(absPath, result.Source, newNotes, changed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s Split into %d notes, updated %d links in %d files\n", colors.Green("✓"), len(newNotes), result.LinksUpdated, result.FilesModified-1)
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n\n", colors.Dim("i"), journalID)
+	return nil
+}
+
+// newNoteSplitter finds the sections of source at level and names their
+// notes in folder.
+func newNoteSplitter(absPath string, notes []*noteFile, source *noteFile, level int, folder string) (*noteSplitter, error) {
+	s := &noteSplitter{
+		source:   strings.TrimSuffix(filepath.ToSlash(source.RelPath), ".md"),
+		headings: parseHeadings(source.Content),
+		blocks:   parseBlocks(source.Content),
+		index:    newNoteIndexFromNotes(notes),
+	}
+	lines := strings.Split(source.Content, "\n")
+	taken := make(map[string]bool)
+	for i, h := range s.headings {
+		if h.Level != level {
+			continue
+		}
+		end := len(lines)
+		for _, next := range s.headings[i+1:] {
+			if next.Level <= level {
+				end = next.Line - 1
+				break
+			}
+		}
+		for end > h.Line && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+
+		name := sanitizeImportName(h.Text)
+		p := name
+		if folder != "" && folder != "." {
+			p = folder + "/" + name
+		}
+		if taken[strings.ToLower(p)] {
+			return nil, fmt.Errorf("two sections would both become %s.md; rename one heading", p)
+		}
+		taken[strings.ToLower(p)] = true
+		full := filepath.Join(absPath, filepath.FromSlash(p)+".md")
+		if !isPathWithinVault(full, absPath) {
+			return nil, fmt.Errorf("path escapes vault boundary: %s", p)
+		}
+		if _, err := os.Lstat(full); err == nil {
+			return nil, fmt.Errorf("note already exists: %s.md", p)
+		}
+		s.sections = append(s.sections, &splitSection{heading: h, start: h.Line - 1, end: end, path: p})
+	}
+	if len(s.sections) == 0 {
+		return nil, fmt.Errorf("%s has no level %d headings", source.RelPath, level)
+	}
+
+	relPaths := make([]string, 0, len(notes)+len(s.sections))
+	for _, n := range notes {
+		relPaths = append(relPaths, n.RelPath)
+	}
+	for _, sec := range s.sections {
+		relPaths = append(relPaths, sec.path+".md")
+	}
+	s.newIndex = newNoteIndex(relPaths)
+	return s, nil
+}
+
+// sectionAt returns the index of the section containing the 1-based line,
+// or -1.
+func (s *noteSplitter) sectionAt(line int) int {
+	for i, sec := range s.sections {
+		if line > sec.start && line <= sec.end {
+			return i
+		}
+	}
+	return -1
+}
+
+// locate returns the section a fragment of the source points into, and the
+// fragment to use in the new note: none for the section's heading. sec is
+// -1 when the fragment isn't in a section.
+func (s *noteSplitter) locate(fragment string) (sec int, newFragment string) {
+	name, isBlock := fragmentTarget(fragment)
+	if isBlock {
+		b, ok := findBlock(s.blocks, name)
+		if !ok {
+			return -1, ""
+		}
+		return s.sectionAt(b.Line), "#^" + b.ID
+	}
+	h, ok := findHeading(s.headings, name)
+	if !ok {
+		return -1, ""
+	}
+	sec = s.sectionAt(h.Line)
+	if sec != -1 && s.sections[sec].heading.Line == h.Line {
+		return sec, ""
+	}
+	return sec, "#" + name
+}
+
+// retarget rewrites a link to the source when it points into a section.
+// inSource reports whether the link is in the source, in section from
+// (-1 outside sections), where [[#Heading]] links to the source.
+func (s *noteSplitter) retarget(l wikilink, inSource bool, from int) (string, bool) {
+	if l.Target == "" {
+		if !inSource {
+			return "", false
+		}
+	} else if p, ok := s.index.resolve(l.Target); !ok || p != s.source {
+		return "", false
+	}
+	if l.Fragment == "" {
+		return "", false
+	}
+
+	sec, fragment := s.locate(l.Fragment)
+	switch {
+	case sec == -1 && l.Target == "" && from != -1:
+		// A same-note link moving out of the source
+		l.Target = s.newIndex.linkText(s.source)
+	case sec == -1 || (sec == from && l.Target == "" && fragment != ""):
+		return "", false
+	default:
+		l.Target, l.Fragment = s.newIndex.linkText(s.sections[sec].path), fragment
+		if sec == from && fragment != "" {
+			l.Target = ""
+		}
+	}
+	return l.String(), true
+}
+
+// rewriteLinks applies retarget to the links in the body of a note.
+// firstLine is the line the body starts on.
+func (s *noteSplitter) rewriteLinks(body string, firstLine int, inSource bool) (string, int) {
+	count := 0
+	out := mapOutsideFences(body, firstLine, func(chunk string, line int) string {
+		return mapOutsideInlineCode(chunk, line, func(piece string, line int) string {
+			out, n := rewriteWikilinks(piece, func(l wikilink) (string, bool) {
+				from := -1
+				if inSource {
+					from = s.sectionAt(line + lineNumberAt(piece, l.Start) - 1)
+				}
+				return s.retarget(l, inSource, from)
+			})
+			count += n
+			return out
+		})
+	})
+	return out, count
+}
+
+// retargetNote rewrites the links of another note into the sections.
+func (s *noteSplitter) retargetNote(n *noteFile) (string, int) {
+	body, count := s.rewriteLinks(n.Body, n.BodyLine, false)
+	return n.Content[:len(n.Content)-len(n.Body)] + body, count
+}
+
+// split returns the content of the new notes (by vault-relative path) and
+// the new content of the source, whose sections are replaced by links or
+// embeds.
+func (s *noteSplitter) split(source *noteFile, embed bool) (map[string]string, string) {
+	body, _ := s.rewriteLinks(source.Body, source.BodyLine, true)
+	lines := strings.Split(source.Content[:len(source.Content)-len(source.Body)]+body, "\n")
+
+	newNotes := make(map[string]string)
+	var out []string
+	last := 0
+	for _, sec := range s.sections {
+		out = append(out, lines[last:sec.start]...)
+		l := wikilink{Embed: embed, Target: s.newIndex.linkText(sec.path)}
+		out = append(out, l.String())
+		last = sec.end
+
+		fm := parseFrontmatter("")
+		if fmText, _, ok := splitFrontmatter(source.Content); ok {
+			fm = parseFrontmatter(fmText)
+		}
+		for _, key := range splitSkipFields {
+			fm.Delete(key)
+		}
+		text := strings.Trim(strings.Join(lines[sec.start+1:sec.end], "\n"), "\r\n") + "\n"
+		if len(fm.Fields) > 0 {
+			text = fm.Block() + text
+		}
+		newNotes[filepath.FromSlash(sec.path)+".md"] = text
+	}
+	out = append(out, lines[last:]...)
+	return newNotes, strings.Join(out, "\n")
+}
+
+// writeSplit creates the new notes and writes the changed ones through a
+// journal. Returns the journal ID.
+func writeSplit(absPath, source string, newNotes, changed map[string]string) (journalID string, err error) {
+	j := newJournal(absPath, "split", fmt.Sprintf("split %s into %d notes", source, len(newNotes)))
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	for _, file := range sortedKeys(newNotes) {
+		// Re-check right before writing; never overwrite
+		if _, err := os.Lstat(filepath.Join(absPath, file)); err == nil {
+			return j.ID, fmt.Errorf("note already exists: %s", filepath.ToSlash(file))
+		}
+		if err := j.writeFile(file, []byte(newNotes[file]), 0644); err != nil {
+			return j.ID, err
+		}
+	}
+	for _, file := range sortedKeys(changed) {
+		if err := j.writeFile(file, []byte(changed[file]), 0644); err != nil {
+			return j.ID, err
+		}
+	}
+	return j.ID, nil
+}
+
+func printSplit(result *SplitResult, oldContent, newContent map[string]string, elapsed time.Duration) {
+	title := "Split " + result.Source
+	if splitDryRun {
+		title += " (dry run)"
+	}
+	fmt.Printf("%s %s %s\n\n", colors.Green("→"), title, colors.Dim(fmt.Sprintf("(level %d headings)", result.Level)))
+
+	for _, n := range result.Notes {
+		fmt.Printf("    %s %s %s\n", colors.Green("+"), n.Path, colors.Dim(fmt.Sprintf("(%d lines)", n.Lines)))
+	}
+	fmt.Println()
+	if len(result.Backlinks) > 0 {
+		fmt.Printf("  %s\n", colors.Cyan("Backlinks retargeted:"))
+		for _, f := range result.Backlinks {
+			fmt.Printf("    %s %s\n", colors.Cyan(f.File), colors.Dim(fmt.Sprintf("(%d links)", f.Links)))
+			if splitDryRun {
+				file := filepath.FromSlash(f.File)
+				printDiff(diffLines(oldContent[file], newContent[file]), 0)
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Printf("  %s %s\n\n", colors.Cyan("Analyzed in:"), elapsed.Round(time.Millisecond))
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestNoteSplitter tests the new notes, the source's links and backlink
+// retargeting
+func TestNoteSplitter(t *testing.T) {
+	dir := t.TempDir()
+	source := parseNote(dir, filepath.Join(dir, "A.md"), "---\ntags: [x]\nid: 1\n---\n# A\nsee [[#Two]]\n\n## One\none [[#Two]]\n### Sub\ns\n\n## Two\ntwo\n")
+	other := parseNote(dir, filepath.Join(dir, "B.md"), "[[A#One]] [[A#Sub|sub]] [[A#A]]\n")
+	s, err := newNoteSplitter(dir, []*noteFile{source, other}, source, 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newNotes, content := s.split(source, false)
+	if want := "---\ntags: [x]\nid: 1\n---\n# A\nsee [[Two]]\n\n[[One]]\n\n[[Two]]\n"; content != want {
+		t.Errorf("source = %q, want %q", content, want)
+	}
+	if got, want := newNotes["One.md"], "---\ntags: [x]\n---\none [[Two]]\n### Sub\ns\n"; got != want {
+		t.Errorf("One.md = %q, want %q", got, want)
+	}
+	got, n := s.retargetNote(other)
+	if want := "[[One]] [[One#Sub|sub]] [[A#A]]\n"; got != want || n != 2 {
+		t.Errorf("retargetNote = %q, %d; want %q, 2", got, n, want)
+	}
+}
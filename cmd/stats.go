@@ -11,6 +11,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var statsFormat string
+
 var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show vault statistics",
@@ -21,13 +23,27 @@ Shows:
   - Breakdown by top-level folder
   - File type distribution
 
-Example:
-  obsidian-cli stats --vault ~/Documents/Obsidian`,
+Examples:
+  obsidian-cli stats --vault ~/Documents/Obsidian
+  obsidian-cli stats --vault ~/Documents/Obsidian --format json`,
 	RunE: runStats,
 }
 
 func init() {
 	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().StringVar(&statsFormat, "format", "text", "Output format: text, json")
+}
+
+// StatsResult holds vault statistics for JSON output.
+type StatsResult struct {
+	TotalFiles      int64            `json:"total_files"`
+	MarkdownFiles   int64            `json:"markdown_files"`
+	Directories     int64            `json:"directories"`
+	TopLevelFolders int              `json:"top_level_folders"`
+	ByFolder        map[string]int64 `json:"by_folder"`
+	Orphans         int              `json:"orphans"`
+	DeadLinks       int              `json:"dead_links"`
+	NoFrontmatter   int              `json:"no_frontmatter"`
 }
 
 func runStats(cmd *cobra.Command, args []string) error {
@@ -39,7 +55,9 @@ func runStats(cmd *cobra.Command, args []string) error {
 	bold := color.New(color.Bold).SprintFunc()
 	dim := color.New(color.Faint).SprintFunc()
 
-	fmt.Printf("\n%s Scanning vault: %s\n\n", cyan("=>"), vaultPath)
+	if statsFormat == "text" {
+		fmt.Printf("\n%s Scanning vault: %s\n\n", cyan("=>"), vaultPath)
+	}
 
 	start := time.Now()
 	result, err := vault.ScanVault(vaultPath)
@@ -48,6 +66,23 @@ func runStats(cmd *cobra.Command, args []string) error {
 	}
 	elapsed := time.Since(start)
 
+	switch statsFormat {
+	case "text":
+	case "json":
+		return encodeJSON(cmd, &StatsResult{
+			TotalFiles:      result.TotalFiles,
+			MarkdownFiles:   result.MarkdownFiles,
+			Directories:     result.Directories,
+			TopLevelFolders: len(result.FilesByFolder),
+			ByFolder:        result.FilesByFolder,
+			Orphans:         len(result.Orphans),
+			DeadLinks:       len(result.DeadLinks),
+			NoFrontmatter:   len(result.FrontmatterErrs),
+		})
+	default:
+		return fmt.Errorf("unknown format %q (valid: text, json)", statsFormat)
+	}
+
 	fmt.Printf("%s %s\n\n", "📊", bold("Vault Statistics"))
 
 	// Total notes
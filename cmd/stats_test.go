@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestStatsJSON tests the fields of stats --format json
+func TestStatsJSON(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"a.md": "[[b]] [[gone]]\n", "sub/b.md": "---\nx: 1\n---\nb\n", "sub/c.txt": "c"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldVault, oldFormat := vaultPath, statsFormat
+	defer func() { vaultPath, statsFormat = oldVault, oldFormat }()
+	vaultPath, statsFormat = dir, "json"
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	if err := runStats(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(out.Bytes(), &fields); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"by_folder", "dead_links", "directories", "markdown_files", "no_frontmatter", "orphans", "top_level_folders", "total_files"}
+	if got := sortedKeys(fields); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+	var result StatsResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.MarkdownFiles != 2 || result.DeadLinks != 1 || result.NoFrontmatter != 1 {
+		t.Errorf("stats = %+v", result)
+	}
+}
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kofifort/obsidian-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+// StubNote is a note created (or planned) for a dead link target.
+type StubNote struct {
+	Path    string   `json:"path"`    // Vault-relative path of the new note
+	Target  string   `json:"target"`  // Link target as most often written
+	Sources []string `json:"sources"` // Notes linking to the target
+	Links   int      `json:"links"`
+}
+
+// StubSkip is a dead link target no stub was created for.
+type StubSkip struct {
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+// StubResult holds the outcome of deadlinks --create-stubs.
+type StubResult struct {
+	Created   []StubNote `json:"created"`
+	Skipped   []StubSkip `json:"skipped"`
+	Executed  bool       `json:"executed"`
+	JournalID string     `json:"journal_id,omitempty"`
+}
+
+// Characters that can't appear in a note filename on common filesystems
+const invalidStubChars = `:*?"<>|\`
+
+// planStubs groups dead links by target (case-insensitively) and decides
+// where each stub goes: bare targets in folder, path-style targets at
+// their path from the vault root.
+func planStubs(absPath string, deadLinks []vault.DeadLink, folder string) ([]StubNote, []StubSkip) {
+	type group struct {
+		spellings map[string]int
+		sources   map[string]bool
+		links     int
+	}
+	groups := make(map[string]*group)
+	for _, dl := range deadLinks {
+		target := strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(dl.Target), "/"), ".md")
+		key := strings.ToLower(target)
+		g := groups[key]
+		if g == nil {
+			g = &group{spellings: make(map[string]int), sources: make(map[string]bool)}
+			groups[key] = g
+		}
+		g.spellings[target]++
+		g.sources[dl.SourceFile] = true
+		g.links++
+	}
+
+	var stubs []StubNote
+	var skipped []StubSkip
+	for _, key := range sortedKeys(groups) {
+		g := groups[key]
+
+		// Most used spelling wins; ties go to the first alphabetically
+		target, best := "", 0
+		for _, spelling := range sortedKeys(g.spellings) {
+			if g.spellings[spelling] > best {
+				target, best = spelling, g.spellings[spelling]
+			}
+		}
+
+		relPath, reason := stubPath(target, folder)
+		if reason == "" && !isPathWithinVault(filepath.Join(absPath, relPath), absPath) {
+			reason = "path escapes vault"
+		}
+		if reason == "" {
+			if _, err := os.Lstat(filepath.Join(absPath, relPath)); err == nil {
+				reason = "file already exists: " + relPath
+			}
+		}
+		if reason != "" {
+			skipped = append(skipped, StubSkip{Target: target, Reason: reason})
+			continue
+		}
+
+		stubs = append(stubs, StubNote{
+			Path:    relPath,
+			Target:  target,
+			Sources: sortedKeys(g.sources),
+			Links:   g.links,
+		})
+	}
+
+	// Two targets may map to the same file ([[a/b]] and [[A/B.md]] are
+	// merged above, but a bare [[b]] can also land on folder/b.md)
+	sort.SliceStable(stubs, func(i, j int) bool { return stubs[i].Path < stubs[j].Path })
+	var deduped []StubNote
+	for _, s := range stubs {
+		if n := len(deduped); n > 0 && strings.EqualFold(deduped[n-1].Path, s.Path) {
+			prev := &deduped[n-1]
+			prev.Links += s.Links
+			prev.Sources = sortedKeys(unionSets(stringSet(prev.Sources), stringSet(s.Sources)))
+			continue
+		}
+		deduped = append(deduped, s)
+	}
+	return deduped, skipped
+}
+
+// stubPath returns the vault-relative path for a stub, or a reason the
+// target can't become a note.
+func stubPath(target, folder string) (string, string) {
+	switch {
+	case target == "" || strings.HasSuffix(target, "/"):
+		return "", "folder link"
+	case strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:"):
+		return "", "external link"
+	case strings.ContainsAny(target, invalidStubChars):
+		return "", "invalid filename characters"
+	}
+	if ext := filepath.Ext(target); ext != "" && isAlphaExt(ext[1:]) {
+		return "", "attachment link (" + ext + ")"
+	}
+	for _, part := range strings.Split(target, "/") {
+		if part == "" || part == "." || part == ".." || strings.HasPrefix(part, ".") {
+			return "", "invalid path"
+		}
+	}
+
+	if strings.Contains(target, "/") {
+		return filepath.FromSlash(target) + ".md", ""
+	}
+	return filepath.Join(folder, target+".md"), ""
+}
+
+// isAlphaExt reports whether ext looks like a file extension (pdf, canvas)
+// rather than part of a note name (v1.2).
+func isAlphaExt(ext string) bool {
+	if len(ext) == 0 || len(ext) > 6 {
+		return false
+	}
+	for _, r := range ext {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func stringSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+// renderStub builds a stub note from the template (if any), adding
+// created_from links to the notes that reference it.
+func renderStub(template, title string, sourceLinks []string) string {
+	fm := &frontmatter{}
+	body := ""
+	if template != "" {
+		text := applyTemplateVars(template, title)
+		if fmText, rest, ok := splitFrontmatter(text); ok {
+			fm = parseFrontmatter(fmText)
+			body = rest
+		} else {
+			body = text
+		}
+	}
+
+	links := make([]string, len(sourceLinks))
+	for i, s := range sourceLinks {
+		links[i] = "[[" + s + "]]"
+	}
+	fm.SetList("created_from", links)
+	return fm.Block() + body
+}
+
+// createDeadLinkStubs creates a note for every dead link target.
+func createDeadLinkStubs(cmd *cobra.Command, deadLinks []vault.DeadLink) error {
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+	cfg, err := loadVaultConfig(absPath)
+	if err != nil {
+		return err
+	}
+
+	folder := cfg.Stubs.Folder
+	if cmd.Flags().Changed("stub-folder") {
+		folder = deadlinksStubFolder
+	}
+	templatePath := cfg.Stubs.Template
+	if cmd.Flags().Changed("stub-template") {
+		templatePath = deadlinksStubTemplate
+	}
+	if !isPathWithinVault(filepath.Join(absPath, folder), absPath) {
+		return fmt.Errorf("stub folder escapes vault boundary: %s", folder)
+	}
+
+	template := ""
+	if templatePath != "" {
+		if template, err = loadTemplate(absPath, templatePath); err != nil {
+			return err
+		}
+	}
+
+	mdFiles, err := collectMarkdownFiles(absPath)
+	if err != nil {
+		return err
+	}
+	relPaths := make([]string, len(mdFiles))
+	for i, f := range mdFiles {
+		relPaths[i] = mustRelPath(absPath, f)
+	}
+	ix := newNoteIndex(relPaths)
+
+	stubs, skipped := planStubs(absPath, deadLinks, folder)
+	result := &StubResult{
+		Created:  stubs,
+		Skipped:  skipped,
+		Executed: !deadlinksDryRun,
+	}
+	if result.Created == nil {
+		result.Created = []StubNote{}
+	}
+	if result.Skipped == nil {
+		result.Skipped = []StubSkip{}
+	}
+
+	contents := make(map[string]string, len(stubs))
+	for _, s := range stubs {
+		var sourceLinks []string
+		for _, src := range s.Sources {
+			sourceLinks = append(sourceLinks, ix.linkText(strings.TrimSuffix(filepath.ToSlash(src), ".md")))
+		}
+		contents[s.Path] = renderStub(template, pathBase(s.Target), sourceLinks)
+	}
+
+	if deadlinksFormat != "json" {
+		printStubPlan(result, contents)
+	}
+
+	if !deadlinksDryRun && len(stubs) > 0 {
+		result.JournalID, err = writeStubs(absPath, stubs, contents)
+		if err != nil {
+			return err
+		}
+	}
+
+	if deadlinksFormat == "json" {
+		return encodeJSON(cmd, result)
+	}
+	if len(stubs) == 0 {
+		return nil
+	}
+	if deadlinksDryRun {
+		fmt.Printf("  %s Run without --dry-run to execute\n\n", colors.Yellow("!"))
+		return nil
+	}
+	fmt.Printf("  %s Created %d notes\n", colors.Green("✓"), len(stubs))
+	fmt.Printf("  %s Journal: %s (revert with: obsidian-cli undo)\n\n", colors.Dim("i"), result.JournalID)
+	return nil
+}
+
+// writeStubs creates stub notes through a journal. Returns the journal ID.
+func writeStubs(absPath string, stubs []StubNote, contents map[string]string) (journalID string, err error) {
+	j := newJournal(absPath, "stubs", fmt.Sprintf("create %d stub notes", len(stubs)))
+	defer func() {
+		if saveErr := j.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}()
+
+	for _, s := range stubs {
+		// Re-check right before writing; never overwrite
+		if _, err := os.Lstat(filepath.Join(absPath, s.Path)); err == nil {
+			return j.ID, fmt.Errorf("file already exists: %s", s.Path)
+		}
+		if err := j.writeFile(s.Path, []byte(contents[s.Path]), 0644); err != nil {
+			return j.ID, err
+		}
+	}
+	return j.ID, nil
+}
+
+func printStubPlan(result *StubResult, contents map[string]string) {
+	title := "Stub Notes"
+	if deadlinksDryRun {
+		title += " (dry run)"
+	}
+	fmt.Printf("%s %s %s\n\n", colors.Green("→"), title, colors.Dim(fmt.Sprintf("(%d notes)", len(result.Created))))
+
+	if len(result.Created) == 0 {
+		fmt.Println("  No stubs to create.")
+	}
+	for _, s := range result.Created {
+		fmt.Printf("  %s %s %s\n", colors.Green("+"), s.Path,
+			colors.Dim(fmt.Sprintf("(%d links from %d notes)", s.Links, len(s.Sources))))
+		if deadlinksDryRun {
+			for _, line := range strings.Split(strings.TrimRight(contents[s.Path], "\n"), "\n") {
+				fmt.Printf("      %s\n", colors.Dim(truncateRunes(line, 76)))
+			}
+		}
+	}
+
+	if len(result.Skipped) > 0 {
+		fmt.Printf("\n  %s\n", colors.Yellow("Skipped:"))
+		for _, s := range result.Skipped {
+			fmt.Printf("    %s %s\n", colors.Red("[["+s.Target+"]]"), colors.Dim(s.Reason))
+		}
+	}
+	fmt.Println()
+}
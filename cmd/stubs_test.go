@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kofifort/obsidian-cli/internal/vault"
+)
+
+// TestPlanStubs tests case-insensitive grouping, path-style targets and
+// the skip reasons
+func TestPlanStubs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "Stubs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Stubs", "taken.md"), []byte("x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dead := func(source string, targets ...string) []vault.DeadLink {
+		var links []vault.DeadLink
+		for _, target := range targets {
+			links = append(links, vault.DeadLink{SourceFile: source, Target: target})
+		}
+		return links
+	}
+	var links []vault.DeadLink
+	links = append(links, dead("a.md", "Idea", "idea", "Projects/Plan", "/projects/plan.md", "Folder/", "https://x.y", "a:b", "doc.pdf", ".obsidian/x", "x/../y", "taken", "v1.2")...)
+	links = append(links, dead("b.md", "idea", "Stubs/Idea")...)
+
+	stubs, skipped := planStubs(dir, links, "Stubs")
+	want := []StubNote{
+		{Path: filepath.Join("Projects", "Plan.md"), Target: "Projects/Plan", Sources: []string{"a.md"}, Links: 2},
+		// [[idea]] lands on the same file as [[Stubs/Idea]]
+		{Path: filepath.Join("Stubs", "Idea.md"), Target: "Stubs/Idea", Sources: []string{"a.md", "b.md"}, Links: 4},
+		{Path: filepath.Join("Stubs", "v1.2.md"), Target: "v1.2", Sources: []string{"a.md"}, Links: 1},
+	}
+	if !reflect.DeepEqual(stubs, want) {
+		t.Errorf("stubs = %+v, want %+v", stubs, want)
+	}
+	wantSkipped := []StubSkip{
+		{Target: ".obsidian/x", Reason: "invalid path"},
+		{Target: "a:b", Reason: "invalid filename characters"},
+		{Target: "doc.pdf", Reason: "attachment link (.pdf)"},
+		{Target: "Folder/", Reason: "folder link"},
+		{Target: "https://x.y", Reason: "external link"},
+		{Target: "taken", Reason: "file already exists: " + filepath.Join("Stubs", "taken.md")},
+		{Target: "x/../y", Reason: "invalid path"},
+	}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("skipped = %+v, want %+v", skipped, wantSkipped)
+	}
+
+	// A stub folder outside the vault
+	_, skipped = planStubs(dir, dead("a.md", "Idea"), "../out")
+	if want := []StubSkip{{Target: "Idea", Reason: "path escapes vault"}}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %+v, want %+v", skipped, want)
+	}
+}
+
+// TestRenderStub tests created_from with and without a template
+func TestRenderStub(t *testing.T) {
+	if got, want := renderStub("", "Idea", []string{"a", "sub/b"}), "---\ncreated_from:\n  - \"[[a]]\"\n  - \"[[sub/b]]\"\n---\n"; got != want {
+		t.Errorf("renderStub = %q, want %q", got, want)
+	}
+	template := "---\ntags: [stub]\n---\n# {{title}}\n"
+	if got, want := renderStub(template, "Idea", []string{"a"}), "---\ntags: [stub]\ncreated_from:\n  - \"[[a]]\"\n---\n# Idea\n"; got != want {
+		t.Errorf("renderStub = %q, want %q", got, want)
+	}
+	if got, want := renderStub("# {{title}}\n", "Idea", []string{"a"}), "---\ncreated_from:\n  - \"[[a]]\"\n---\n# Idea\n"; got != want {
+		t.Errorf("renderStub = %q, want %q", got, want)
+	}
+}
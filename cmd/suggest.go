@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Suggestion scores by signal. A note can match on several signals; its
+// score is the strongest one.
+const (
+	scoreRenamed = 1.0  // Target was renamed to this note in git history
+	scoreAlias   = 0.95 // Target is one of the note's aliases
+	scoreTypo    = 0.9  // Scaled by name similarity
+	scoreTokens  = 0.8  // Scaled by word overlap
+
+	// A best suggestion is applied automatically only when it scores at
+	// least minConfidentScore and leads the runner-up by confidentMargin.
+	minConfidentScore = 0.75
+	confidentMargin   = 0.1
+)
+
+// linkSuggestion is an existing note proposed as the target of a dead link.
+type linkSuggestion struct {
+	Path    string   `json:"path"` // Vault-relative, without .md
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// linkSuggester ranks existing notes as replacements for dead link targets.
+type linkSuggester struct {
+	ix      *noteIndex
+	aliases map[string][]string // Lowercase alias -> note paths
+	renames map[string]gitRename
+}
+
+// gitRename is the latest rename of a note found in git history.
+type gitRename struct {
+	To     string // Path without .md
+	Commit string
+}
+
+// newLinkSuggester indexes notes and their aliases. With useGit, renames
+// are read from the history of the git repository containing the vault.
+func newLinkSuggester(absPath string, notes []*noteFile, useGit bool) *linkSuggester {
+	s := &linkSuggester{
+		ix:      newNoteIndexFromNotes(notes),
+		aliases: make(map[string][]string),
+		renames: make(map[string]gitRename),
+	}
+	for _, note := range notes {
+		path := s.ix.byPath[strings.ToLower(strings.TrimSuffix(filepath.ToSlash(note.RelPath), ".md"))]
+		for _, key := range []string{"aliases", "alias"} {
+			for _, alias := range note.Frontmatter.Values(key) {
+				if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+					s.aliases[alias] = append(s.aliases[alias], path)
+				}
+			}
+		}
+	}
+	if useGit {
+		s.renames = gitRenames(absPath)
+	}
+	return s
+}
+
+var nameTokenRegex = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// nameTokens returns the lowercase words of a note name.
+func nameTokens(name string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, t := range nameTokenRegex.FindAllString(strings.ToLower(name), -1) {
+		tokens[t] = true
+	}
+	return tokens
+}
+
+// suggest returns up to limit notes for a dead target, best first.
+func (s *linkSuggester) suggest(target string, limit int) []linkSuggestion {
+	target = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(target), "/"), ".md")
+	lower := strings.ToLower(target)
+	name := pathBase(lower)
+	nameLen := len([]rune(name))
+	maxDist := maxTypoDistance(name)
+	targetTokens := nameTokens(name)
+
+	byPath := make(map[string]*linkSuggestion)
+	add := func(path string, score float64, reason string) {
+		sg := byPath[path]
+		if sg == nil {
+			sg = &linkSuggestion{Path: path}
+			byPath[path] = sg
+		}
+		sg.Score = max(sg.Score, score)
+		sg.Reasons = append(sg.Reasons, reason)
+	}
+
+	if r, ok := s.followRename(lower); ok {
+		add(r.To, scoreRenamed, fmt.Sprintf("renamed in git (%s)", r.Commit))
+	}
+	for _, path := range s.aliases[name] {
+		add(path, scoreAlias, "alias")
+	}
+
+	for _, path := range s.ix.paths {
+		base := strings.ToLower(pathBase(path))
+		if d := levenshtein(name, base); d <= maxDist {
+			similarity := 1 - float64(d)/float64(max(nameLen, len([]rune(base))))
+			add(path, scoreTypo*similarity, fmt.Sprintf("edit distance %d", d))
+			continue
+		}
+		if len(targetTokens) == 0 {
+			continue
+		}
+		tokens := nameTokens(base)
+		shared := 0
+		for t := range targetTokens {
+			if tokens[t] {
+				shared++
+			}
+		}
+		if jaccard := float64(shared) / float64(len(unionSets(targetTokens, tokens))); jaccard >= 0.5 {
+			add(path, scoreTokens*jaccard, fmt.Sprintf("%d/%d words match", shared, len(targetTokens)))
+		}
+	}
+
+	suggestions := make([]linkSuggestion, 0, len(byPath))
+	for _, sg := range byPath {
+		suggestions = append(suggestions, *sg)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Path < suggestions[j].Path
+	})
+	return applyLimit(suggestions, limit)
+}
+
+// followRename resolves a target through git renames, following chains
+// (a -> b -> c) until it reaches a note that still exists.
+func (s *linkSuggester) followRename(target string) (gitRename, bool) {
+	var found gitRename
+	key := target
+	for hops := 0; hops < 10; hops++ {
+		r, ok := s.renames[key]
+		if !ok {
+			r, ok = s.renames[pathBase(key)]
+		}
+		if !ok {
+			return gitRename{}, false
+		}
+		if found.Commit == "" {
+			found.Commit = r.Commit
+		}
+		if path, exists := s.ix.byPath[strings.ToLower(r.To)]; exists {
+			found.To = path
+			return found, true
+		}
+		key = strings.ToLower(r.To)
+	}
+	return gitRename{}, false
+}
+
+// bestSuggestion returns the top suggestion when it is confident enough to
+// apply without asking.
+func bestSuggestion(suggestions []linkSuggestion) (linkSuggestion, bool) {
+	if len(suggestions) == 0 || suggestions[0].Score < minConfidentScore {
+		return linkSuggestion{}, false
+	}
+	if len(suggestions) > 1 && suggestions[0].Score-suggestions[1].Score < confidentMargin {
+		return linkSuggestion{}, false
+	}
+	return suggestions[0], true
+}
+
+// gitRenames reads markdown renames from the history of the git repository
+// containing absPath, keyed by lowercase old path and old basename (both
+// without .md). The most recent rename of a name wins. Returns an empty map
+// when git or a repository isn't available.
+func gitRenames(absPath string) map[string]gitRename {
+	renames := make(map[string]gitRename)
+	out, err := exec.Command("git", "-C", absPath, "-c", "core.quotePath=false", "log", "--relative", "-M", "--diff-filter=R",
+		"--name-status", "--format=%h").Output()
+	if err != nil {
+		return renames
+	}
+
+	commit := ""
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], "R") {
+			commit = line
+			continue
+		}
+		from, to := fields[1], fields[2]
+		if !strings.HasSuffix(strings.ToLower(from), ".md") || !strings.HasSuffix(strings.ToLower(to), ".md") {
+			continue
+		}
+		from = strings.ToLower(from[:len(from)-3])
+		r := gitRename{To: to[:len(to)-3], Commit: commit}
+		for _, key := range []string{from, pathBase(from)} {
+			if _, seen := renames[key]; !seen {
+				renames[key] = r
+			}
+		}
+	}
+	return renames
+}
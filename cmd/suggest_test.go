@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+)
+
+// TestLinkSuggestions tests ranking of replacement notes for dead link targets
+func TestLinkSuggestions(t *testing.T) {
+	notes := []*noteFile{
+		parseNote("/v", "/v/Project Plan.md", "body"),
+		parseNote("/v", "/v/Project Planning.md", "body"),
+		parseNote("/v", "/v/team/Meeting.md", "---\naliases: [Weekly Sync]\n---\n"),
+		parseNote("/v", "/v/Renamed.md", "body"),
+	}
+	s := newLinkSuggester("/v", notes, false)
+	s.renames["old name"] = gitRename{To: "Intermediate", Commit: "abc1234"}
+	s.renames["intermediate"] = gitRename{To: "Renamed", Commit: "def5678"}
+
+	tests := []struct {
+		name      string
+		target    string
+		wantBest  string
+		confident bool
+	}{
+		{"typo", "Projct Plan", "Project Plan", true},
+		{"word order", "plan project", "Project Plan", true},
+		{"alias", "weekly sync", "team/Meeting", true},
+		{"rename chain", "Old Name", "Renamed", true},
+		{"no match", "zzz", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.suggest(tt.target, 3)
+			best := ""
+			if len(got) > 0 {
+				best = got[0].Path
+			}
+			if best != tt.wantBest {
+				t.Errorf("suggest(%q) best = %q, want %q (%v)", tt.target, best, tt.wantBest, got)
+			}
+			if _, ok := bestSuggestion(got); ok != tt.confident {
+				t.Errorf("bestSuggestion(%q) confident = %v, want %v (%v)", tt.target, ok, tt.confident, got)
+			}
+		})
+	}
+}
+
+// TestLevenshtein tests rune-based edit distance
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+		{"same", "same", 0},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
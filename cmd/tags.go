@@ -381,3 +381,33 @@ func outputAllTags(cmd *cobra.Command, result *TagScanResult) error {
 
 	return nil
 }
+
+// forEachInlineTag calls fn with the byte range of each inline tag name
+// (without the #) in a note body. Code blocks and headings are skipped
+// using the same rules as extractTagsFromFile.
+func forEachInlineTag(body string, fn func(start, end int)) {
+	inCodeBlock := false
+	offset := 0
+	for _, line := range strings.SplitAfter(body, "\n") {
+		lineStart := offset
+		offset += len(line)
+
+		if strings.HasPrefix(line, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock || strings.HasPrefix(line, "    ") {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) > 0 && trimmed[0] == '#' {
+			if len(trimmed) == 1 || trimmed[1] == ' ' || trimmed[1] == '#' {
+				continue
+			}
+		}
+
+		for _, m := range inlineTagRegex.FindAllStringSubmatchIndex(line, -1) {
+			fn(lineStart+m[2], lineStart+m[3])
+		}
+	}
+}
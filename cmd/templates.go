@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadTemplate reads a vault-relative template note. The .md extension is
+// optional.
+func loadTemplate(absPath, relPath string) (string, error) {
+	path := filepath.Join(absPath, relPath)
+	if !strings.HasSuffix(strings.ToLower(path), ".md") {
+		path += ".md"
+	}
+	if !isPathWithinVault(path, absPath) {
+		return "", fmt.Errorf("template path escapes vault boundary: %s", relPath)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read template %s: %w", relPath, err)
+	}
+	return string(data), nil
+}
+
+// templateVars are the values of the variables in a template.
+type templateVars struct {
+	Title      string
+	Date       time.Time // {{date}}; the note's date for periodic notes
+	Now        time.Time // {{time}}
+	DateFormat string    // Moment format of {{date}}; YYYY-MM-DD if empty
+	TimeFormat string    // Moment format of {{time}}; HH:mm if empty
+	Path       string    // Vault-relative path of the note, for Templater
+	Vars       map[string]string
+}
+
+// Matches {{title}}, {{date}}, {{time:HH:mm}}, {{date+1d:YYYY-MM-DD}},
+// {{yesterday}} and {{monday:YYYY-MM-DD}}
+var templateVarRegex = regexp.MustCompile(`\{\{\s*(\w+)\s*(?:([+-]\d+)([dwMmyY]))?\s*(?::(.*?))?\s*\}\}`)
+
+// renderTemplate fills in the variables of Obsidian's Templates and
+// Periodic Notes plugins: {{title}}, {{date}} and {{time}} with an
+// optional moment format ({{date:dddd}}) and offset ({{date-1d}}),
+// {{yesterday}}, {{tomorrow}}, and weekday names for the weekday in the
+// week of the date. Custom variables ({{project}}) come first. Other
+// variables are kept.
+func renderTemplate(text string, vars templateVars) string {
+	dateFormat, timeFormat := vars.DateFormat, vars.TimeFormat
+	if dateFormat == "" {
+		dateFormat = "YYYY-MM-DD"
+	}
+	if timeFormat == "" {
+		timeFormat = "HH:mm"
+	}
+	return templateVarRegex.ReplaceAllStringFunc(text, func(m string) string {
+		sm := templateVarRegex.FindStringSubmatch(m)
+		name, format := strings.ToLower(sm[1]), sm[4]
+		if value, ok := vars.Vars[sm[1]]; ok && sm[2] == "" && format == "" {
+			return value
+		}
+		var t time.Time
+		switch name {
+		case "title":
+			if sm[2] != "" || format != "" {
+				return m
+			}
+			return vars.Title
+		case "date":
+			t = vars.Date
+		case "time":
+			t = vars.Now
+			if format == "" {
+				format = timeFormat
+			}
+		case "yesterday":
+			t = vars.Date.AddDate(0, 0, -1)
+		case "tomorrow":
+			t = vars.Date.AddDate(0, 0, 1)
+		default:
+			weekday, ok := parseWeekday(name)
+			if !ok {
+				return m
+			}
+			t = vars.Date.AddDate(0, 0, int(weekday)-int(vars.Date.Weekday()))
+		}
+		if sm[2] != "" {
+			n, _ := strconv.Atoi(sm[2])
+			switch sm[3] {
+			case "d":
+				t = t.AddDate(0, 0, n)
+			case "w":
+				t = t.AddDate(0, 0, 7*n)
+			case "M":
+				t = t.AddDate(0, n, 0)
+			case "y", "Y":
+				t = t.AddDate(n, 0, 0)
+			case "m":
+				t = t.Add(time.Duration(n) * time.Minute)
+			}
+		}
+		if format == "" {
+			format = dateFormat
+		}
+		return formatMoment(t, format)
+	})
+}
+
+var (
+	// Matches a Templater tag: <% expr %>, <%* code %>, with whitespace
+	// control (<%- -%>, <%_ _%>)
+	templaterTagRegex = regexp.MustCompile(`(?s)<%([-_*]?)(.*?)([-_]?)%>`)
+	// Matches a call or property of Templater's internal modules: tp.date.now("YYYY", 1)
+	templaterCallRegex = regexp.MustCompile(`(?s)^tp\.(\w+)\.(\w+)(?:\((.*)\))?$`)
+	// Matches an argument of a call: a quoted string, number or boolean
+	templaterArgRegex = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)'|(-?\d+)|(true|false)`)
+)
+
+// renderTemplater evaluates the Templater tags of a template that don't
+// need to run code: tp.file.title, folder, path, creation_date and
+// last_modified_date; tp.date.now, tomorrow, yesterday and
+// weekday; and tp.system.prompt, answered from the custom variables.
+// Other tags are kept and counted in unsupported.
+func renderTemplater(text string, vars templateVars, absPath string) (out string, unsupported int) {
+	var b strings.Builder
+	last := 0
+	for _, m := range templaterTagRegex.FindAllStringSubmatchIndex(text, -1) {
+		open, expr, closing := text[m[2]:m[3]], strings.TrimSpace(text[m[4]:m[5]]), text[m[6]:m[7]]
+		value, ok := "", false
+		if open != "*" {
+			value, ok = evalTemplater(expr, vars, absPath)
+		}
+		if !ok {
+			unsupported++
+			b.WriteString(text[last:m[1]])
+			last = m[1]
+			continue
+		}
+		// "-" trims one newline next to the tag, "_" all whitespace
+		before := text[last:m[0]]
+		switch open {
+		case "-":
+			before = strings.TrimSuffix(strings.TrimSuffix(before, "\n"), "\r")
+		case "_":
+			before = strings.TrimRight(before, " \t\r\n")
+		}
+		b.WriteString(before + value)
+		last = m[1]
+		switch after := text[last:]; closing {
+		case "-":
+			last += len(after) - len(strings.TrimPrefix(strings.TrimPrefix(after, "\r"), "\n"))
+		case "_":
+			last += len(after) - len(strings.TrimLeft(after, " \t\r\n"))
+		}
+	}
+	b.WriteString(text[last:])
+	return b.String(), unsupported
+}
+
+// evalTemplater evaluates one Templater expression.
+func evalTemplater(expr string, vars templateVars, absPath string) (string, bool) {
+	m := templaterCallRegex.FindStringSubmatch(expr)
+	if m == nil {
+		return "", false
+	}
+	var args []string
+	for _, a := range templaterArgRegex.FindAllStringSubmatch(m[3], -1) {
+		args = append(args, a[1]+a[2]+a[3]+a[4])
+	}
+	arg := func(i int, def string) string {
+		if i < len(args) && args[i] != "" {
+			return args[i]
+		}
+		return def
+	}
+
+	relPath := strings.TrimSuffix(vars.Path, ".md")
+	switch m[1] + "." + m[2] {
+	case "file.title":
+		return vars.Title, true
+	case "file.folder":
+		dir := path.Dir(relPath)
+		if dir == "." {
+			return "", true
+		}
+		if arg(0, "false") == "true" {
+			return dir, true
+		}
+		return path.Base(dir), true
+	case "file.path":
+		if arg(0, "false") == "true" {
+			return vars.Path, true
+		}
+		return filepath.Join(absPath, filepath.FromSlash(vars.Path)), true
+	case "file.creation_date", "file.last_modified_date":
+		return formatMoment(vars.Now, arg(0, "YYYY-MM-DD HH:mm")), true
+	case "date.now":
+		offset, _ := strconv.Atoi(arg(1, "0"))
+		return formatMoment(vars.Now.AddDate(0, 0, offset), arg(0, "YYYY-MM-DD")), true
+	case "date.tomorrow":
+		return formatMoment(vars.Now.AddDate(0, 0, 1), arg(0, "YYYY-MM-DD")), true
+	case "date.yesterday":
+		return formatMoment(vars.Now.AddDate(0, 0, -1), arg(0, "YYYY-MM-DD")), true
+	case "date.weekday":
+		weekday, _ := strconv.Atoi(arg(1, "0"))
+		return formatMoment(vars.Now.AddDate(0, 0, weekday-int(vars.Now.Weekday())), arg(0, "YYYY-MM-DD")), true
+	case "system.prompt":
+		value, ok := vars.Vars[arg(0, "")]
+		return value, ok
+	}
+	return "", false
+}
+
+// parseWeekday parses an English weekday name.
+func parseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), name) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// applyTemplateVars fills in {{title}}, {{date}} (YYYY-MM-DD) and
+// {{time}} (HH:mm) for the current time.
+func applyTemplateVars(text, title string) string {
+	now := time.Now()
+	return renderTemplate(text, templateVars{Title: title, Date: now, Now: now})
+}
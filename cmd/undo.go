@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	undoList   bool
+	undoForce  bool
+	undoFormat string
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [journal-id]",
+	Short: "Undo a journaled vault operation",
+	Long: `Reverts a change made by a journaled command (rename, health --fix, ...).
+
+Every mutating operation records the original content of the files it
+touches in <vault>/.obsidian-cli/journal/. Without an ID, the most recent
+operation that hasn't been undone is reverted.
+
+Files modified after the operation are treated as conflicts and nothing is
+changed unless --force is given.
+
+Examples:
+  obsidian-cli undo --vault ~/Documents/Obsidian --list
+  obsidian-cli undo --vault ~/Documents/Obsidian
+  obsidian-cli undo 20250115-103000-rename-ab12cd34 --vault ~/Documents/Obsidian`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+	undoCmd.Flags().BoolVar(&undoList, "list", false, "List journaled operations")
+	undoCmd.Flags().BoolVar(&undoForce, "force", false, "Undo even if files changed since the operation")
+	undoCmd.Flags().StringVar(&undoFormat, "format", "text", "Output format: text, json")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+
+	journals, err := loadJournals(absPath)
+	if err != nil {
+		return err
+	}
+
+	if undoList {
+		if undoFormat == "json" {
+			if journals == nil {
+				journals = []*journal{}
+			}
+			return encodeJSON(cmd, journals)
+		}
+		printJournalList(journals)
+		return nil
+	}
+
+	var target *journal
+	for _, j := range journals {
+		if len(args) == 1 && j.ID == args[0] {
+			target = j
+			break
+		}
+		if len(args) == 0 && !j.Undone {
+			target = j
+			break
+		}
+	}
+	if target == nil {
+		if len(args) == 1 {
+			return fmt.Errorf("journal not found: %s", args[0])
+		}
+		return fmt.Errorf("nothing to undo")
+	}
+	if target.Undone {
+		return fmt.Errorf("operation %s was already undone", target.ID)
+	}
+
+	if err := target.undo(undoForce); err != nil {
+		return err
+	}
+
+	if undoFormat == "json" {
+		return encodeJSON(cmd, target)
+	}
+	fmt.Printf("\n  %s Undid %s: %s (%d changes reverted)\n\n", colors.Green("✓"), target.Operation, target.Description, len(target.Steps))
+	return nil
+}
+
+func printJournalList(journals []*journal) {
+	fmt.Printf("\n%s Journal %s\n\n", colors.Cyan("=>"), colors.Dim(fmt.Sprintf("(%d operations)", len(journals))))
+	if len(journals) == 0 {
+		fmt.Println("  No journaled operations.")
+		return
+	}
+	for _, j := range journals {
+		status := colors.Green("active")
+		if j.Undone {
+			status = colors.Dim("undone")
+		}
+		fmt.Printf("  %s %s %s\n", colors.Cyan(j.ID), status, colors.Dim(fmt.Sprintf("(%d changes)", len(j.Steps))))
+		fmt.Printf("    %s\n", j.Description)
+	}
+	fmt.Println()
+}
@@ -14,9 +14,12 @@ import (
 )
 
 var (
-	unusedFormat string
-	unusedLimit  int
-	unusedDelete bool
+	unusedFormat  string
+	unusedLimit   int
+	unusedDelete  bool
+	unusedTrash   bool
+	unusedTrashTo string
+	unusedYes     bool
 )
 
 var unusedAssetsCmd = &cobra.Command{
@@ -29,6 +32,11 @@ Detects references via:
   - Link syntax: [[document.pdf]]
   - Markdown images: ![alt](image.png)
 
+--trash moves the unused files to a dated quarantine folder in
+.obsidian-cli/quarantine (or Obsidian's .trash with --trash-to obsidian),
+keeping their relative paths and writing a manifest. Bring them back with
+"assets restore" and empty the quarantine with "assets purge".
+
 Supported asset types:
   Images: .png, .jpg, .jpeg, .gif, .svg, .webp, .bmp, .ico
   Documents: .pdf, .doc, .docx, .xls, .xlsx, .ppt, .pptx
@@ -40,6 +48,8 @@ Examples:
   obsidian-cli unused-assets --vault ~/Documents/Obsidian --limit 20
   obsidian-cli unused-assets --vault ~/Documents/Obsidian --format json
   obsidian-cli unused-assets --vault ~/Documents/Obsidian --format paths
+  obsidian-cli unused-assets --vault ~/Documents/Obsidian --trash
+  obsidian-cli unused-assets --vault ~/Documents/Obsidian --trash --trash-to obsidian --yes
   obsidian-cli unused-assets --vault ~/Documents/Obsidian --delete`,
 	RunE: runUnusedAssets,
 }
@@ -49,6 +59,9 @@ func init() {
 	unusedAssetsCmd.Flags().StringVar(&unusedFormat, "format", "text", "Output format: text, json, paths")
 	unusedAssetsCmd.Flags().IntVarP(&unusedLimit, "limit", "n", 0, "Limit number of results (0 = no limit)")
 	unusedAssetsCmd.Flags().BoolVar(&unusedDelete, "delete", false, "Delete unused assets after confirmation")
+	unusedAssetsCmd.Flags().BoolVar(&unusedTrash, "trash", false, "Move unused assets to the trash after confirmation")
+	unusedAssetsCmd.Flags().StringVar(&unusedTrashTo, "trash-to", trashQuarantine, "Trash location: quarantine, obsidian (.trash)")
+	unusedAssetsCmd.Flags().BoolVarP(&unusedYes, "yes", "y", false, "Don't ask for confirmation")
 }
 
 // AssetInfo represents an unused asset file.
@@ -61,11 +74,13 @@ type AssetInfo struct {
 
 // UnusedAssetsResult holds the scan results.
 type UnusedAssetsResult struct {
-	TotalAssets    int           `json:"total_assets"`
-	UnusedAssets   []AssetInfo   `json:"unused_assets"`
-	TotalSize      int64         `json:"total_size"`
-	TotalSizeHuman string        `json:"total_size_human"`
-	Elapsed        time.Duration `json:"-"`
+	TotalAssets    int            `json:"total_assets"`
+	UnusedAssets   []AssetInfo    `json:"unused_assets"`
+	TotalSize      int64          `json:"total_size"`
+	TotalSizeHuman string         `json:"total_size_human"`
+	Trash          *TrashBatch    `json:"trash,omitempty"`
+	TrashFailures  []TrashFailure `json:"trash_failures,omitempty"`
+	Elapsed        time.Duration  `json:"-"`
 }
 
 var (
@@ -96,6 +111,20 @@ func runUnusedAssets(cmd *cobra.Command, args []string) error {
 	if err := RequireVault(); err != nil {
 		return err
 	}
+	if unusedTrash {
+		if unusedDelete {
+			return fmt.Errorf("--trash and --delete can't be combined")
+		}
+		if unusedTrashTo != trashQuarantine && unusedTrashTo != trashObsidian {
+			return fmt.Errorf("invalid --trash-to %q (use quarantine or obsidian)", unusedTrashTo)
+		}
+		if unusedFormat == "paths" {
+			return fmt.Errorf("--trash doesn't support --format paths")
+		}
+		if unusedFormat == "json" && !unusedYes {
+			return fmt.Errorf("--trash with --format json needs --yes")
+		}
+	}
 
 	if unusedFormat == "text" {
 		printScanHeader("Scanning for unused assets")
@@ -258,6 +287,15 @@ func outputUnusedAssets(cmd *cobra.Command, result *UnusedAssetsResult) error {
 
 	switch unusedFormat {
 	case "json":
+		if unusedTrash && total > 0 {
+			absPath, err := filepath.Abs(vaultPath)
+			if err != nil {
+				return fmt.Errorf("invalid vault path: %w", err)
+			}
+			if result.Trash, result.TrashFailures, err = trashFiles(absPath, assetPaths(result.UnusedAssets), unusedTrashTo, "unused assets"); err != nil {
+				return err
+			}
+		}
 		return encodeJSON(cmd, result)
 
 	case "paths":
@@ -306,6 +344,12 @@ func outputUnusedAssets(cmd *cobra.Command, result *UnusedAssetsResult) error {
 				return err
 			}
 		}
+		if unusedTrash && len(result.UnusedAssets) > 0 {
+			fmt.Println()
+			if err := confirmAndTrashAssets(result.UnusedAssets); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -318,26 +362,18 @@ func confirmAndDeleteAssets(assets []AssetInfo) error {
 		totalSize += a.Size
 	}
 
-	// Prompt for confirmation
-	fmt.Printf("  %s Delete %d files (%s)? [y/N]: ",
-		colors.Yellow("?"),
-		len(assets),
-		humanizeBytes(totalSize))
-
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
-	if err != nil {
-		return fmt.Errorf("failed to read input: %w", err)
-	}
-
-	response = strings.TrimSpace(strings.ToLower(response))
-	if response != "y" && response != "yes" {
-		fmt.Printf("\n  %s Cancelled. No files deleted.\n", colors.Yellow("!"))
-		return nil
+	if !unusedYes {
+		ok, err := confirmPrompt(fmt.Sprintf("Delete %d files (%s)?", len(assets), humanizeBytes(totalSize)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("\n  %s Cancelled. No files deleted.\n", colors.Yellow("!"))
+			return nil
+		}
+		fmt.Println()
 	}
 
-	fmt.Println()
-
 	// Delete files
 	absPath, err := filepath.Abs(vaultPath)
 	if err != nil {
@@ -393,3 +429,70 @@ func confirmAndDeleteAssets(assets []AssetInfo) error {
 
 	return nil
 }
+
+// confirmAndTrashAssets prompts for confirmation and moves unused assets to
+// the trash.
+func confirmAndTrashAssets(assets []AssetInfo) error {
+	var totalSize int64
+	for _, a := range assets {
+		totalSize += a.Size
+	}
+	if !unusedYes {
+		ok, err := confirmPrompt(fmt.Sprintf("Move %d files (%s) to the trash?", len(assets), humanizeBytes(totalSize)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("\n  %s Cancelled. No files moved.\n", colors.Yellow("!"))
+			return nil
+		}
+		fmt.Println()
+	}
+
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+	batch, failures, err := trashFiles(absPath, assetPaths(assets), unusedTrashTo, "unused assets")
+	if err != nil {
+		return err
+	}
+	for _, f := range failures {
+		fmt.Printf("  %s Skipped: %s (%s)\n", colors.Red("✗"), f.Path, f.Error)
+	}
+
+	var movedSize int64
+	for _, f := range batch.Files {
+		movedSize += f.Size
+	}
+	where := filepath.Join(batch.dir(), "files")
+	if batch.Location == trashObsidian {
+		where = obsidianTrashDir
+	}
+	fmt.Printf("  %s Moved %d files (%s) to %s\n", colors.Green("✓"), len(batch.Files), humanizeBytes(movedSize), where)
+	if len(batch.Files) > 0 {
+		fmt.Printf("  %s Batch: %s (restore with: obsidian-cli assets restore)\n", colors.Dim("i"), batch.ID)
+	}
+	return nil
+}
+
+// assetPaths returns the paths of assets.
+func assetPaths(assets []AssetInfo) []string {
+	paths := make([]string, len(assets))
+	for i, a := range assets {
+		paths[i] = a.Path
+	}
+	return paths
+}
+
+// confirmPrompt asks a yes/no question on stdin; anything but y or yes
+// is a no.
+func confirmPrompt(question string) (bool, error) {
+	fmt.Printf("  %s %s [y/N]: ", colors.Yellow("?"), question)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && response == "" {
+		return false, fmt.Errorf("failed to read input: %w", err)
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
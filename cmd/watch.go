@@ -0,0 +1,507 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kofifort/obsidian-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchFormat   string
+	watchDebounce time.Duration
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch the vault and stream link, tag and orphan changes",
+	Long: `Watches the vault for file changes and reports how each change affects
+vault health, without rescanning the whole vault.
+
+The vault is indexed once at startup and kept in memory. As notes are
+created, edited, deleted or moved (by Obsidian, an editor, or a git
+checkout), only the affected files are re-read and events are streamed:
+
+  note created / changed / deleted / renamed
+  dead link added / resolved     (new dead link in X:42 -> [[Y]])
+  orphan added / resolved        (note Y became orphan)
+  tag added / removed
+
+Moves are detected by matching deleted and created notes with identical
+content, so a renamed note or folder is reported as a rename.
+
+Use --format ndjson for one JSON event per line.
+
+Examples:
+  obsidian-cli watch --vault ~/Documents/Obsidian
+  obsidian-cli watch --vault ~/Documents/Obsidian --format ndjson | jq .`,
+	RunE: runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().StringVar(&watchFormat, "format", "text", "Output format: text, ndjson")
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", 200*time.Millisecond, "Wait for changes to settle before reporting")
+}
+
+// Watch event types
+const (
+	eventReady           = "ready"
+	eventNoteCreated     = "note_created"
+	eventNoteChanged     = "note_changed"
+	eventNoteDeleted     = "note_deleted"
+	eventNoteRenamed     = "note_renamed"
+	eventDeadLinkAdded   = "dead_link_added"
+	eventDeadLinkRemoved = "dead_link_resolved"
+	eventOrphanAdded     = "orphan_added"
+	eventOrphanRemoved   = "orphan_resolved"
+	eventTagAdded        = "tag_added"
+	eventTagRemoved      = "tag_removed"
+)
+
+// WatchEvent is a change reported by watch.
+type WatchEvent struct {
+	Time    string `json:"time"`
+	Type    string `json:"type"`
+	File    string `json:"file,omitempty"`
+	From    string `json:"from,omitempty"` // Previous path for renames
+	Line    int    `json:"line,omitempty"`
+	Target  string `json:"target,omitempty"` // Link target or tag
+	Message string `json:"message"`
+
+	// Totals, set on the ready event
+	Notes     *int `json:"notes,omitempty"`
+	DeadLinks *int `json:"dead_links,omitempty"`
+	Orphans   *int `json:"orphans,omitempty"`
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if err := RequireVault(); err != nil {
+		return err
+	}
+	if watchFormat != "text" && watchFormat != "ndjson" {
+		return fmt.Errorf("invalid format %q (valid: text, ndjson)", watchFormat)
+	}
+
+	absPath, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+
+	ix, err := newVaultIndex(absPath)
+	if err != nil {
+		return err
+	}
+
+	emit := newWatchEmitter(cmd.OutOrStdout(), watchFormat)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return watchVault(ctx, ix, func() {
+		notes, dead, orphans := ix.counts()
+		emit(WatchEvent{
+			Type:      eventReady,
+			Message:   fmt.Sprintf("watching %s (%d notes, %d dead links, %d orphans)", absPath, notes, dead, orphans),
+			Notes:     &notes,
+			DeadLinks: &dead,
+			Orphans:   &orphans,
+		})
+	}, func(events []WatchEvent) {
+		for _, ev := range events {
+			emit(ev)
+		}
+	})
+}
+
+// watchVault keeps ix up to date with changes on disk until ctx is done.
+// ready is called once the watches are in place; onEvents receives the
+// events of each settled batch of changes.
+func watchVault(ctx context.Context, ix *vaultIndex, ready func(), onEvents func([]WatchEvent)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err := watchTree(watcher, ix.absPath, ix.absPath); err != nil {
+		return err
+	}
+	if ready != nil {
+		ready()
+	}
+
+	pending := make(map[string]bool)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if isHiddenPath(ix.absPath, ev.Name) {
+				continue
+			}
+			pending[ev.Name] = true
+			timer.Reset(watchDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "watch error: %v\n", err)
+
+		case <-timer.C:
+			paths := sortedKeys(pending)
+			pending = make(map[string]bool)
+			if events := applyWatchBatch(ix, watcher, paths); onEvents != nil {
+				onEvents(events)
+			}
+		}
+	}
+}
+
+// watchTree adds a watch for dir and every non-hidden directory below it.
+func watchTree(watcher *fsnotify.Watcher, absPath, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skip, skipDir := shouldSkipEntry(path, d, absPath); skip {
+			if skipDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if err := watcher.Add(path); err != nil {
+				return fmt.Errorf("failed to watch %s: %w", path, err)
+			}
+		}
+		return nil
+	})
+}
+
+// isHiddenPath reports whether path is inside a hidden file or directory
+// (.obsidian, .git, .trash, ...), which scans ignore.
+func isHiddenPath(absPath, path string) bool {
+	rel, err := filepath.Rel(absPath, path)
+	if err != nil {
+		return true
+	}
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if strings.HasPrefix(part, ".") && part != "." {
+			return true
+		}
+	}
+	return false
+}
+
+// watchChange is the index update for one changed path, read from disk
+// before it is applied.
+type watchChange struct {
+	rel     string
+	removed bool           // Deleted or moved away, with everything under it
+	tree    bool           // New or moved-in folder
+	notes   []*indexedNote // Notes of a folder, or the note (nil if unreadable)
+	folders []string       // Folders of a folder
+}
+
+// applyWatchBatch updates the index for a settled batch of changed paths
+// and returns the resulting events. Dead links and orphans are compared
+// only for the notes the batch can affect: the changed notes, the notes
+// linking to their names and the notes their links name.
+func applyWatchBatch(ix *vaultIndex, watcher *fsnotify.Watcher, paths []string) []WatchEvent {
+	var changes []watchChange
+	for _, path := range paths {
+		rel := mustRelPath(ix.absPath, path)
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			changes = append(changes, watchChange{rel: rel, removed: true})
+		case info.IsDir():
+			_ = watchTree(watcher, ix.absPath, path)
+			notes, folders, _ := ix.readTree(rel)
+			changes = append(changes, watchChange{rel: rel, tree: true, notes: notes, folders: folders})
+		case strings.HasSuffix(strings.ToLower(path), ".md"):
+			note, _ := readIndexedNote(ix.absPath, rel)
+			changes = append(changes, watchChange{rel: rel, notes: []*indexedNote{note}})
+		}
+	}
+
+	deadScope, orphanScope := watchScope(ix, changes)
+	beforeDead := deadLinkSet(ix.deadLinksIn(deadScope))
+	beforeOrphans := stringSet(ix.orphansIn(orphanScope))
+
+	removed := make(map[string]*indexedNote)
+	created := make(map[string]*indexedNote)
+	var changed [][2]*indexedNote
+
+	for _, c := range changes {
+		switch {
+		case c.removed:
+			for _, note := range ix.remove(c.rel) {
+				removed[note.RelPath] = note
+			}
+		case c.tree:
+			known := ix.notesUnder(c.rel)
+			ix.putTree(c.notes, c.folders)
+			for _, note := range c.notes {
+				if !known[note.RelPath] {
+					created[note.RelPath] = note
+				}
+			}
+		default:
+			after := c.notes[0]
+			before := ix.set(c.rel, after)
+			switch {
+			case before == nil && after != nil:
+				created[c.rel] = after
+			case before != nil && after == nil:
+				removed[c.rel] = before
+			case before != nil && after != nil && before.Hash != after.Hash:
+				changed = append(changed, [2]*indexedNote{before, after})
+			}
+		}
+	}
+
+	// Pair deletions with creations of identical content as renames
+	renamedFrom := make(map[string]string) // new path -> old path
+	byHash := make(map[string][]string)
+	for _, rel := range sortedKeys(removed) {
+		byHash[removed[rel].Hash] = append(byHash[removed[rel].Hash], rel)
+	}
+	for _, rel := range sortedKeys(created) {
+		if olds := byHash[created[rel].Hash]; len(olds) > 0 {
+			renamedFrom[rel] = olds[0]
+			byHash[created[rel].Hash] = olds[1:]
+		}
+	}
+	renamedTo := make(map[string]string)
+	for to, from := range renamedFrom {
+		renamedTo[from] = to
+	}
+
+	var events []WatchEvent
+	for _, rel := range sortedKeys(created) {
+		if from, ok := renamedFrom[rel]; ok {
+			events = append(events, WatchEvent{Type: eventNoteRenamed, File: rel, From: from,
+				Message: fmt.Sprintf("note %s renamed to %s", from, rel)})
+			continue
+		}
+		events = append(events, WatchEvent{Type: eventNoteCreated, File: rel, Message: "note created: " + rel})
+	}
+	for _, pair := range changed {
+		events = append(events, WatchEvent{Type: eventNoteChanged, File: pair[1].RelPath, Message: "note changed: " + pair[1].RelPath})
+	}
+	for _, rel := range sortedKeys(removed) {
+		if _, ok := renamedTo[rel]; !ok {
+			events = append(events, WatchEvent{Type: eventNoteDeleted, File: rel, Message: "note deleted: " + rel})
+		}
+	}
+
+	// Renamed notes carry their dead links and orphan status along; compare
+	// against the old state as if they had always lived at the new path
+	movedPath := func(rel string) string {
+		if to, ok := renamedTo[rel]; ok {
+			return to
+		}
+		return rel
+	}
+
+	afterDead := deadLinkSet(ix.deadLinksIn(deadScope))
+	for _, key := range sortedKeys(afterDead) {
+		dl := afterDead[key]
+		if _, ok := beforeDead[key]; ok {
+			continue
+		}
+		if from, ok := renamedFrom[dl.SourceFile]; ok {
+			if _, ok := beforeDead[deadLinkKey(from, dl.Target)]; ok {
+				continue
+			}
+		}
+		events = append(events, WatchEvent{Type: eventDeadLinkAdded, File: dl.SourceFile, Line: dl.Line, Target: dl.Target,
+			Message: fmt.Sprintf("new dead link in %s:%d -> [[%s]]", dl.SourceFile, dl.Line, dl.Target)})
+	}
+	for _, key := range sortedKeys(beforeDead) {
+		dl := beforeDead[key]
+		if _, ok := afterDead[deadLinkKey(movedPath(dl.SourceFile), dl.Target)]; ok {
+			continue
+		}
+		if _, gone := removed[dl.SourceFile]; gone && renamedTo[dl.SourceFile] == "" {
+			continue // Source deleted; reported as note_deleted
+		}
+		events = append(events, WatchEvent{Type: eventDeadLinkRemoved, File: dl.SourceFile, Target: dl.Target,
+			Message: fmt.Sprintf("dead link resolved in %s -> [[%s]]", dl.SourceFile, dl.Target)})
+	}
+
+	afterOrphans := stringSet(ix.orphansIn(orphanScope))
+	movedBefore := make(map[string]bool, len(beforeOrphans))
+	for rel := range beforeOrphans {
+		movedBefore[movedPath(rel)] = true
+	}
+	for _, rel := range sortedKeys(afterOrphans) {
+		if !movedBefore[rel] {
+			events = append(events, WatchEvent{Type: eventOrphanAdded, File: rel, Message: fmt.Sprintf("note %s became orphan", rel)})
+		}
+	}
+	for _, rel := range sortedKeys(movedBefore) {
+		if _, gone := removed[rel]; gone && renamedTo[rel] == "" {
+			continue
+		}
+		if !afterOrphans[rel] {
+			events = append(events, WatchEvent{Type: eventOrphanRemoved, File: rel, Message: fmt.Sprintf("note %s is no longer orphan", rel)})
+		}
+	}
+
+	// Tag changes on created, renamed and edited notes
+	tagPairs := make([][2]*indexedNote, 0, len(changed)+len(created))
+	tagPairs = append(tagPairs, changed...)
+	for _, rel := range sortedKeys(created) {
+		var before *indexedNote
+		if from, ok := renamedFrom[rel]; ok {
+			before = removed[from]
+		}
+		tagPairs = append(tagPairs, [2]*indexedNote{before, created[rel]})
+	}
+	for _, pair := range tagPairs {
+		var oldTags []string
+		if pair[0] != nil {
+			oldTags = pair[0].Tags
+		}
+		added, dropped := diffStrings(oldTags, pair[1].Tags)
+		for _, tag := range added {
+			events = append(events, WatchEvent{Type: eventTagAdded, File: pair[1].RelPath, Target: tag,
+				Message: fmt.Sprintf("tag #%s added to %s", tag, pair[1].RelPath)})
+		}
+		for _, tag := range dropped {
+			events = append(events, WatchEvent{Type: eventTagRemoved, File: pair[1].RelPath, Target: tag,
+				Message: fmt.Sprintf("tag #%s removed from %s", tag, pair[1].RelPath)})
+		}
+	}
+	return events
+}
+
+func deadLinkKey(source, target string) string {
+	return source + "\x00" + strings.ToLower(target)
+}
+
+// watchScope returns the notes whose dead links and orphan status changes
+// can affect, before they are applied. A note's dead links change with its
+// content or with the notes and folders its links name; its orphan status
+// with the notes linking to it. Unchanged notes link the same way before
+// and after, so looking them up in the current index is enough.
+func watchScope(ix *vaultIndex, changes []watchChange) (dead, orphans map[string]bool) {
+	touched := make(map[string]bool)
+	var keys, targets []string
+	touch := func(note *indexedNote) {
+		touched[note.RelPath] = true
+		keys = append(keys, linkKeys(note.RelPath)...)
+		for _, l := range note.Links {
+			targets = append(targets, strings.ToLower(l.Target))
+		}
+	}
+	for _, c := range changes {
+		var notes []*indexedNote
+		if note := ix.note(c.rel); note != nil {
+			notes = append(notes, note)
+		}
+		if c.removed || c.tree {
+			for rel := range ix.notesUnder(c.rel) {
+				notes = append(notes, ix.note(rel))
+			}
+			keys = append(keys, ix.foldersUnder(c.rel)...)
+		}
+		for _, f := range c.folders {
+			keys = append(keys, strings.ToLower(f), strings.ToLower(f+"/"))
+		}
+		for _, note := range append(notes, c.notes...) {
+			if note != nil {
+				touch(note)
+			}
+		}
+	}
+
+	dead, orphans = ix.linking(keys), ix.linkedBy(targets)
+	for rel := range touched {
+		dead[rel] = true
+		orphans[rel] = true
+	}
+	return dead, orphans
+}
+
+// deadLinkSet returns dead links keyed by source and target, so line shifts
+// from unrelated edits don't produce events.
+func deadLinkSet(links []vault.DeadLink) map[string]vault.DeadLink {
+	set := make(map[string]vault.DeadLink)
+	for _, dl := range links {
+		key := deadLinkKey(dl.SourceFile, dl.Target)
+		if _, ok := set[key]; !ok {
+			set[key] = dl
+		}
+	}
+	return set
+}
+
+// diffStrings returns the items only in b (added) and only in a (removed).
+func diffStrings(a, b []string) (added, removed []string) {
+	inA, inB := stringSet(a), stringSet(b)
+	for _, s := range b {
+		if !inA[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range a {
+		if !inB[s] {
+			removed = append(removed, s)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// newWatchEmitter returns a function that writes events as text or NDJSON.
+func newWatchEmitter(w io.Writer, format string) func(WatchEvent) {
+	enc := json.NewEncoder(w)
+	symbols := map[string]string{
+		eventReady:           colors.Cyan("=>"),
+		eventNoteCreated:     colors.Green("+"),
+		eventNoteChanged:     colors.Dim("~"),
+		eventNoteDeleted:     colors.Red("-"),
+		eventNoteRenamed:     colors.Cyan("→"),
+		eventDeadLinkAdded:   colors.Red("✗"),
+		eventDeadLinkRemoved: colors.Green("✓"),
+		eventOrphanAdded:     colors.Yellow("!"),
+		eventOrphanRemoved:   colors.Green("✓"),
+		eventTagAdded:        colors.Dim("#"),
+		eventTagRemoved:      colors.Dim("#"),
+	}
+	return func(ev WatchEvent) {
+		now := time.Now()
+		ev.Time = now.Format(time.RFC3339)
+		if format == "ndjson" {
+			_ = enc.Encode(ev)
+			return
+		}
+		if ev.Type == eventReady {
+			fmt.Fprintf(w, "\n%s %s\n\n", symbols[ev.Type], ev.Message)
+			return
+		}
+		fmt.Fprintf(w, "  %s %s %s\n", colors.Dim(now.Format("15:04:05")), symbols[ev.Type], ev.Message)
+	}
+}
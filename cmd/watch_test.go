@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// TestApplyWatchBatch tests the events of successive batches, including
+// dead links and orphans of notes outside the batch
+func TestApplyWatchBatch(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"a.md": "[[b]]\n", "b.md": "text\n", "c.md": "[[missing]]\n"}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ix, err := newVaultIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	write := func(rel, content string) func() error {
+		return func() error { return os.WriteFile(filepath.Join(dir, rel), []byte(content), 0644) }
+	}
+	steps := []struct {
+		name   string
+		change func() error
+		paths  []string
+		want   []string
+	}{
+		{"create linked note", write("missing.md", "x\n"), []string{"missing.md"},
+			[]string{"note_created missing.md", "dead_link_resolved c.md missing"}},
+		{"delete linked note", func() error { return os.Remove(filepath.Join(dir, "b.md")) }, []string{"b.md"},
+			[]string{"note_deleted b.md", "dead_link_added a.md b"}},
+		{"move into folder", func() error {
+			if err := os.Mkdir(filepath.Join(dir, "d"), 0755); err != nil {
+				return err
+			}
+			return os.Rename(filepath.Join(dir, "c.md"), filepath.Join(dir, "d", "c.md"))
+		}, []string{"c.md", "d"},
+			[]string{"note_renamed d/c.md"}},
+		{"edit links", write("a.md", "[[c]] #tag\n"), []string{"a.md"},
+			[]string{"note_changed a.md", "dead_link_resolved a.md b", "orphan_resolved d/c.md", "tag_added a.md tag"}},
+		{"delete folder", func() error { return os.RemoveAll(filepath.Join(dir, "d")) }, []string{"d"},
+			[]string{"note_deleted d/c.md", "dead_link_added a.md c", "orphan_added missing.md"}},
+	}
+
+	for _, step := range steps {
+		if err := step.change(); err != nil {
+			t.Fatal(err)
+		}
+		var paths []string
+		for _, p := range step.paths {
+			paths = append(paths, filepath.Join(dir, filepath.FromSlash(p)))
+		}
+		var got []string
+		for _, ev := range applyWatchBatch(ix, watcher, paths) {
+			got = append(got, strings.TrimSpace(strings.Join([]string{ev.Type, filepath.ToSlash(ev.File), ev.Target}, " ")))
+		}
+		if strings.Join(got, "\n") != strings.Join(step.want, "\n") {
+			t.Errorf("%s: events = %q, want %q", step.name, got, step.want)
+		}
+	}
+}
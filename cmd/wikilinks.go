@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+// wikilink is a parsed [[target#fragment|alias]] occurrence in a note.
+type wikilink struct {
+	Embed    bool   // Prefixed with ! (![[...]])
+	Target   string // Note or file name/path, without fragment
+	Fragment string // "#Heading", "#^block" or "^block", including the marker
+	Alias    string // Display text or embed size after |, without the pipe
+	HasAlias bool
+	Start    int // Byte offset of the match (including !) in the source text
+	End      int
+}
+
+var (
+	// Matches [[target]], [[target|alias]] and ![[embed]] with capture groups
+	// for the embed marker, link body and alias.
+	wikilinkFullRegex = regexp.MustCompile(`(!?)\[\[([^\[\]|]*)(?:\|([^\[\]]*))?\]\]`)
+	// Matches inline code spans
+	inlineCodeRegex = regexp.MustCompile("`[^`\n]+`")
+)
+
+// String renders the link back to wikilink syntax.
+func (l wikilink) String() string {
+	var b strings.Builder
+	if l.Embed {
+		b.WriteByte('!')
+	}
+	b.WriteString("[[")
+	b.WriteString(l.Target)
+	b.WriteString(l.Fragment)
+	if l.HasAlias {
+		b.WriteByte('|')
+		b.WriteString(l.Alias)
+	}
+	b.WriteString("]]")
+	return b.String()
+}
+
+// splitLinkFragment splits "note#heading" or "note^block" into the target and
+// the fragment (with its marker). Mirrors vault.NormalizeLink.
+func splitLinkFragment(link string) (target, fragment string) {
+	if idx := strings.Index(link, "#"); idx != -1 {
+		return link[:idx], link[idx:]
+	}
+	if idx := strings.Index(link, "^"); idx != -1 {
+		return link[:idx], link[idx:]
+	}
+	return link, ""
+}
+
+// parseWikilinks returns all wikilinks and embeds in text, in order.
+func parseWikilinks(text string) []wikilink {
+	var links []wikilink
+	for _, m := range wikilinkFullRegex.FindAllStringSubmatchIndex(text, -1) {
+		body := text[m[4]:m[5]]
+		target, fragment := splitLinkFragment(body)
+		l := wikilink{
+			Embed:    m[3] > m[2],
+			Target:   target,
+			Fragment: fragment,
+			Start:    m[0],
+			End:      m[1],
+		}
+		if m[6] != -1 {
+			l.Alias = text[m[6]:m[7]]
+			l.HasAlias = true
+		}
+		links = append(links, l)
+	}
+	return links
+}
+
+// rewriteWikilinks calls fn for every wikilink in text and substitutes the
+// returned replacement text when fn reports a change. Returns the new text
+// and the number of links replaced.
+func rewriteWikilinks(text string, fn func(l wikilink) (string, bool)) (string, int) {
+	links := parseWikilinks(text)
+	if len(links) == 0 {
+		return text, 0
+	}
+
+	var b strings.Builder
+	last, count := 0, 0
+	for _, l := range links {
+		replacement, changed := fn(l)
+		if !changed {
+			continue
+		}
+		b.WriteString(text[last:l.Start])
+		b.WriteString(replacement)
+		last = l.End
+		count++
+	}
+	if count == 0 {
+		return text, 0
+	}
+	b.WriteString(text[last:])
+	return b.String(), count
+}
+
+// lineNumberAt returns the 1-based line number of byte offset pos in text.
+func lineNumberAt(text string, pos int) int {
+	return strings.Count(text[:pos], "\n") + 1
+}
+
+// mapOutsideFences replaces each run of text outside fenced code blocks
+// with fn's result. fn receives the run and the line it starts on, counting
+// the first line of text as firstLine. Fences are copied unchanged.
+func mapOutsideFences(text string, firstLine int, fn func(chunk string, line int) string) string {
+	var b strings.Builder
+	inFence := false
+	chunkStart, chunkLine, offset := 0, 0, 0
+	flush := func(end int) {
+		if chunkStart < end {
+			b.WriteString(fn(text[chunkStart:end], firstLine+chunkLine))
+		}
+	}
+	for i, line := range strings.SplitAfter(text, "\n") {
+		lineStart := offset
+		offset += len(line)
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if !inFence {
+				flush(lineStart)
+			}
+			inFence = !inFence
+			b.WriteString(line)
+			chunkStart, chunkLine = offset, i+1
+			continue
+		}
+		if inFence {
+			b.WriteString(line)
+			chunkStart, chunkLine = offset, i+1
+		}
+	}
+	flush(len(text))
+	return b.String()
+}
+
+// mapOutsideInlineCode is mapOutsideFences for `inline code` spans.
+func mapOutsideInlineCode(text string, firstLine int, fn func(piece string, line int) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range inlineCodeRegex.FindAllStringIndex(text, -1) {
+		b.WriteString(fn(text[last:m[0]], firstLine+strings.Count(text[:last], "\n")))
+		b.WriteString(text[m[0]:m[1]])
+		last = m[1]
+	}
+	b.WriteString(fn(text[last:], firstLine+strings.Count(text[:last], "\n")))
+	return b.String()
+}
@@ -62,8 +62,8 @@ func NormalizeLink(link string) string {
 	return link
 }
 
-// isAssetFile checks if a filename is a non-markdown asset (image, PDF, etc.)
-func isAssetFile(target string) bool {
+// IsAssetFile reports whether a filename is a non-markdown asset (image, PDF, etc.)
+func IsAssetFile(target string) bool {
 	ext := strings.ToLower(filepath.Ext(target))
 	assetExts := map[string]bool{
 		".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
@@ -73,8 +73,8 @@ func isAssetFile(target string) bool {
 	return assetExts[ext]
 }
 
-// isExternalLink checks if a link is external (URL or protocol)
-func isExternalLink(target string) bool {
+// IsExternalLink reports whether a link is external (URL or protocol)
+func IsExternalLink(target string) bool {
 	return strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:")
 }
 
@@ -304,7 +304,7 @@ func processFile(path, vaultPath string, existingFiles, existingFolders map[stri
 				}
 
 				// Skip external links (URLs, mailto:, etc.)
-				if isExternalLink(target) {
+				if IsExternalLink(target) {
 					continue
 				}
 
@@ -317,7 +317,7 @@ func processFile(path, vaultPath string, existingFiles, existingFolders map[stri
 				result.mu.Unlock()
 
 				// Skip asset files (images, PDFs) - they're not in existingFiles
-				if isAssetFile(target) {
+				if IsAssetFile(target) {
 					continue
 				}
 